@@ -0,0 +1,286 @@
+// Command listbench seeds a local PostgreSQL with generated subscriptions and reports p50/p99
+// latencies of the main list queries, optionally before and after the list indexes migration
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/odlev/subscriptions/internal/config"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/migrations"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// indexesMigration - миграция с индексами списка, которую -compare откатывает на время замера "before"
+const indexesMigration = "000017_list_indexes"
+
+// benchEmail - email сгенерированных пользователей, по нему -cleanup находит данные бенчмарка
+const benchEmail = "bench-%@bench.invalid"
+
+// pageSize - размер страницы в замерах
+const pageSize = 50
+
+// warmup - прогоны перед замером, которые не учитываются
+const warmup = 5
+
+// scenario - один замеряемый запрос; i - номер прогона для выбора параметров
+type scenario struct {
+	name string
+	run  func(i int) error
+}
+
+func main() {
+	rows := flag.Int("rows", 1_000_000, "number of generated subscriptions")
+	users := flag.Int("users", 10_000, "number of generated users")
+	runs := flag.Int("runs", 200, "measured runs per query")
+	compare := flag.Bool("compare", false, "also measure with migration "+indexesMigration+" rolled back")
+	cleanup := flag.Bool("cleanup", false, "delete generated data and exit")
+	flag.Parse()
+
+	cfg := config.MustLoad()
+	log := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	ctx := context.Background()
+
+	// InitPostgres применяет миграции, в том числе индексы списка
+	db, err := storage.InitPostgres(log, *cfg)
+	if err != nil {
+		log.Error("error initialization database", sl.Err(err))
+		os.Exit(1)
+	}
+	pool, err := pgxpool.New(ctx, cfg.DSN())
+	if err != nil {
+		log.Error("failed to connect", sl.Err(err))
+		os.Exit(1)
+	}
+	defer pool.Close()
+
+	if *cleanup {
+		if err := deleteSeed(ctx, pool); err != nil {
+			log.Error("failed to delete generated data", sl.Err(err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := seed(ctx, log, pool, *users, *rows); err != nil {
+		log.Error("failed to seed", sl.Err(err))
+		os.Exit(1)
+	}
+
+	scenarios, err := newScenarios(ctx, pool, db, *runs)
+	if err != nil {
+		log.Error("failed to prepare queries", sl.Err(err))
+		os.Exit(1)
+	}
+
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "query\tindexes\tp50\tp99")
+
+	if *compare {
+		if err := runMigration(ctx, pool, indexesMigration+".down.sql"); err != nil {
+			log.Error("failed to drop list indexes", sl.Err(err))
+			os.Exit(1)
+		}
+		err := measure(out, scenarios, "before", *runs)
+		// индексы возвращаются и после неудачного замера
+		if upErr := runMigration(ctx, pool, indexesMigration+".up.sql"); upErr != nil {
+			log.Error("failed to restore list indexes, run "+indexesMigration+".up.sql manually", sl.Err(upErr))
+			os.Exit(1)
+		}
+		if err != nil {
+			log.Error("failed to measure", sl.Err(err))
+			os.Exit(1)
+		}
+	}
+
+	if err := measure(out, scenarios, "after", *runs); err != nil {
+		log.Error("failed to measure", sl.Err(err))
+		os.Exit(1)
+	}
+	out.Flush()
+}
+
+// seed дополняет сгенерированные данные до users пользователей и rows подписок.
+// Подписки распределены по пользователям, 500 сервисам и 72 месяцам начала
+func seed(ctx context.Context, log *slog.Logger, pool *pgxpool.Pool, users, rows int) error {
+	_, err := pool.Exec(ctx, `INSERT INTO users (email, display_name)
+	SELECT replace($2, '%', g::TEXT), 'bench user ' || g FROM generate_series(1, $1::INT) AS g
+	ON CONFLICT (email) DO NOTHING`, users, benchEmail)
+	if err != nil {
+		return fmt.Errorf("users: %w", err)
+	}
+
+	var existing int
+	err = pool.QueryRow(ctx, `SELECT count(*) FROM subscriptions
+	WHERE user_id IN (SELECT id FROM users WHERE email LIKE $1)`, benchEmail).Scan(&existing)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+	if existing >= rows {
+		log.Info("generated data already present", slog.Int("subscriptions", existing))
+		return nil
+	}
+
+	log.Info("seeding subscriptions", slog.Int("existing", existing), slog.Int("target", rows))
+	started := time.Now()
+	_, err = pool.Exec(ctx, `WITH u AS (
+		SELECT id, row_number() OVER (ORDER BY id) - 1 AS n FROM users WHERE email LIKE $3
+	), s AS (
+		SELECT g, (DATE '2020-01-01' + make_interval(months => g % 72))::DATE AS start_date
+		FROM generate_series($1::INT + 1, $2::INT) AS g
+	)
+	INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date, auto_renew)
+	SELECT 'Bench service ' || (s.g % 500), 100 + s.g % 1900, u.id,
+		s.start_date, (s.start_date + INTERVAL '12 months')::DATE, s.g % 3 = 0
+	FROM s JOIN u ON u.n = s.g % (SELECT count(*) FROM u)`, existing, rows, benchEmail)
+	if err != nil {
+		return fmt.Errorf("subscriptions: %w", err)
+	}
+
+	if _, err := pool.Exec(ctx, `ANALYZE subscriptions`); err != nil {
+		return fmt.Errorf("analyze: %w", err)
+	}
+	log.Info("seeded", slog.Duration("took", time.Since(started)))
+
+	return nil
+}
+
+// deleteSeed удаляет сгенерированные подписки и пользователей
+func deleteSeed(ctx context.Context, pool *pgxpool.Pool) error {
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `DELETE FROM subscriptions
+		WHERE user_id IN (SELECT id FROM users WHERE email LIKE $1)`, benchEmail)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `DELETE FROM users WHERE email LIKE $1`, benchEmail)
+
+		return err
+	})
+}
+
+// runMigration выполняет файл миграции и обновляет статистику планировщика
+func runMigration(ctx context.Context, pool *pgxpool.Pool, name string) error {
+	raw, err := fs.ReadFile(migrations.FS, name)
+	if err != nil {
+		return err
+	}
+	if _, err := pool.Exec(ctx, string(raw)); err != nil {
+		return err
+	}
+	_, err = pool.Exec(ctx, `ANALYZE subscriptions`)
+
+	return err
+}
+
+// newScenarios выбирает случайные параметры для каждого прогона и возвращает замеряемые запросы
+func newScenarios(ctx context.Context, pool *pgxpool.Pool, db *storage.Storage, runs int) ([]scenario, error) {
+	n := runs + warmup
+
+	userRows, err := pool.Query(ctx, `SELECT id FROM users WHERE email LIKE $1 ORDER BY random() LIMIT $2`, benchEmail, n)
+	if err != nil {
+		return nil, err
+	}
+	userIDs, err := pgx.CollectRows(userRows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, err
+	}
+	if len(userIDs) == 0 {
+		return nil, fmt.Errorf("no generated users")
+	}
+
+	// курсоры из случайных мест списка: глубокие страницы, до которых OFFSET шел бы через все строки перед ними
+	cursorRows, err := pool.Query(ctx, `SELECT id, start_date FROM subscriptions TABLESAMPLE SYSTEM (1) LIMIT $1`, n)
+	if err != nil {
+		return nil, err
+	}
+	cursors, err := pgx.CollectRows(cursorRows, func(row pgx.CollectableRow) (string, error) {
+		var sub storage.Subscription
+		if err := row.Scan(&sub.ID, &sub.StartDate); err != nil {
+			return "", err
+		}
+		return storage.EncodeCursor(sub), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(cursors) == 0 {
+		return nil, fmt.Errorf("no subscriptions to page through")
+	}
+
+	limit := strconv.Itoa(pageSize)
+	user := func(i int) string { return userIDs[i%len(userIDs)].String() }
+	service := func(i int) string { return fmt.Sprintf("bench service %d", i*37%500) }
+	list := func(filter storage.SubscriptionFilter) error {
+		_, err := db.GetListSubscriptions(filter)
+		return err
+	}
+
+	return []scenario{
+		{"list by user", func(i int) error {
+			return list(storage.SubscriptionFilter{UserID: user(i)})
+		}},
+		{"list by user and service", func(i int) error {
+			return list(storage.SubscriptionFilter{UserID: user(i), ServiceNames: []string{service(i)}})
+		}},
+		{"list by service, first page", func(i int) error {
+			return list(storage.SubscriptionFilter{ServiceNames: []string{service(i)}, Limit: limit})
+		}},
+		{"list, first page", func(i int) error {
+			return list(storage.SubscriptionFilter{Limit: limit})
+		}},
+		{"list, page after cursor", func(i int) error {
+			return list(storage.SubscriptionFilter{Limit: limit, After: cursors[i%len(cursors)]})
+		}},
+		{"total by user", func(i int) error {
+			_, err := db.GetTotalCost(storage.SubscriptionFilter{UserID: user(i)}, "2024-01", "2024-12", "")
+			return err
+		}},
+	}, nil
+}
+
+// measure прогоняет каждый запрос warmup+runs раз и пишет p50 и p99 замеренных прогонов
+func measure(out *tabwriter.Writer, scenarios []scenario, label string, runs int) error {
+	for _, sc := range scenarios {
+		took := make([]time.Duration, 0, runs)
+		for i := range warmup + runs {
+			started := time.Now()
+			if err := sc.run(i); err != nil {
+				return fmt.Errorf("%s: %w", sc.name, err)
+			}
+			if i >= warmup {
+				took = append(took, time.Since(started))
+			}
+		}
+
+		slices.Sort(took)
+		fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", sc.name, label, percentile(took, 50), percentile(took, 99))
+	}
+
+	return nil
+}
+
+// percentile - p-й процентиль отсортированных замеров (ближайший ранг)
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1].Round(10 * time.Microsecond)
+}
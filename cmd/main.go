@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/odlev/subscriptions/docs"
 	"github.com/odlev/subscriptions/internal/config"
+	"github.com/odlev/subscriptions/internal/grpcserver"
 	"github.com/odlev/subscriptions/internal/handlers"
+	"github.com/odlev/subscriptions/internal/notifier"
+	"github.com/odlev/subscriptions/internal/reminders"
+	"github.com/odlev/subscriptions/internal/renewals"
+	"github.com/odlev/subscriptions/internal/sweeper"
 	"github.com/odlev/subscriptions/internal/storage"
 	"github.com/odlev/subscriptions/pkg/sl"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -32,15 +40,147 @@ func main() {
 		return
 	}
 
+	db.OnBudgetAlert(func(alert storage.BudgetAlert) {
+		log.Warn("budget threshold crossed",
+			slog.Any("budget id", alert.BudgetID),
+			slog.Any("user id", alert.UserID),
+			slog.String("month", alert.Month),
+			slog.Int("threshold", alert.Threshold),
+			slog.Int("spent", alert.Spent),
+			slog.Int("amount", alert.Amount))
+	})
+
+	// с event_sourced изменения подписок записываются событиями в журнал
+	var subscriptions subscriptionStore = db
+	if cfg.EventSourced {
+		subscriptions = storage.NewEventStore(db)
+		log.Info("event-sourced subscription store enabled")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n, err := notifier.New(log, cfg.Notifier)
+	if err != nil {
+		log.Error("error initialization notifier", sl.Err(err))
+		return
+	}
+	if cfg.RemindersEnabled {
+		go reminders.New(log, db, n, cfg.ReminderWindows, cfg.RemindersInterval).Run(ctx)
+	}
+	if cfg.RenewalsEnabled {
+		go renewals.New(log, subscriptions, n, cfg.RenewalsInterval).Run(ctx)
+	}
+	if cfg.SweeperEnabled {
+		go sweeper.New(log, subscriptions, n, cfg.SweeperInterval).Run(ctx)
+	}
+
 	router := gin.Default()
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	router.POST("/new", handlers.CreateSubscription(log, db))
-	router.GET("/get/:id", handlers.GetSubscription(log, db))
-	router.DELETE("/delete/:id", handlers.DeleteSubscription(log, db))
-	router.PATCH("/update/:id", handlers.UpdateSubscription(log, db))
-	router.GET("/get/list", handlers.GetListSubscriptions(log, db))
+	v1 := router.Group(handlers.SubscriptionsV1Path)
+	{
+		v1.POST("", handlers.CreateSubscriptionV1(log, subscriptions))
+		v1.GET("", handlers.ListSubscriptionsV1(log, subscriptions))
+		v1.GET("/total", handlers.TotalCostV1(log, subscriptions))
+		v1.GET("/timeseries", handlers.TimeSeriesV1(log, db))
+		v1.GET("/forecast", handlers.ForecastV1(log, db))
+		v1.GET("/overlaps", handlers.OverlapsReportV1(log, db))
+		v1.GET("/suggest", handlers.SuggestServiceNamesV1(log, db))
+		v1.GET("/aggregate", handlers.AggregateV1(log, db))
+		v1.GET("/:id", handlers.GetSubscriptionV1(log, subscriptions))
+		v1.PUT("/:id", handlers.ReplaceSubscriptionV1(log, subscriptions))
+		v1.PATCH("/:id", handlers.PatchSubscriptionV1(log, subscriptions))
+		v1.DELETE("/:id", handlers.DeleteSubscriptionV1(log, subscriptions))
+		v1.PUT("/:id/tags", handlers.SetSubscriptionTags(log, subscriptions, subscriptions))
+		v1.POST("/:id/tags", handlers.AddSubscriptionTag(log, subscriptions, subscriptions))
+		v1.DELETE("/:id/tags/:tag", handlers.RemoveSubscriptionTag(log, subscriptions))
+		v1.GET("/:id/renewals", handlers.ListRenewals(log, db))
+		v1.GET("/:id/prices", handlers.ListPrices(log, db))
+		v1.POST("/:id/pause", handlers.PauseSubscription(log, subscriptions))
+		v1.POST("/:id/resume", handlers.ResumeSubscription(log, subscriptions))
+		v1.GET("/:id/pauses", handlers.ListPauses(log, db))
+		v1.POST("/:id/cancel", handlers.CancelSubscription(log, subscriptions))
+		v1.POST("/:id/reactivate", handlers.ReactivateSubscription(log, subscriptions))
+		v1.GET("/:id/members", handlers.ListMembers(log, subscriptions))
+		v1.PUT("/:id/members/:user_id", handlers.SetMember(log, subscriptions))
+		v1.DELETE("/:id/members/:user_id", handlers.RemoveMember(log, subscriptions))
+	}
+
+	users := router.Group(handlers.UsersV1Path)
+	{
+		users.POST("", handlers.CreateUser(log, subscriptions))
+		users.GET("", handlers.ListUsers(log, subscriptions))
+		users.GET("/:id", handlers.GetUser(log, subscriptions))
+		users.PATCH("/:id", handlers.UpdateUser(log, subscriptions))
+		users.DELETE("/:id", handlers.DeleteUser(log, subscriptions))
+	}
+
+	services := router.Group(handlers.ServicesV1Path)
+	{
+		services.POST("", handlers.CreateService(log, subscriptions))
+		services.GET("", handlers.ListServices(log, subscriptions))
+		services.GET("/:id", handlers.GetService(log, subscriptions))
+		services.PATCH("/:id", handlers.UpdateService(log, subscriptions))
+		services.DELETE("/:id", handlers.DeleteService(log, subscriptions))
+	}
+
+	categories := router.Group(handlers.CategoriesV1Path)
+	{
+		categories.POST("", handlers.CreateCategory(log, subscriptions))
+		categories.GET("", handlers.ListCategories(log, subscriptions))
+		categories.GET("/:id", handlers.GetCategory(log, subscriptions))
+		categories.PATCH("/:id", handlers.UpdateCategory(log, subscriptions))
+		categories.DELETE("/:id", handlers.DeleteCategory(log, subscriptions))
+	}
+
+	budgets := router.Group(handlers.BudgetsV1Path)
+	{
+		budgets.POST("", handlers.CreateBudget(log, subscriptions))
+		budgets.GET("", handlers.ListBudgets(log, subscriptions))
+		budgets.GET("/:id", handlers.GetBudget(log, subscriptions))
+		budgets.GET("/:id/status", handlers.GetBudgetStatus(log, subscriptions))
+		budgets.PATCH("/:id", handlers.UpdateBudget(log, subscriptions))
+		budgets.DELETE("/:id", handlers.DeleteBudget(log, subscriptions))
+	}
+
+	tags := router.Group(handlers.TagsV1Path)
+	{
+		tags.POST("", handlers.CreateTag(log, subscriptions))
+		tags.GET("", handlers.ListTags(log, subscriptions))
+		tags.PATCH("/:id", handlers.RenameTag(log, subscriptions))
+		tags.DELETE("/:id", handlers.DeleteTag(log, subscriptions))
+	}
+
+	deprecatedSince, err := time.Parse(time.DateOnly, cfg.LegacyDeprecatedSince)
+	if err != nil {
+		log.Error("invalid legacy_deprecated_since", sl.Err(err))
+		return
+	}
+	sunset, err := time.Parse(time.DateOnly, cfg.LegacySunset)
+	if err != nil {
+		log.Error("invalid legacy_sunset", sl.Err(err))
+		return
+	}
+
+	// старые маршруты оставлены как устаревшие псевдонимы для /api/v1/subscriptions
+	legacy := router.Group("/", handlers.Deprecated(deprecatedSince, sunset, handlers.SubscriptionsV1Path))
+	{
+		legacy.POST("/new", handlers.CreateSubscription(log, subscriptions))
+		legacy.GET("/get/:id", handlers.GetSubscription(log, subscriptions))
+		legacy.DELETE("/delete/:id", handlers.DeleteSubscription(log, subscriptions))
+		legacy.PATCH("/update/:id", handlers.UpdateSubscription(log, subscriptions))
+		legacy.GET("/get/list", handlers.GetListSubscriptions(log, subscriptions))
+	}
+
+	grpcServer, grpcErrs := grpcserver.Run(log, cfg.GRPCAddress, subscriptions)
+	defer grpcServer.Stop()
+	go func() {
+		if err := <-grpcErrs; err != nil {
+			log.Error("gRPC server stopped", sl.Err(err))
+		}
+	}()
 
 	srv := &http.Server{
 		Addr:         cfg.Address,
@@ -56,6 +196,22 @@ func main() {
 
 }
 
+// subscriptionStore - Storage или EventStore; все записи API идут через него. Изменения участников,
+// пользователей, сервисов, категорий и тегов меняют подписки и записываются событиями в их журнал
+type subscriptionStore interface {
+	handlers.DataWizard
+	handlers.TagWizard
+	handlers.ServiceWizard
+	handlers.CategoryWizard
+	handlers.StatusWizard
+	handlers.PauseWizard
+	handlers.MemberWizard
+	handlers.UserWizard
+	handlers.BudgetWizard
+	renewals.RenewalStore
+	sweeper.StatusStore
+}
+
 func newLogger(environment string) *slog.Logger {
 	var log *slog.Logger
 
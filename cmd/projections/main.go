@@ -0,0 +1,47 @@
+// Command projections checks the subscriptions table against the subscription event log and rebuilds
+// the rows that drifted from it
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/odlev/subscriptions/internal/config"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only report rows that differ from the event log")
+	importUntracked := flag.Bool("import", false, "record subscriptions without events as imported")
+	flag.Parse()
+
+	cfg := config.MustLoad()
+	log := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	db, err := storage.InitPostgres(log, *cfg)
+	if err != nil {
+		log.Error("error initialization database", sl.Err(err))
+		os.Exit(1)
+	}
+
+	report, err := storage.NewEventStore(db).RebuildProjections(*dryRun, *importUntracked)
+	if err != nil {
+		log.Error("failed to rebuild projections", sl.Err(err))
+		os.Exit(1)
+	}
+
+	verb := "rebuilt"
+	if *dryRun {
+		verb = "would rebuild"
+	}
+	fmt.Printf("events: %d, subscriptions: %d\n", report.Events, report.Subscriptions)
+	fmt.Printf("%s: %d\n", verb, len(report.Drifted))
+	for _, d := range report.Drifted {
+		fmt.Printf("  %s: %s\n", d.SubscriptionID, strings.Join(d.Fields, ", "))
+	}
+	fmt.Printf("without events: %d, imported: %d\n", report.Untracked, report.Imported)
+}
@@ -0,0 +1,599 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/pkg/client"
+)
+
+type cmdEnv struct {
+	ctx    context.Context
+	api    *client.Client
+	format string
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+type command func(env *cmdEnv, args []string) error
+
+var commands = map[string]command{
+	"create":     cmdCreate,
+	"get":        cmdGet,
+	"list":       cmdList,
+	"update":     cmdUpdate,
+	"delete":     cmdDelete,
+	"cancel":     cmdCancel,
+	"reactivate": cmdReactivate,
+	"import":     cmdImport,
+	"export":     cmdExport,
+	"total":      cmdTotal,
+}
+
+func (env *cmdEnv) flagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.stderr, "Usage: subsctl %s %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parseFlags разбирает флаги команды; ошибки разбора превращаются в usageError
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+
+	return nil
+}
+
+// singleID достает единственный позиционный аргумент - ID подписки
+func singleID(fs *flag.FlagSet) (uuid.UUID, error) {
+	if fs.NArg() != 1 {
+		return uuid.Nil, usagef("expected exactly one subscription id")
+	}
+	id, err := uuid.Parse(fs.Arg(0))
+	if err != nil {
+		return uuid.Nil, usagef("invalid subscription id %q", fs.Arg(0))
+	}
+
+	return id, nil
+}
+
+// parseUserFlag разбирает необязательный флаг -user
+func parseUserFlag(user string) (uuid.UUID, error) {
+	if user == "" {
+		return uuid.Nil, nil
+	}
+	id, err := uuid.Parse(user)
+	if err != nil {
+		return uuid.Nil, usagef("invalid user id %q", user)
+	}
+
+	return id, nil
+}
+
+// stringsFlag - флаг, который можно указать несколько раз
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// parseCategoryFlag разбирает необязательный флаг -category
+func parseCategoryFlag(category string) (uuid.UUID, error) {
+	if category == "" {
+		return uuid.Nil, nil
+	}
+	id, err := uuid.Parse(category)
+	if err != nil {
+		return uuid.Nil, usagef("invalid category id %q", category)
+	}
+
+	return id, nil
+}
+
+func cmdCreate(env *cmdEnv, args []string) error {
+	fs := env.flagSet("create", "-user UUID -service NAME -price N -start YYYY-MM [-end YYYY-MM] [-category UUID] [-tag TAG]... [-auto-renew] [-renewal-months N] [-trial-until YYYY-MM] [-intro-price N -intro-months N]")
+	service := fs.String("service", "", "service name (required)")
+	price := fs.Int("price", 0, "monthly price (required)")
+	start := fs.String("start", "", "start month YYYY-MM (required)")
+	end := fs.String("end", "", "end month YYYY-MM (default start + 1 year)")
+	user := fs.String("user", "", "owner user id (required)")
+	category := fs.String("category", "", "category id")
+	var tags stringsFlag
+	fs.Var(&tags, "tag", "tag, can be repeated")
+	autoRenew := fs.Bool("auto-renew", false, "renew automatically when the subscription ends")
+	renewalMonths := fs.Int("renewal-months", 0, "renewal term in months (default 12)")
+	trialUntil := fs.String("trial-until", "", "last month of the free trial YYYY-MM")
+	introPrice := fs.Int("intro-price", 0, "introductory monthly price after the trial")
+	introMonths := fs.Int("intro-months", 0, "number of months the introductory price applies")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *user == "" || *service == "" || *price < 1 || *start == "" {
+		return usagef("-user, -service, -price and -start are required")
+	}
+
+	req := client.SubscriptionCreateRequest{
+		ServiceName:   *service,
+		Price:         *price,
+		StartDate:     *start,
+		AutoRenew:     *autoRenew,
+		RenewalMonths: *renewalMonths,
+		IntroPrice:    *introPrice,
+		IntroMonths:   *introMonths,
+	}
+	if *end != "" {
+		req.EndDate = end
+	}
+	if *trialUntil != "" {
+		req.TrialEndsAt = trialUntil
+	}
+	userID, err := parseUserFlag(*user)
+	if err != nil {
+		return err
+	}
+	if userID != uuid.Nil {
+		req.UserID = &userID
+	}
+	categoryID, err := parseCategoryFlag(*category)
+	if err != nil {
+		return err
+	}
+	if categoryID != uuid.Nil {
+		req.CategoryID = &categoryID
+	}
+	req.Tags = tags
+
+	sub, err := env.api.CreateSubscription(env.ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return printSubscription(env.stdout, env.format, sub)
+}
+
+func cmdGet(env *cmdEnv, args []string) error {
+	fs := env.flagSet("get", "ID")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := singleID(fs)
+	if err != nil {
+		return err
+	}
+
+	sub, err := env.api.GetSubscription(env.ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return printSubscription(env.stdout, env.format, sub)
+}
+
+func cmdList(env *cmdEnv, args []string) error {
+	fs := env.flagSet("list", "[-user UUID] [-service NAME] [-category UUID] [-tag TAG]... [-status STATUS]... [-filter EXPR]")
+	user := fs.String("user", "", "filter by user id")
+	service := fs.String("service", "", "filter by service name")
+	category := fs.String("category", "", "filter by category id, child categories included")
+	var tags, statuses stringsFlag
+	fs.Var(&tags, "tag", "filter by tag, can be repeated (all tags required)")
+	fs.Var(&statuses, "status", "filter by status (trial, active, paused, cancelled, expired), can be repeated")
+	expr := fs.String("filter", "", `filter expression, e.g. 'price > 500 and active_at("2025-06")'`)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	userID, err := parseUserFlag(*user)
+	if err != nil {
+		return err
+	}
+	categoryID, err := parseCategoryFlag(*category)
+	if err != nil {
+		return err
+	}
+
+	subs, err := env.api.ListSubscriptions(env.ctx, client.ListFilter{
+		UserID:      userID,
+		ServiceName: *service,
+		CategoryID:  categoryID,
+		Tags:        tags,
+		Statuses:    statuses,
+		Expr:        *expr,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printSubscriptions(env.stdout, env.format, subs)
+}
+
+func cmdUpdate(env *cmdEnv, args []string) error {
+	fs := env.flagSet("update", "[-service NAME] [-price N] [-price-from YYYY-MM] [-start YYYY-MM] [-end YYYY-MM] [-auto-renew true|false] [-renewal-months N] [-trial-until YYYY-MM|none] [-intro-price N] [-intro-months N] ID")
+	service := fs.String("service", "", "new service name")
+	price := fs.Int("price", 0, "new monthly price")
+	priceFrom := fs.String("price-from", "", "month YYYY-MM the new price applies from (default current month)")
+	start := fs.String("start", "", "new start month YYYY-MM")
+	end := fs.String("end", "", "new end month YYYY-MM")
+	autoRenew := fs.String("auto-renew", "", "turn auto-renewal on or off: true|false")
+	renewalMonths := fs.Int("renewal-months", 0, "new renewal term in months")
+	trialUntil := fs.String("trial-until", "", "new last trial month YYYY-MM, none removes the trial")
+	introPrice := fs.Int("intro-price", -1, "new introductory price, 0 removes it")
+	introMonths := fs.Int("intro-months", -1, "new number of introductory months")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := singleID(fs)
+	if err != nil {
+		return err
+	}
+
+	req := client.UpdateSubscriptionRequest{
+		ServiceName:        *service,
+		Price:              *price,
+		StartDate:          *start,
+		EndDate:            *end,
+		RenewalMonths:      *renewalMonths,
+		PriceEffectiveFrom: *priceFrom,
+	}
+	if *autoRenew != "" {
+		on, err := strconv.ParseBool(*autoRenew)
+		if err != nil {
+			return usagef("-auto-renew must be true or false")
+		}
+		req.AutoRenew = &on
+	}
+	switch *trialUntil {
+	case "":
+	case "none":
+		req.TrialEndsAt = new(string)
+	default:
+		req.TrialEndsAt = trialUntil
+	}
+	if *introPrice >= 0 {
+		req.IntroPrice = introPrice
+	}
+	if *introMonths >= 0 {
+		req.IntroMonths = introMonths
+	}
+	if req == (client.UpdateSubscriptionRequest{}) {
+		return usagef("nothing to update")
+	}
+	if *price < 0 {
+		return usagef("-price must be positive")
+	}
+
+	sub, err := env.api.UpdateSubscription(env.ctx, id, req)
+	if err != nil {
+		return err
+	}
+
+	return printSubscription(env.stdout, env.format, sub)
+}
+
+func cmdDelete(env *cmdEnv, args []string) error {
+	fs := env.flagSet("delete", "ID")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := singleID(fs)
+	if err != nil {
+		return err
+	}
+
+	return env.api.DeleteSubscription(env.ctx, id)
+}
+
+func cmdCancel(env *cmdEnv, args []string) error {
+	fs := env.flagSet("cancel", "[-reason TEXT] [-effective YYYY-MM] ID")
+	reason := fs.String("reason", "", "cancellation reason")
+	effective := fs.String("effective", "", "last paid month YYYY-MM (default current month)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := singleID(fs)
+	if err != nil {
+		return err
+	}
+
+	sub, err := env.api.CancelSubscription(env.ctx, id, client.CancelRequest{Reason: *reason, EffectiveDate: *effective})
+	if err != nil {
+		return err
+	}
+
+	return printSubscription(env.stdout, env.format, sub)
+}
+
+func cmdReactivate(env *cmdEnv, args []string) error {
+	fs := env.flagSet("reactivate", "[-end YYYY-MM] ID")
+	end := fs.String("end", "", "new end month YYYY-MM (default: keep a future end month, otherwise open-ended)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := singleID(fs)
+	if err != nil {
+		return err
+	}
+
+	sub, err := env.api.ReactivateSubscription(env.ctx, id, client.ReactivateRequest{EndDate: *end})
+	if err != nil {
+		return err
+	}
+
+	return printSubscription(env.stdout, env.format, sub)
+}
+
+func cmdTotal(env *cmdEnv, args []string) error {
+	fs := env.flagSet("total", "-from YYYY-MM -to YYYY-MM [-user UUID] [-service NAME] [-category UUID] [-tag TAG]... [-group-by category]")
+	from := fs.String("from", "", "first month of the period YYYY-MM (required)")
+	to := fs.String("to", "", "last month of the period YYYY-MM (required)")
+	user := fs.String("user", "", "filter by user id")
+	service := fs.String("service", "", "filter by service name")
+	category := fs.String("category", "", "filter by category id, child categories included")
+	groupBy := fs.String("group-by", "", "also print totals per group: category")
+	var tags stringsFlag
+	fs.Var(&tags, "tag", "filter by tag, can be repeated (all tags required)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *from == "" || *to == "" {
+		return usagef("-from and -to are required")
+	}
+
+	userID, err := parseUserFlag(*user)
+	if err != nil {
+		return err
+	}
+
+	categoryID, err := parseCategoryFlag(*category)
+	if err != nil {
+		return err
+	}
+
+	total, err := env.api.TotalCost(env.ctx, client.TotalFilter{
+		From:        *from,
+		To:          *to,
+		UserID:      userID,
+		ServiceName: *service,
+		CategoryID:  categoryID,
+		Tags:        tags,
+		GroupBy:     *groupBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printTotal(env.stdout, env.format, total)
+}
+
+func cmdExport(env *cmdEnv, args []string) error {
+	fs := env.flagSet("export", "[-format json|csv] [-f FILE] [-user UUID] [-service NAME] [-filter EXPR]")
+	format := fs.String("format", "", "file format: json or csv (default from -f extension, else csv)")
+	file := fs.String("f", "-", "output file, - for stdout")
+	user := fs.String("user", "", "filter by user id")
+	service := fs.String("service", "", "filter by service name")
+	expr := fs.String("filter", "", "filter expression, same as in list")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	fileFormat, err := fileFormat(*format, *file)
+	if err != nil {
+		return err
+	}
+
+	userID, err := parseUserFlag(*user)
+	if err != nil {
+		return err
+	}
+
+	subs, err := env.api.ListSubscriptions(env.ctx, client.ListFilter{UserID: userID, ServiceName: *service, Expr: *expr})
+	if err != nil {
+		return err
+	}
+
+	out := env.stdout
+	if *file != "-" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := printSubscriptions(out, fileFormat, subs); err != nil {
+		return err
+	}
+
+	if *file != "-" {
+		fmt.Fprintf(env.stderr, "exported %d subscriptions to %s\n", len(subs), *file)
+	}
+
+	return nil
+}
+
+// importRecord - строка файла импорта. Поле id (как в файле export) игнорируется
+type importRecord struct {
+	ServiceName string `json:"service_name"`
+	Price       int    `json:"price"`
+	UserID      string `json:"user_id"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+}
+
+func cmdImport(env *cmdEnv, args []string) error {
+	fs := env.flagSet("import", "[-format json|csv] FILE")
+	format := fs.String("format", "", "file format: json or csv (default from file extension, else csv)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("expected exactly one file, - for stdin")
+	}
+	file := fs.Arg(0)
+
+	fileFormat, err := fileFormat(*format, file)
+	if err != nil {
+		return err
+	}
+
+	in := env.stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var records []importRecord
+	if fileFormat == formatJSON {
+		err = json.NewDecoder(in).Decode(&records)
+	} else {
+		records, err = readCSVRecords(in)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	var created, failed int
+	var lastErr error
+	for i, rec := range records {
+		req, err := rec.toCreateRequest()
+		if err == nil {
+			_, err = env.api.CreateSubscription(env.ctx, req)
+		}
+		if err != nil {
+			failed++
+			lastErr = err
+			fmt.Fprintf(env.stderr, "record %d (%s): %v\n", i+1, rec.ServiceName, err)
+			continue
+		}
+		created++
+	}
+
+	fmt.Fprintf(env.stderr, "imported %d of %d subscriptions\n", created, len(records))
+	if failed > 0 {
+		return fmt.Errorf("%d records failed, last error: %v", failed, lastErr)
+	}
+
+	return nil
+}
+
+func (rec importRecord) toCreateRequest() (client.SubscriptionCreateRequest, error) {
+	req := client.SubscriptionCreateRequest{
+		ServiceName: rec.ServiceName,
+		Price:       rec.Price,
+		StartDate:   rec.StartDate,
+	}
+	if rec.EndDate != "" {
+		req.EndDate = &rec.EndDate
+	}
+	if rec.UserID != "" {
+		userID, err := uuid.Parse(rec.UserID)
+		if err != nil {
+			return req, fmt.Errorf("invalid user_id %q", rec.UserID)
+		}
+		req.UserID = &userID
+	}
+
+	return req, nil
+}
+
+// readCSVRecords читает CSV с заголовком; порядок колонок любой, лишние колонки игнорируются
+func readCSVRecords(in io.Reader) ([]importRecord, error) {
+	r := csv.NewReader(in)
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"service_name", "price", "start_date"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var records []importRecord
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		price, err := strconv.Atoi(field(row, "price"))
+		if err != nil {
+			line, _ := r.FieldPos(0)
+			return nil, fmt.Errorf("line %d: invalid price %q", line, field(row, "price"))
+		}
+
+		records = append(records, importRecord{
+			ServiceName: field(row, "service_name"),
+			Price:       price,
+			UserID:      field(row, "user_id"),
+			StartDate:   field(row, "start_date"),
+			EndDate:     field(row, "end_date"),
+		})
+	}
+
+	return records, nil
+}
+
+// fileFormat выбирает формат файла: явный -format, иначе расширение, иначе csv
+func fileFormat(explicit, file string) (string, error) {
+	switch explicit {
+	case formatJSON, formatCSV:
+		return explicit, nil
+	case "":
+	default:
+		return "", usagef("unknown file format %q", explicit)
+	}
+
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		return formatJSON, nil
+	}
+
+	return formatCSV, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+// Config - настройки subsctl. Источники по возрастанию приоритета:
+// файл конфигурации, переменные окружения, флаги командной строки
+type Config struct {
+	ServerURL string `yaml:"server_url" env:"SUBSCTL_SERVER_URL" env-default:"http://localhost:8080"`
+	APIKey    string `yaml:"api_key" env:"SUBSCTL_API_KEY"`
+}
+
+// defaultConfigPath возвращает $SUBSCTL_CONFIG или ~/.config/subsctl/config.yaml
+func defaultConfigPath() string {
+	if path := os.Getenv("SUBSCTL_CONFIG"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "subsctl", "config.yaml")
+}
+
+// loadConfig читает файл конфигурации, если он есть, и переменные окружения.
+// Отсутствие файла по умолчанию ошибкой не считается, явно указанного - считается
+func loadConfig(path string, explicit bool) (*Config, error) {
+	var cfg Config
+
+	if path != "" {
+		_, err := os.Stat(path)
+		switch {
+		case err == nil:
+			if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+				return nil, fmt.Errorf("error reading config file %s: %w", path, err)
+			}
+			return &cfg, nil
+		case !errors.Is(err, os.ErrNotExist) || explicit:
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return nil, fmt.Errorf("error reading environment: %w", err)
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,162 @@
+// Command subsctl is a command-line client for the subscriptions HTTP API
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/odlev/subscriptions/pkg/client"
+)
+
+// коды завершения
+const (
+	exitOK       = 0
+	exitError    = 1 // непредвиденная ошибка, в т.ч. частично неудачный import
+	exitUsage    = 2 // неверные аргументы командной строки
+	exitNotFound = 3 // 404 от сервера
+	exitInvalid  = 4 // 400 от сервера
+	exitServer   = 5 // 5xx или сервер недоступен
+)
+
+const usage = `Usage: subsctl [global flags] <command> [flags] [args]
+
+Commands:
+  create      create a subscription
+  get         show a subscription by id
+  list        list subscriptions
+  update      change fields of a subscription
+  delete      delete a subscription
+  cancel      cancel a subscription (-reason, -effective YYYY-MM)
+  reactivate  reactivate a cancelled or expired subscription
+  import      create subscriptions from a CSV or JSON file
+  export      write subscriptions as CSV or JSON
+  total       total cost of subscriptions for a period
+
+Global flags:
+  -config string    config file (default $SUBSCTL_CONFIG or ~/.config/subsctl/config.yaml)
+  -server string    server URL (overrides server_url and $SUBSCTL_SERVER_URL)
+  -api-key string   API key (overrides api_key and $SUBSCTL_API_KEY)
+  -o string         output format: table, json or csv (default "table")
+
+Run "subsctl <command> -h" for command flags.
+`
+
+// usageError - ошибка в аргументах, завершает работу с exitUsage
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("subsctl", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.Usage = func() { fmt.Fprint(stderr, usage) }
+
+	configPath := global.String("config", "", "config file")
+	server := global.String("server", "", "server URL")
+	apiKey := global.String("api-key", "", "API key")
+	format := global.String("o", formatTable, "output format")
+
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if global.NArg() == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	if !validFormat(*format) {
+		fmt.Fprintf(stderr, "subsctl: unknown output format %q\n", *format)
+		return exitUsage
+	}
+
+	explicit := *configPath != ""
+	if !explicit {
+		*configPath = defaultConfigPath()
+	}
+	cfg, err := loadConfig(*configPath, explicit)
+	if err != nil {
+		fmt.Fprintf(stderr, "subsctl: %v\n", err)
+		return exitError
+	}
+	if *server != "" {
+		cfg.ServerURL = *server
+	}
+	if *apiKey != "" {
+		cfg.APIKey = *apiKey
+	}
+
+	cmdName, cmdArgs := global.Arg(0), global.Args()[1:]
+
+	cmd, ok := commands[cmdName]
+	if !ok {
+		fmt.Fprintf(stderr, "subsctl: unknown command %q\n\n%s", cmdName, usage)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	api, err := client.New(cfg.ServerURL, client.WithAPIKey(cfg.APIKey))
+	if err != nil {
+		fmt.Fprintf(stderr, "subsctl: invalid server URL: %v\n", err)
+		return exitUsage
+	}
+
+	env := &cmdEnv{
+		ctx:    ctx,
+		api:    api,
+		format: *format,
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+
+	err = cmd(env, cmdArgs)
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "subsctl %s: %v\n", cmdName, err)
+
+	return exitCode(err)
+}
+
+func exitCode(err error) int {
+	var uErr *usageError
+	if errors.As(err, &uErr) {
+		return exitUsage
+	}
+
+	switch {
+	case errors.Is(err, client.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, client.ErrInvalidRequest), errors.Is(err, client.ErrConflict):
+		return exitInvalid
+	case errors.Is(err, client.ErrServer), errors.Is(err, client.ErrUnavailable):
+		return exitServer
+	default:
+		return exitError
+	}
+}
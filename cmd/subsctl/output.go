@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/odlev/subscriptions/pkg/client"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+var csvHeader = []string{"id", "service_name", "price", "user_id", "start_date", "end_date"}
+
+func validFormat(format string) bool {
+	return format == formatTable || format == formatJSON || format == formatCSV
+}
+
+func printSubscriptions(w io.Writer, format string, subs []client.Subscription) error {
+	switch format {
+	case formatJSON:
+		if subs == nil {
+			subs = []client.Subscription{}
+		}
+		return printJSON(w, subs)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return err
+		}
+		for _, sub := range subs {
+			if err := cw.Write(subscriptionRecord(sub)); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tSERVICE\tPRICE\tUSER\tSTART\tEND\tSTATUS\tTAGS")
+		for _, sub := range subs {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n", sub.ID, sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate, sub.Status, strings.Join(sub.Tags, ","))
+		}
+		return tw.Flush()
+	}
+}
+
+func printSubscription(w io.Writer, format string, sub *client.Subscription) error {
+	if format == formatJSON {
+		return printJSON(w, sub)
+	}
+
+	return printSubscriptions(w, format, []client.Subscription{*sub})
+}
+
+func printTotal(w io.Writer, format string, total *client.TotalCostResponse) error {
+	switch format {
+	case formatJSON:
+		return printJSON(w, total)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"from", "to", "group", "total"})
+		for _, group := range total.Groups {
+			_ = cw.Write([]string{total.From, total.To, groupName(group), strconv.Itoa(group.Total)})
+		}
+		_ = cw.Write([]string{total.From, total.To, "", strconv.Itoa(total.Total)})
+		cw.Flush()
+		return cw.Error()
+	default:
+		for _, group := range total.Groups {
+			if _, err := fmt.Fprintf(w, "%s: %d\n", groupName(group), group.Total); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(w, "%s..%s: %d\n", total.From, total.To, total.Total)
+		return err
+	}
+}
+
+// groupName - название группы для вывода, подписки без категории идут как "-"
+func groupName(group client.CostGroup) string {
+	if group.Name == "" {
+		return "-"
+	}
+
+	return group.Name
+}
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+func subscriptionRecord(sub client.Subscription) []string {
+	return []string{
+		sub.ID.String(),
+		sub.ServiceName,
+		strconv.Itoa(sub.Price),
+		sub.UserID.String(),
+		sub.StartDate,
+		sub.EndDate,
+	}
+}
@@ -15,51 +15,99 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/delete/{id}": {
-            "delete": {
-                "description": "Удаляет подписку по ID и возвращает название удаленного сервиса",
+        "/api/v1/budgets": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Удалить подписку",
+                "summary": "Получить список бюджетов",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "example": "550e8400-e29b-41d4-a716-446655440000",
-                        "description": "ID подписки",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное удаление\" example({\"status\":\"Success\",\"deleted service\":\"Netflix\"})",
+                        "description": "Список бюджетов",
+                        "schema": {
+                            "$ref": "#/definitions/storage.BudgetsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный user_id\" example({\"error\": \"invalid user_id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает месячный бюджет пользователя на все подписки или на категорию (вместе с дочерними)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Создать бюджет",
+                "parameters": [
+                    {
+                        "description": "Данные бюджета",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.BudgetCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Бюджет создан",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Budget"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/api/v1/budgets/{id}"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Неверный ID\" example({\"error\":\"failed to parse id\",\"details\":\"invalid UUID format\"})",
+                        "description": "Ошибка валидации\" example({\"error\": \"user_id does not reference an existing user\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Подписка не найдена\" example({\"error\":\"subscription not found\"})",
+                    "409": {
+                        "description": "Бюджет уже есть\" example({\"error\": \"budget for this user and category already exists\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Внутренняя ошибка сервера\" example({\"error\":\"internal server error\"})",
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -68,43 +116,144 @@ const docTemplate = `{
                 }
             }
         },
-        "/get/list": {
+        "/api/v1/budgets/{id}": {
             "get": {
-                "description": "Возвращает список подписок с возможностью фильтрации по user_id и названию сервиса. Если подписки не найдены, возвращает \"not found\".",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Получить список подписок",
+                "summary": "Получить бюджет по ID",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "example": "550e8400-e29b-41d4-a716-446655440000",
-                        "description": "ID пользователя для фильтрации",
-                        "name": "user_id",
-                        "in": "query"
+                        "description": "ID бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Бюджет",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Budget"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Бюджет не найден\" example({\"error\": \"budget not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Удалить бюджет",
+                "parameters": [
                     {
                         "type": "string",
-                        "example": "Netflix",
-                        "description": "Название сервиса для фильтрации",
-                        "name": "service_name",
-                        "in": "query"
+                        "format": "uuid",
+                        "description": "ID бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Если подписок нет\" example({\"subscriptions\": \"not found\"})",
+                    "204": {
+                        "description": "Бюджет удален"
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Бюджет не найден\" example({\"error\": \"budget not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Меняет сумму и/или валюту бюджета",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Обновить бюджет",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.BudgetUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Бюджет обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Budget"
+                        }
                     },
                     "400": {
-                        "description": "Неверный user_id\" example({\"error\": \"invalid user_id\"})",
+                        "description": "Ошибка валидации\" example({\"error\": \"failed to decode request body\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Бюджет не найден\" example({\"error\": \"budget not found\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -120,50 +269,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/get/{id}": {
+        "/api/v1/budgets/{id}/status": {
             "get": {
-                "description": "Возвращает подписку в формате, готовом для API (с преобразованными датами в необходимый формат)",
+                "description": "Возвращает расход за месяц (подписки, действующие в этом месяце), прогноз на следующий месяц, достигнутый порог (80 или 100%) и оповещения, отправленные за месяц",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "budgets"
                 ],
-                "summary": "Получить подписку по ID",
+                "summary": "Состояние бюджета",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "example": "c9fd9538-e38c-429c-981b-f3ed34aee585",
-                        "description": "ID подписки",
+                        "description": "ID бюджета",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-07",
+                        "description": "Месяц YYYY-MM, по умолчанию текущий в часовом поясе пользователя",
+                        "name": "month",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешно получено",
+                        "description": "Состояние бюджета",
                         "schema": {
-                            "$ref": "#/definitions/storage.SubscriptionR"
+                            "$ref": "#/definitions/storage.BudgetStatus"
                         }
                     },
                     "400": {
-                        "description": "Неверный UUID\" example({\"error\": \"failed to parse UUID\"})",
+                        "description": "Неверный месяц\" example({\"error\": \"invalid month\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Подписка не найдена\" example({\"subscription\": \"not found\"})",
+                        "description": "Бюджет не найден\" example({\"error\": \"budget not found\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"failed to get subscription, internal error\"})",
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -172,9 +327,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/new": {
+        "/api/v1/categories": {
+            "get": {
+                "description": "Возвращает все категории плоским списком, иерархия задается полем parent_id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Получить список категорий",
+                "responses": {
+                    "200": {
+                        "description": "Список категорий",
+                        "schema": {
+                            "$ref": "#/definitions/storage.CategoriesListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Добавляет новую подписку для пользователя. Поля user_id и end_date опциональны, если не указать user_id - сгенерируется автоматически, если не указать end_date - прибавиться + 1 год от начала подписки.",
+                "description": "Создает категорию. Без parent_id категория корневая.",
                 "consumes": [
                     "application/json"
                 ],
@@ -182,37 +362,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "categories"
                 ],
-                "summary": "Создать подписку",
+                "summary": "Создать категорию",
                 "parameters": [
                     {
-                        "description": "Данные подписки",
+                        "description": "Данные категории",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/storage.SubscriptionCreateRequest"
+                            "$ref": "#/definitions/storage.CategoryCreateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Успешное создание",
+                        "description": "Категория создана",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Category"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/api/v1/categories/{id}"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"category_id does not reference an existing category\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Ошибка валидации",
+                    "409": {
+                        "description": "Имя занято\" example({\"error\": \"category with this name already exists under the same parent\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Внутрення ошибка сервера",
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -221,83 +413,3759 @@ const docTemplate = `{
                 }
             }
         },
-        "/update/{id}": {
-            "patch": {
-                "description": "Обновляет любые поля записи о подписке ID и User_ID, сохраняет время последнего обновления в поле updated_at базы данных",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/categories/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "categories"
                 ],
-                "summary": "Обновить подписку",
+                "summary": "Получить категорию по ID",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "example": "550e8400-e29b-41d4-a716-446655440000",
-                        "description": "ID подписки",
+                        "description": "ID категории",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Данные для обновления",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/storage.UpdateSubscriptionRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешно обновлено\" example({\"status\": \"success\"})",
+                        "description": "Категория",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Некорретный диапазон дат\" example({\"error\": \"invalid request\"})",
+                    "404": {
+                        "description": "Категория не найдена\" example({\"error\": \"category not found\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal error\"})",
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "storage.SubscriptionCreateRequest": {
-            "type": "object",
-            "required": [
-                "price",
-                "service_name",
-                "start_date"
-            ],
-            "properties": {
-                "end_date": {
-                    "type": "string",
-                    "example": "2026-07"
-                },
-                "price": {
-                    "type": "integer",
+            },
+            "delete": {
+                "description": "Удаляет категорию. Подписки категории остаются без категории, категорию с дочерними удалить нельзя.",
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Удалить категорию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Категория удалена"
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Категория не найдена\" example({\"error\": \"category not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Есть дочерние категории\" example({\"error\": \"category still has child categories\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Переименовывает категорию и/или переносит ее под другого родителя. Нулевой UUID в parent_id делает категорию корневой.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Обновить категорию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.CategoryUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Категория обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"category can not be moved under itself or its descendant\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Категория не найдена\" example({\"error\": \"category not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Имя занято\" example({\"error\": \"category with this name already exists under the same parent\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Получить каталог сервисов",
+                "responses": {
+                    "200": {
+                        "description": "Каталог сервисов",
+                        "schema": {
+                            "$ref": "#/definitions/storage.ServicesListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавляет сервис с каноническим названием и псевдонимами. Существующие подписки без service_id, чье название совпадает с названием или псевдонимом, привязываются к сервису.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Добавить сервис в каталог",
+                "parameters": [
+                    {
+                        "description": "Данные сервиса",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.ServiceCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Сервис добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Service"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/api/v1/services/{id}"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"failed to decode request body\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Название или псевдоним заняты\" example({\"error\": \"service name or alias is already used by another service\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Получить сервис по ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID сервиса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сервис",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Сервис не найден\" example({\"error\": \"service not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет сервис. Подписки сохраняют service_name, service_id у них обнуляется.",
+                "tags": [
+                    "services"
+                ],
+                "summary": "Удалить сервис из каталога",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID сервиса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Сервис удален"
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Сервис не найден\" example({\"error\": \"service not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Обновляет переданные поля сервиса. Новое название проставляется всем привязанным подпискам, aliases заменяются целиком.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Обновить сервис",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID сервиса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.ServiceUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сервис обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"failed to decode request body\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Сервис не найден\" example({\"error\": \"service not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Название или псевдоним заняты\" example({\"error\": \"service name or alias is already used by another service\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions": {
+            "get": {
+                "description": "Возвращает список подписок с фильтрацией по user_id, названию сервиса, категории (вместе с дочерними) и тегам. Пустой результат - пустой массив, а не 404.\nС q подписки ищутся по названию сервиса и упорядочены по релевантности.\nС limit список отдается страницами по (start_date, id): заполненная страница содержит next_cursor, который передается в after за следующей.\nДиапазоны start_from..start_to и end_from..end_to, active_at и price_min..price_max включительны.\nfilter принимает выражение над полями service_name, price, status, user_id, service_id, category_id, start_date, end_date, trial_ends_at, auto_renew, renewal_months, intro_price, cancel_reason; ошибка разбора указывает position.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Получить список подписок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Название сервиса, можно указать несколько раз - подписка на любой из них",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID категории, подписки дочерних категорий тоже попадают в выборку",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Тег, можно указать несколько раз - подписка должна иметь все",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статус (trial, active, paused, cancelled, expired), можно указать несколько раз",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "netf",
+                        "description": "Поиск по названию сервиса: начало названия или похожее название, без учета регистра. Результаты упорядочены по релевантности",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-01",
+                        "description": "start_date не раньше месяца YYYY-MM",
+                        "name": "start_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-06",
+                        "description": "start_date не позже месяца YYYY-MM",
+                        "name": "start_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-01",
+                        "description": "end_date не раньше месяца YYYY-MM, бессрочные подписки не попадают",
+                        "name": "end_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-12",
+                        "description": "end_date не позже месяца YYYY-MM, бессрочные подписки не попадают",
+                        "name": "end_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-03",
+                        "description": "Подписка действует и не на паузе в месяце YYYY-MM",
+                        "name": "active_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 300,
+                        "description": "Цена не меньше, с active_at - цена в этом месяце",
+                        "name": "price_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1000,
+                        "description": "Цена не больше, с active_at - цена в этом месяце",
+                        "name": "price_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "price \u003e 500 and service_name ~ \"net\" and active_at(\"2025-06\"",
+                        "description": "Выражение фильтра: сравнения полей (=, !=, \u003c, \u003c=, \u003e, \u003e=, ~ - содержит, !~, in, is null), функции active_at, paused_at, has_tag, and, or, not и скобки",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-03-01",
+                        "description": "Список на момент в прошлом (RFC 3339 или YYYY-MM-DD) по истории версий; не сочетается с active_at, price_min, price_max и filter",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 50,
+                        "description": "Размер страницы, 1-1000; без него возвращается весь список",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор next_cursor предыдущей страницы; не сочетается с q, с q next_cursor не возвращается",
+                        "name": "after",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список подписок",
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры фильтра, по одной ошибке на параметр в params\" example({\"error\": \"price_min must be a non-negative integer\", \"params\": [{\"param\": \"price_min\", \"error\": \"price_min must be a non-negative integer\"}]})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает подписку и возвращает ее представление. Адрес нового ресурса передается в заголовке Location.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Создать подписку",
+                "parameters": [
+                    {
+                        "description": "Данные подписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionCreateRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "warn",
+                            "reject"
+                        ],
+                        "type": "string",
+                        "description": "Пересечение с подпиской того же пользователя на тот же сервис: warn - записать и вернуть conflicts, reject - 409",
+                        "name": "conflict_policy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Подписка создана",
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionR"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/api/v1/subscriptions/{id}"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"failed to decode request body\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Пересечение при conflict_policy=reject\" example({\"error\": \"subscription overlaps another subscription to the same service\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/aggregate": {
+            "get": {
+                "description": "Считает метрики по подпискам с фильтрами списка одним запросом в разрезе group_by.\ngroup_by: service, user, category (category_id и category_name), start_month - можно несколько; без него одна строка по всем подпискам.\nmetrics: count, sum_price, avg_price, min_price, max_price (цена в текущем месяце), по умолчанию count.\nОтвет - таблица: columns с именами и типами (string, uuid, month, integer, number) и rows со значениями в том же порядке.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Агрегация подписок",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "service",
+                                "user",
+                                "category",
+                                "start_month"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Группировка, через запятую или несколько раз",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "count",
+                                "sum_price",
+                                "avg_price",
+                                "min_price",
+                                "max_price"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Метрики, через запятую или несколько раз",
+                        "name": "metrics",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Название сервиса, можно указать несколько раз",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID категории, включая дочерние",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Тег, можно указать несколько раз",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статус, можно указать несколько раз",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-03",
+                        "description": "Подписка действует и не на паузе в месяце YYYY-MM",
+                        "name": "active_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "price \u003e 500",
+                        "description": "Выражение фильтра, как у списка",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Таблица агрегации",
+                        "schema": {
+                            "$ref": "#/definitions/storage.AggregateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры\" example({\"error\": \"unsupported group_by\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/forecast": {
+            "get": {
+                "description": "Прогнозирует расходы на months месяцев начиная с from по неистекшим подпискам с теми же фильтрами, что у списка.\nМесяц стоит столько же, сколько в /total: с пробным периодом, вступительной ценой, паузами и ценой, действующей в этом месяце.\nПосле end_date подписка продолжается, только если продлевается: renewal=auto - подписки с auto_renew, all - все неотмененные, none - никакие.\nВозвращает итоги по месяцам и вклад каждой подписки с месяцами продления.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Прогноз расходов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2025-10",
+                        "description": "Первый месяц прогноза YYYY-MM, по умолчанию текущий",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 12,
+                        "description": "Горизонт в месяцах, от 1 до 120 (по умолчанию 12)",
+                        "name": "months",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "auto",
+                            "all",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "Режим продления (по умолчанию auto)",
+                        "name": "renewal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Netflix",
+                        "description": "Название сервиса для фильтрации",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID категории, включая дочерние",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Тег, можно указать несколько раз",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статус, можно указать несколько раз",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Прогноз",
+                        "schema": {
+                            "$ref": "#/definitions/storage.ForecastResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры\" example({\"error\": \"months must be between 1 and 120\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/overlaps": {
+            "get": {
+                "description": "Возвращает пары подписок одного пользователя на один сервис (по каталогу или по названию без учета регистра), периоды которых пересекаются.\nfrom..to - общие месяцы пары, пустой to - пересечение не ограничено.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Отчет о пересекающихся подписках",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID владельца подписок",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пересечения",
+                        "schema": {
+                            "$ref": "#/definitions/storage.OverlapsReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный user_id\" example({\"error\": \"invalid user_id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/suggest": {
+            "get": {
+                "description": "Возвращает различные названия сервисов подписок, подходящие под q: сначала совпадения по началу названия, затем похожие (триграммы) по убыванию похожести.\nРегистр не учитывается. Остальные фильтры такие же, как у списка.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Подсказки названий сервисов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "netf",
+                        "description": "Начало или часть названия сервиса",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "description": "Число подсказок, от 1 до 50 (по умолчанию 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID категории, включая дочерние",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Тег, можно указать несколько раз",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статус, можно указать несколько раз",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подсказки",
+                        "schema": {
+                            "$ref": "#/definitions/storage.ServiceNamesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры\" example({\"error\": \"q is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/timeseries": {
+            "get": {
+                "description": "Для каждого месяца периода from..to (включительно, не больше 240 месяцев) возвращает стоимость подписок и число действующих подписок (подписки на паузе не считаются).\nФильтры те же, что у списка. С group_by месяц дополнительно разбит по сервисам, категориям или пользователям; при group_by=user общая подписка делится между участниками по долям.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Расходы по месяцам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2025-01",
+                        "description": "Начало периода YYYY-MM",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-12",
+                        "description": "Конец периода YYYY-MM",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Netflix",
+                        "description": "Название сервиса для фильтрации",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID категории, включая дочерние",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Тег, можно указать несколько раз",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статус, можно указать несколько раз",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "service",
+                            "category",
+                            "user"
+                        ],
+                        "type": "string",
+                        "description": "Группировка",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расходы по месяцам",
+                        "schema": {
+                            "$ref": "#/definitions/storage.TimeSeriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры\" example({\"error\": \"period must not exceed 240 months\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/total": {
+            "get": {
+                "description": "Считает стоимость всех подписок за каждый месяц периода from..to (включительно) с теми же фильтрами, что и список.\nС group_by=category дополнительно возвращает суммы по категориям.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Суммарная стоимость подписок за период",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2025-01",
+                        "description": "Начало периода YYYY-MM",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-12",
+                        "description": "Конец периода YYYY-MM",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Netflix",
+                        "description": "Название сервиса для фильтрации",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID категории, включая дочерние",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Тег, можно указать несколько раз",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Статус, можно указать несколько раз",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "category"
+                        ],
+                        "type": "string",
+                        "description": "Группировка",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Суммарная стоимость",
+                        "schema": {
+                            "$ref": "#/definitions/storage.TotalCostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры\" example({\"error\": \"from and to are required\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}": {
+            "get": {
+                "description": "С as_of возвращает подписку в том виде, в каком она была в этот момент, по истории версий; 404 - подписки тогда не было.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Получить подписку по ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-03-01T12:00:00Z",
+                        "description": "Момент в прошлом: RFC 3339 или YYYY-MM-DD",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка",
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionR"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID или as_of\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Полностью перезаписывает подписку. Если не указан end_date - ставится start_date + 1 год, если не указан user_id - остается прежний.\nОтличающаяся price действует с текущего месяца, история цены сохраняется.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Заменить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое состояние подписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionCreateRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "warn",
+                            "reject"
+                        ],
+                        "type": "string",
+                        "description": "Пересечение с подпиской того же пользователя на тот же сервис: warn - записать и вернуть conflicts, reject - 409",
+                        "name": "conflict_policy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionR"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"failed to decode request body\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Пересечение при conflict_policy=reject\" example({\"error\": \"subscription overlaps another subscription to the same service\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Удалить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Подписка удалена"
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Обновляет только переданные поля подписки. Новая price не переписывает историю:\nона действует с месяца price_effective_from (по умолчанию текущего), прежние месяцы считаются по старой цене.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Частично обновить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.UpdateSubscriptionRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "warn",
+                            "reject"
+                        ],
+                        "type": "string",
+                        "description": "Пересечение с подпиской того же пользователя на тот же сервис: warn - записать и вернуть conflicts, reject - 409",
+                        "name": "conflict_policy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionR"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"failed to decode request body\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Пересечение при conflict_policy=reject\" example({\"error\": \"subscription overlaps another subscription to the same service\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/cancel": {
+            "post": {
+                "description": "Подписка получает статус cancelled и действует по месяц effective_date включительно (по умолчанию текущий месяц пользователя).\nАвтопродление выключается, паузы после effective_date удаляются. Отменить можно подписку в статусе trial, active или paused. Тело можно не передавать.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Отменить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина и дата отмены",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/storage.CancelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отмененная подписка",
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionR"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"effective_date must be between start_date and end_date\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Переход не разрешен\" example({\"error\": \"subscription status transition is not allowed\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/members": {
+            "get": {
+                "description": "Владелец участвует в оплате с весом 1, если его доля не задана явно, и попадает в список только в этом случае.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Участники общей подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Участники",
+                        "schema": {
+                            "$ref": "#/definitions/storage.MembersListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/members/{user_id}": {
+            "put": {
+                "description": "Доля задается весом (weight) или фиксированной суммой в месяц (amount), ровно одним из них.\nФиксированные суммы вычитаются из стоимости месяца первыми, остаток делится между участниками по весам.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Добавить участника или изменить его долю",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID пользователя-участника",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Доля участника",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.MemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Участник",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Member"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"exactly one of weight and amount must be positive\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Доля участника переходит к остальным участникам по весам.",
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Убрать участника из подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID пользователя-участника",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Участник удален"
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse user_id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка или участник не найдены\" example({\"error\": \"member not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/pause": {
+            "post": {
+                "description": "Месяцы паузы с from по until включительно не учитываются в расходах, бюджетах и не считаются активными.\nБез from пауза начинается с текущего месяца пользователя, без until длится до возобновления. Тело можно не передавать.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Поставить подписку на паузу",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Период паузы",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/storage.PauseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Паузы подписки",
+                        "schema": {
+                            "$ref": "#/definitions/storage.PausesListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"pause must be within start_date and end_date\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Пересечение с другой паузой\" example({\"error\": \"pause overlaps an existing pause\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/pauses": {
+            "get": {
+                "description": "Возвращает паузы по возрастанию start_month. Пауза без end_month длится до возобновления.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Паузы подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Паузы подписки",
+                        "schema": {
+                            "$ref": "#/definitions/storage.PausesListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/prices": {
+            "get": {
+                "description": "Возвращает периоды цены по возрастанию effective_from. Каждая цена действует с effective_from до начала следующего периода.\nНовый период создается, когда в PATCH или PUT передана цена, отличная от действующей.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "История цены подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Периоды цены",
+                        "schema": {
+                            "$ref": "#/definitions/storage.PricesListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/reactivate": {
+            "post": {
+                "description": "Снимает отмену; статус снова следует из дат и пауз. Если end_date не передан, сохраняется еще не прошедший end_date.\nПодписке, чей end_date уже прошел, нужен новый end_date, не раньше текущего месяца; без него 400.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Возобновить отмененную или истекшую подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый срок",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/storage.ReactivateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Возобновленная подписка",
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionR"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"end_date can not be earlier than start_date\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка не отменена и не истекла\" example({\"error\": \"subscription status transition is not allowed\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/renewals": {
+            "get": {
+                "description": "Возвращает продления подписки с auto_renew, новые первыми. Подписка продлевается на renewal_months, когда заканчивается месяц end_date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "История автопродлений подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Продления",
+                        "schema": {
+                            "$ref": "#/definitions/storage.RenewalsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/resume": {
+            "post": {
+                "description": "Пауза, действующая в месяце from (по умолчанию текущий месяц пользователя), заканчивается месяцем раньше.\nЕсли пауза начинается с from, она удаляется. Тело можно не передавать.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Возобновить подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Месяц возобновления",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/storage.ResumeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Паузы подписки",
+                        "schema": {
+                            "$ref": "#/definitions/storage.PausesListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"invalid request\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка не на паузе\" example({\"error\": \"subscription is not paused\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/tags": {
+            "put": {
+                "description": "Заменяет набор тегов подписки целиком, пустой массив снимает все теги",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Заменить теги подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый набор тегов",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка с новыми тегами",
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionR"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"tag must be non-empty and at most 64 characters\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавляет один тег, повторное добавление ничего не меняет",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Добавить тег подписке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Тег",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка с новым тегом",
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionR"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"tag must be non-empty and at most 64 characters\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/tags/{tag}": {
+            "delete": {
+                "tags": [
+                    "subscriptions v1"
+                ],
+                "summary": "Снять тег с подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "family",
+                        "description": "Тег",
+                        "name": "tag",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Тег снят"
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tags": {
+            "get": {
+                "description": "Возвращает все теги с числом подписок у каждого",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получить список тегов",
+                "responses": {
+                    "200": {
+                        "description": "Список тегов",
+                        "schema": {
+                            "$ref": "#/definitions/storage.TagsListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Теги хранятся в нижнем регистре. Обычно создавать тег заранее не нужно: он создается при добавлении к подписке.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Создать тег",
+                "parameters": [
+                    {
+                        "description": "Тег",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Тег создан",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"tag must be non-empty and at most 64 characters\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Тег уже есть\" example({\"error\": \"tag already exists\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tags/{id}": {
+            "delete": {
+                "description": "Удаляет тег из справочника и снимает его со всех подписок",
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Удалить тег",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID тега",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Тег удален"
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Тег не найден\" example({\"error\": \"tag not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Новое имя сразу видно у всех подписок с этим тегом",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Переименовать тег",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID тега",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое имя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Тег переименован",
+                        "schema": {
+                            "$ref": "#/definitions/storage.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"tag must be non-empty and at most 64 characters\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Тег не найден\" example({\"error\": \"tag not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Имя занято\" example({\"error\": \"tag already exists\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получить список пользователей",
+                "responses": {
+                    "200": {
+                        "description": "Список пользователей",
+                        "schema": {
+                            "$ref": "#/definitions/storage.UsersListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Создать пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.UserCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Пользователь создан",
+                        "schema": {
+                            "$ref": "#/definitions/storage.User"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/api/v1/users/{id}"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"failed to decode request body\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Email уже занят\" example({\"error\": \"user with this email already exists\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получить пользователя по ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/storage.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден\" example({\"error\": \"user not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет пользователя. Пользователя с подписками удалить нельзя.",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Удалить пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Пользователь удален"
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\": \"failed to parse id\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден\" example({\"error\": \"user not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "У пользователя есть подписки\" example({\"error\": \"user still has subscriptions\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Обновляет только переданные поля пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Обновить пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.UserUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/storage.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации\" example({\"error\": \"invalid timezone\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден\" example({\"error\": \"user not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Email уже занят\" example({\"error\": \"user with this email already exists\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/delete/{id}": {
+            "delete": {
+                "description": "Удаляет подписку по ID и возвращает название удаленного сервиса",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Удалить подписку",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "example": "550e8400-e29b-41d4-a716-446655440000",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное удаление\" example({\"status\":\"Success\",\"deleted service\":\"Netflix\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID\" example({\"error\":\"failed to parse id\",\"details\":\"invalid UUID format\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\":\"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\":\"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/get/list": {
+            "get": {
+                "description": "Возвращает список подписок с возможностью фильтрации по user_id и названию сервиса. Если подписки не найдены, возвращает \"not found\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получить список подписок",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "example": "550e8400-e29b-41d4-a716-446655440000",
+                        "description": "ID пользователя для фильтрации",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Netflix",
+                        "description": "Название сервиса для фильтрации",
+                        "name": "service_name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Если подписок нет\" example({\"subscriptions\": \"not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры фильтра, по одной ошибке на параметр в params\" example({\"error\": \"invalid user_id\", \"params\": [{\"param\": \"user_id\", \"error\": \"invalid user_id\"}]})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal server error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/get/{id}": {
+            "get": {
+                "description": "Возвращает подписку в формате, готовом для API (с преобразованными датами в необходимый формат)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получить подписку по ID",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "example": "c9fd9538-e38c-429c-981b-f3ed34aee585",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешно получено",
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionR"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный UUID\" example({\"error\": \"failed to parse UUID\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"subscription\": \"not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"failed to get subscription, internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/new": {
+            "post": {
+                "description": "Добавляет новую подписку для существующего пользователя (user_id обязателен, см. /api/v1/users). Поле end_date опционально, если не указать end_date - прибавиться + 1 год от начала подписки.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Создать подписку",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Данные подписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.SubscriptionCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Успешное создание",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутрення ошибка сервера",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/update/{id}": {
+            "patch": {
+                "description": "Обновляет любые поля записи о подписке ID и User_ID, сохраняет время последнего обновления в поле updated_at базы данных",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Обновить подписку",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "example": "550e8400-e29b-41d4-a716-446655440000",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storage.UpdateSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешно обновлено\" example({\"status\": \"success\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Некорретный диапазон дат\" example({\"error\": \"invalid request\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена\" example({\"error\": \"subscription not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\" example({\"error\": \"internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "storage.AggregateColumn": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "service_name"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "string"
+                }
+            }
+        },
+        "storage.AggregateResponse": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.AggregateColumn"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                }
+            }
+        },
+        "storage.Budget": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 3000
+                },
+                "category_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "storage.BudgetAlert": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 3000
+                },
+                "budget_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "category_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "spent": {
+                    "type": "integer",
+                    "example": 2600
+                },
+                "threshold": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "storage.BudgetCreateRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3000
+                },
+                "category_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "storage.BudgetStatus": {
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.BudgetAlert"
+                    }
+                },
+                "budget": {
+                    "$ref": "#/definitions/storage.Budget"
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "percent": {
+                    "description": "Percent - Spent в процентах от Amount",
+                    "type": "integer",
+                    "example": 86
+                },
+                "projected": {
+                    "type": "integer",
+                    "example": 3100
+                },
+                "spent": {
+                    "type": "integer",
+                    "example": 2600
+                },
+                "threshold": {
+                    "description": "Threshold - наибольший достигнутый порог (80 или 100), 0 - ни один",
+                    "type": "integer",
+                    "example": 80
+                }
+            }
+        },
+        "storage.BudgetUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 5000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                }
+            }
+        },
+        "storage.BudgetsListResponse": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.Budget"
+                    }
+                }
+            }
+        },
+        "storage.CancelRequest": {
+            "type": "object",
+            "properties": {
+                "effective_date": {
+                    "description": "EffectiveDate - последний оплачиваемый месяц YYYY-MM, по умолчанию текущий",
+                    "type": "string",
+                    "example": "2025-09"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "too expensive"
+                }
+            }
+        },
+        "storage.CategoriesListResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.Category"
+                    }
+                }
+            }
+        },
+        "storage.Category": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "streaming"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "storage.CategoryCreateRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "streaming"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "storage.CategoryUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "video"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "storage.CostGroup": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "streaming"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3000
+                }
+            }
+        },
+        "storage.ForecastMonth": {
+            "type": "object",
+            "properties": {
+                "month": {
+                    "type": "string",
+                    "example": "2025-10"
+                },
+                "subscriptions": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1500
+                }
+            }
+        },
+        "storage.ForecastResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2025-10"
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.ForecastMonth"
+                    }
+                },
+                "renewal": {
+                    "type": "string",
+                    "example": "auto"
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.ForecastSubscription"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-09"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 18000
+                }
+            }
+        },
+        "storage.ForecastSubscription": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "2026-03"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "months": {
+                    "description": "Months - сколько месяцев прогноза подписка действует",
+                    "type": "integer",
+                    "example": 12
+                },
+                "renewal_dates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2026-04"
+                    ]
+                },
+                "renews": {
+                    "description": "Renews - подписка продлевается в пределах прогноза, RenewalDates - месяцы начала новых сроков",
+                    "type": "boolean"
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 6000
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "storage.Member": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount - фиксированная сумма в месяц",
+                    "type": "integer",
+                    "example": 150
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "weight": {
+                    "description": "Weight - вес в делении остатка стоимости после фиксированных сумм",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "storage.MemberRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 150
+                },
+                "weight": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                }
+            }
+        },
+        "storage.MembersListResponse": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.Member"
+                    }
+                }
+            }
+        },
+        "storage.Overlap": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-12"
+                },
+                "from": {
+                    "description": "From..To - общие месяцы, пустой To - пересечение не ограничено",
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "subscription_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12"
+                }
+            }
+        },
+        "storage.OverlapPair": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "other_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "subscription_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "storage.OverlapsReportResponse": {
+            "type": "object",
+            "properties": {
+                "overlaps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.OverlapPair"
+                    }
+                }
+            }
+        },
+        "storage.Pause": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "end_month": {
+                    "description": "EndMonth пустой - пауза до возобновления",
+                    "type": "string",
+                    "example": "2025-11"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "start_month": {
+                    "type": "string",
+                    "example": "2025-09"
+                }
+            }
+        },
+        "storage.PauseRequest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2025-09"
+                },
+                "until": {
+                    "type": "string",
+                    "example": "2025-11"
+                }
+            }
+        },
+        "storage.PausesListResponse": {
+            "type": "object",
+            "properties": {
+                "pauses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.Pause"
+                    }
+                }
+            }
+        },
+        "storage.PricesListResponse": {
+            "type": "object",
+            "properties": {
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.SubscriptionPrice"
+                    }
+                }
+            }
+        },
+        "storage.ReactivateRequest": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "description": "EndDate - новый месяц окончания YYYY-MM; без него сохраняется end_date, если он еще не прошел, иначе обязателен",
+                    "type": "string",
+                    "example": "2026-09"
+                }
+            }
+        },
+        "storage.Renewal": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "new_end_date": {
+                    "type": "string",
+                    "example": "2027-07"
+                },
+                "previous_end_date": {
+                    "type": "string",
+                    "example": "2026-07"
+                },
+                "price": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "renewed_at": {
+                    "type": "string"
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "subscription_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "storage.RenewalsListResponse": {
+            "type": "object",
+            "properties": {
+                "renewals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.Renewal"
+                    }
+                }
+            }
+        },
+        "storage.ResumeRequest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2025-10"
+                }
+            }
+        },
+        "storage.Service": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "netflix hd",
+                        "нетфликс"
+                    ]
+                },
+                "category": {
+                    "type": "string",
+                    "example": "streaming"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "default_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "website": {
+                    "type": "string",
+                    "example": "https://netflix.com"
+                }
+            }
+        },
+        "storage.ServiceCreateRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "netflix hd",
+                        "нетфликс"
+                    ]
+                },
+                "category": {
+                    "type": "string",
+                    "example": "streaming"
+                },
+                "default_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "website": {
+                    "type": "string",
+                    "example": "https://netflix.com"
+                }
+            }
+        },
+        "storage.ServiceNamesResponse": {
+            "type": "object",
+            "properties": {
+                "service_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "storage.ServiceUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "netflix hd",
+                        "нетфликс"
+                    ]
+                },
+                "category": {
+                    "type": "string",
+                    "example": "streaming"
+                },
+                "default_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "website": {
+                    "type": "string",
+                    "example": "https://netflix.com"
+                }
+            }
+        },
+        "storage.ServicesListResponse": {
+            "type": "object",
+            "properties": {
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.Service"
+                    }
+                }
+            }
+        },
+        "storage.SubscriptionCreateRequest": {
+            "type": "object",
+            "required": [
+                "price",
+                "start_date",
+                "user_id"
+            ],
+            "properties": {
+                "auto_renew": {
+                    "type": "boolean"
+                },
+                "category_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2026-07"
+                },
+                "intro_months": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
+                },
+                "intro_price": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 199
+                },
+                "price": {
+                    "type": "integer",
                     "minimum": 1,
                     "example": 500
                 },
+                "renewal_months": {
+                    "type": "integer",
+                    "maximum": 120,
+                    "minimum": 1,
+                    "example": 12
+                },
+                "service_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
                 "service_name": {
                     "type": "string",
                     "example": "Netflix"
@@ -306,6 +4174,20 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2025-07"
                 },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "family",
+                        "work"
+                    ]
+                },
+                "trial_ends_at": {
+                    "type": "string",
+                    "example": "2025-08"
+                },
                 "user_id": {
                     "type": "string",
                     "format": "uuid",
@@ -313,9 +4195,46 @@ const docTemplate = `{
                 }
             }
         },
+        "storage.SubscriptionPrice": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string",
+                    "example": "2025-09"
+                },
+                "price": {
+                    "type": "integer",
+                    "example": 600
+                }
+            }
+        },
         "storage.SubscriptionR": {
             "type": "object",
             "properties": {
+                "auto_renew": {
+                    "type": "boolean"
+                },
+                "cancel_reason": {
+                    "type": "string",
+                    "example": "too expensive"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "conflicts": {
+                    "description": "Conflicts - пересекающиеся подписки, в ответ на запись с ConflictPolicyWarn",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.Overlap"
+                    }
+                },
                 "end_date": {
                     "type": "string",
                     "example": "2026-07"
@@ -325,10 +4244,26 @@ const docTemplate = `{
                     "format": "uuid",
                     "example": "550e8400-e29b-41d4-a716-446655440090"
                 },
+                "intro_months": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "intro_price": {
+                    "type": "integer",
+                    "example": 199
+                },
                 "price": {
                     "type": "integer",
                     "example": 500
                 },
+                "renewal_months": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "service_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
                 "service_name": {
                     "type": "string",
                     "example": "Netflix"
@@ -337,6 +4272,26 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2025-07"
                 },
+                "status": {
+                    "description": "Status - trial, active, paused, cancelled или expired",
+                    "type": "string",
+                    "example": "active"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "family",
+                        "work"
+                    ]
+                },
+                "trial_ends_at": {
+                    "description": "TrialEndsAt - последний месяц пробного периода, пустой - пробного периода нет",
+                    "type": "string",
+                    "example": "2025-08"
+                },
                 "user_id": {
                     "type": "string",
                     "format": "uuid",
@@ -344,17 +4299,215 @@ const docTemplate = `{
                 }
             }
         },
+        "storage.SubscriptionTagsRequest": {
+            "type": "object",
+            "required": [
+                "tags"
+            ],
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "family",
+                        "work"
+                    ]
+                }
+            }
+        },
+        "storage.SubscriptionsListResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "NextCursor - значение after для следующей страницы; есть, только если страница заполнена до limit и нет q",
+                    "type": "string",
+                    "example": "MjAyNS0wMS0wMXw1NTBlODQwMC1lMjliLTQxZDQtYTcxNi00NDY2NTU0NDAyMDA"
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.SubscriptionR"
+                    }
+                }
+            }
+        },
+        "storage.Tag": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "family"
+                },
+                "subscriptions": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "storage.TagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "family"
+                }
+            }
+        },
+        "storage.TagsListResponse": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.Tag"
+                    }
+                }
+            }
+        },
+        "storage.TimeSeriesGroup": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "key": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440300"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "streaming"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1000
+                }
+            }
+        },
+        "storage.TimeSeriesPoint": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "groups": {
+                    "description": "Groups заполняется только при group_by",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.TimeSeriesGroup"
+                    }
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1500
+                }
+            }
+        },
+        "storage.TimeSeriesResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "group_by": {
+                    "type": "string",
+                    "example": "category"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.TimeSeriesPoint"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12"
+                }
+            }
+        },
+        "storage.TotalCostResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "groups": {
+                    "description": "Groups заполняется только при group_by",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.CostGroup"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 6000
+                }
+            }
+        },
         "storage.UpdateSubscriptionRequest": {
             "type": "object",
             "properties": {
+                "auto_renew": {
+                    "type": "boolean"
+                },
+                "category_id": {
+                    "description": "CategoryID - нулевой UUID снимает категорию",
+                    "type": "string",
+                    "format": "uuid"
+                },
                 "end_date": {
                     "type": "string",
                     "example": "2026-07"
                 },
+                "intro_months": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "intro_price": {
+                    "description": "IntroPrice - 0 убирает вступительную цену вместе с intro_months",
+                    "type": "integer",
+                    "example": 199
+                },
                 "price": {
                     "type": "integer",
                     "example": 500
                 },
+                "price_effective_from": {
+                    "description": "PriceEffectiveFrom - месяц YYYY-MM, с которого действует новая price (по умолчанию текущий)",
+                    "type": "string",
+                    "example": "2025-09"
+                },
+                "renewal_months": {
+                    "type": "integer",
+                    "maximum": 120,
+                    "minimum": 1,
+                    "example": 12
+                },
+                "service_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
                 "service_name": {
                     "type": "string",
                     "example": "Netflix"
@@ -362,6 +4515,99 @@ const docTemplate = `{
                 "start_date": {
                     "type": "string",
                     "example": "2025-07"
+                },
+                "trial_ends_at": {
+                    "description": "TrialEndsAt - пустая строка убирает пробный период",
+                    "type": "string",
+                    "example": "2025-08"
+                }
+            }
+        },
+        "storage.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "default_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655240000"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Moscow"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "storage.UserCreateRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "default_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Moscow"
+                }
+            }
+        },
+        "storage.UserUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "default_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Иван Петров"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Moscow"
+                }
+            }
+        },
+        "storage.UsersListResponse": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.User"
+                    }
                 }
             }
         }
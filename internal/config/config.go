@@ -2,6 +2,7 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -13,17 +14,64 @@ import (
 type Config struct {
 	Environment string `yaml:"environment" env-required:"true"`
 	HTTPServer  `yaml:"http_server"`
+	GRPCServer  `yaml:"grpc_server"`
 	Storage     `yaml:"storage"`
+	Reminders   `yaml:"reminders"`
+	Renewals    `yaml:"renewals"`
+	Sweeper     `yaml:"status_sweeper"`
+	Notifier    `yaml:"notifier"`
 }
 
 type HTTPServer struct {
 	Address     string        `yaml:"address" env-default:"localhost:8080"`
 	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
+	// даты (YYYY-MM-DD) для заголовков Deprecation и Sunset на старых маршрутах
+	LegacyDeprecatedSince string `yaml:"legacy_deprecated_since" env-default:"2026-10-18"`
+	LegacySunset          string `yaml:"legacy_sunset" env-default:"2027-04-01"`
 	/* User string `yaml:"user" env-required:"true"`
 	Password string `yaml:"password" env-required:"true" env:"HTTP_SERVER_PASSWORD"` */
 }
 
+type GRPCServer struct {
+	GRPCAddress string `yaml:"address" env-default:"localhost:9090"`
+}
+
+// Reminders - напоминания об окончании подписок
+type Reminders struct {
+	RemindersEnabled  bool          `yaml:"enabled" env-default:"true"`
+	RemindersInterval time.Duration `yaml:"interval" env-default:"1h"`
+	// за сколько дней до окончания напоминать
+	ReminderWindows []int `yaml:"windows" env-default:"7,1"`
+}
+
+// Renewals - автопродление подписок с auto_renew
+type Renewals struct {
+	RenewalsEnabled  bool          `yaml:"enabled" env-default:"true"`
+	RenewalsInterval time.Duration `yaml:"interval" env-default:"24h"`
+}
+
+// Sweeper - перевод подписок в статус по их датам: истечение, начало и конец пробного периода и пауз
+type Sweeper struct {
+	SweeperEnabled  bool          `yaml:"enabled" env-default:"true"`
+	SweeperInterval time.Duration `yaml:"interval" env-default:"1h"`
+}
+
+// Notifier - канал доставки уведомлений: log, webhook или smtp
+type Notifier struct {
+	NotifierType string `yaml:"type" env-default:"log"`
+	WebhookURL   string `yaml:"webhook_url"`
+	SMTP         SMTP   `yaml:"smtp"`
+}
+
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port" env-default:"25"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
 type Storage struct {
 	User     string `yaml:"user" env-required:"true"`
 	Password string `yaml:"password" env-required:"true"`
@@ -31,6 +79,14 @@ type Storage struct {
 	Port     int    `yaml:"port" env-required:"true"`
 	DBName   string `yaml:"db_name" env-required:"true"`
 	SSLMode  string `yaml:"sslmode" env-required:"true"`
+	// EventSourced - подписки записываются событиями в журнал, таблица subscriptions - его проекция
+	EventSourced bool `yaml:"event_sourced" env-default:"false"`
+}
+
+// DSN - строка подключения к PostgreSQL
+func (s Storage) DSN() string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
+		s.User, s.Password, s.Host, s.Port, s.DBName, s.SSLMode)
 }
 
 func MustLoad() *Config {
@@ -0,0 +1,442 @@
+// Package grpcserver exposes the subscriptions storage over gRPC
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+
+	"github.com/google/uuid"
+	subscriptionsv1 "github.com/odlev/subscriptions/api/subscriptions/v1"
+	"github.com/odlev/subscriptions/internal/handlers"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// Server реализует subscriptionsv1.SubscriptionServiceServer поверх того же DataWizard, что и HTTP-обработчики
+type Server struct {
+	subscriptionsv1.UnimplementedSubscriptionServiceServer
+
+	log        *slog.Logger
+	dataWizard handlers.DataWizard
+}
+
+func New(log *slog.Logger, dataWizard handlers.DataWizard) *Server {
+	return &Server{log: log, dataWizard: dataWizard}
+}
+
+// Run регистрирует сервис подписок, health и reflection и запускает сервер в отдельной горутине.
+// Ошибка запуска или работы сервера приходит в возвращаемый канал
+func Run(log *slog.Logger, address string, dataWizard handlers.DataWizard) (*grpc.Server, <-chan error) {
+	const op = "grpcserver.Run"
+
+	grpcServer := grpc.NewServer()
+
+	subscriptionsv1.RegisterSubscriptionServiceServer(grpcServer, New(log, dataWizard))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(subscriptionsv1.SubscriptionService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	reflection.Register(grpcServer)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+
+		lis, err := net.Listen("tcp", address)
+		if err != nil {
+			errCh <- fmt.Errorf("%s: %w", op, err)
+			return
+		}
+
+		log.Info("gRPC server started", "address", address)
+		if err := grpcServer.Serve(lis); err != nil {
+			errCh <- fmt.Errorf("%s: %w", op, err)
+		}
+	}()
+
+	return grpcServer, errCh
+}
+
+func (s *Server) CreateSubscription(_ context.Context, req *subscriptionsv1.CreateSubscriptionRequest) (*subscriptionsv1.CreateSubscriptionResponse, error) {
+	if (req.GetServiceName() == "" && req.ServiceId == nil) || req.GetPrice() < 1 || req.GetStartDate() == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_name or service_id, price and start_date are required")
+	}
+	serviceID, err := parseOptionalID(req.ServiceId, "service_id")
+	if err != nil {
+		return nil, err
+	}
+	categoryID, err := parseOptionalID(req.CategoryId, "category_id")
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &storage.SubscriptionR{
+		ServiceID:      serviceID,
+		ServiceName:    req.GetServiceName(),
+		Price:          int(req.GetPrice()),
+		StartDate:      req.GetStartDate(),
+		EndDate:        req.GetEndDate(),
+		CategoryID:     categoryID,
+		Tags:           req.GetTags(),
+		AutoRenew:      req.GetAutoRenew(),
+		RenewalMonths:  int(req.GetRenewalMonths()),
+		TrialEndsAt:    req.GetTrialEndsAt(),
+		IntroPrice:     int(req.GetIntroPrice()),
+		IntroMonths:    int(req.GetIntroMonths()),
+		ConflictPolicy: req.GetConflictPolicy(),
+	}
+	if req.UserId != nil {
+		userID, err := uuid.Parse(req.GetUserId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+		}
+		sub.UserID = userID
+	}
+
+	id, err := s.dataWizard.CreateSubscription(sub)
+	if err != nil {
+		return nil, s.toStatus("failed to create new subscription", err)
+	}
+
+	created, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscriptionsv1.CreateSubscriptionResponse{Subscription: created}, nil
+}
+
+func (s *Server) GetSubscription(_ context.Context, req *subscriptionsv1.GetSubscriptionRequest) (*subscriptionsv1.GetSubscriptionResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscriptionsv1.GetSubscriptionResponse{Subscription: sub}, nil
+}
+
+func (s *Server) ListSubscriptions(_ context.Context, req *subscriptionsv1.ListSubscriptionsRequest) (*subscriptionsv1.ListSubscriptionsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return nil, status.Error(codes.InvalidArgument, "page_size can not be negative")
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	// страницы идут по (start_date, id): page_token - курсор storage.EncodeCursor последней подписки,
+	// лишняя подписка сверх page_size показывает, что есть следующая страница
+	filter := storage.SubscriptionFilter{
+		UserID:     req.GetUserId(),
+		CategoryID: req.GetCategoryId(),
+		Tags:       req.GetTags(),
+		Statuses:   req.GetStatuses(),
+		Limit:      strconv.Itoa(pageSize + 1),
+		After:      req.GetPageToken(),
+	}
+	if req.GetServiceName() != "" {
+		filter.ServiceNames = []string{req.GetServiceName()}
+	}
+	subs, err := s.dataWizard.GetListSubscriptions(filter)
+	if err != nil {
+		var filterErr *storage.FilterError
+		if errors.As(err, &filterErr) {
+			return nil, status.Error(codes.InvalidArgument, pageTokenError(filterErr).Error())
+		}
+		return nil, s.toStatus("error getting list subscriptions", err)
+	}
+
+	resp := &subscriptionsv1.ListSubscriptionsResponse{}
+	if len(subs) > pageSize {
+		subs = subs[:pageSize]
+		resp.NextPageToken = storage.EncodeCursor(subs[len(subs)-1])
+	}
+	for i := range subs {
+		resp.Subscriptions = append(resp.Subscriptions, toProto(&subs[i]))
+	}
+
+	return resp, nil
+}
+
+func (s *Server) UpdateSubscription(_ context.Context, req *subscriptionsv1.UpdateSubscriptionRequest) (*subscriptionsv1.UpdateSubscriptionResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if req.Price != nil && req.GetPrice() < 1 {
+		return nil, status.Error(codes.InvalidArgument, "price must be positive")
+	}
+
+	serviceID, err := parseOptionalID(req.ServiceId, "service_id")
+	if err != nil {
+		return nil, err
+	}
+
+	update := storage.UpdateSubscriptionRequest{
+		ServiceID:          serviceID,
+		ServiceName:        req.GetServiceName(),
+		Price:              int(req.GetPrice()),
+		StartDate:          req.GetStartDate(),
+		EndDate:            req.GetEndDate(),
+		AutoRenew:          req.AutoRenew,
+		PriceEffectiveFrom: req.GetPriceEffectiveFrom(),
+		ConflictPolicy:     req.GetConflictPolicy(),
+	}
+	if req.RenewalMonths != nil {
+		if req.GetRenewalMonths() < 1 {
+			return nil, status.Error(codes.InvalidArgument, myerrors.ErrInvalidRenewalTerm.Error())
+		}
+		update.RenewalMonths = int(req.GetRenewalMonths())
+	}
+	update.TrialEndsAt = req.TrialEndsAt
+	if req.IntroPrice != nil {
+		introPrice := int(req.GetIntroPrice())
+		update.IntroPrice = &introPrice
+	}
+	if req.IntroMonths != nil {
+		introMonths := int(req.GetIntroMonths())
+		update.IntroMonths = &introMonths
+	}
+	// пустая строка снимает категорию, в слое хранения это нулевой UUID
+	if req.CategoryId != nil {
+		categoryID := uuid.Nil
+		if req.GetCategoryId() != "" {
+			categoryID, err = uuid.Parse(req.GetCategoryId())
+			if err != nil {
+				return nil, status.Error(codes.InvalidArgument, "invalid category_id")
+			}
+		}
+		update.CategoryID = &categoryID
+	}
+
+	if err := s.dataWizard.UpdateSubscription(id, update); err != nil {
+		return nil, s.toStatus("update error", err)
+	}
+
+	sub, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscriptionsv1.UpdateSubscriptionResponse{Subscription: sub}, nil
+}
+
+func (s *Server) ReplaceSubscription(_ context.Context, req *subscriptionsv1.ReplaceSubscriptionRequest) (*subscriptionsv1.ReplaceSubscriptionResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if (req.GetServiceName() == "" && req.ServiceId == nil) || req.GetPrice() < 1 || req.GetStartDate() == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_name or service_id, price and start_date are required")
+	}
+	serviceID, err := parseOptionalID(req.ServiceId, "service_id")
+	if err != nil {
+		return nil, err
+	}
+	categoryID, err := parseOptionalID(req.CategoryId, "category_id")
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &storage.SubscriptionR{
+		ServiceID:      serviceID,
+		ServiceName:    req.GetServiceName(),
+		Price:          int(req.GetPrice()),
+		StartDate:      req.GetStartDate(),
+		EndDate:        req.GetEndDate(),
+		CategoryID:     categoryID,
+		AutoRenew:      req.GetAutoRenew(),
+		RenewalMonths:  int(req.GetRenewalMonths()),
+		TrialEndsAt:    req.GetTrialEndsAt(),
+		IntroPrice:     int(req.GetIntroPrice()),
+		IntroMonths:    int(req.GetIntroMonths()),
+		ConflictPolicy: req.GetConflictPolicy(),
+	}
+	if req.UserId != nil {
+		userID, err := uuid.Parse(req.GetUserId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+		}
+		sub.UserID = userID
+	}
+
+	if err := s.dataWizard.ReplaceSubscription(id, sub); err != nil {
+		return nil, s.toStatus("replace error", err)
+	}
+
+	replaced, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscriptionsv1.ReplaceSubscriptionResponse{Subscription: replaced}, nil
+}
+
+func (s *Server) DeleteSubscription(_ context.Context, req *subscriptionsv1.DeleteSubscriptionRequest) (*subscriptionsv1.DeleteSubscriptionResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName, err := s.dataWizard.DeleteSubscription(id)
+	if err != nil {
+		return nil, s.toStatus("failed to delete", err)
+	}
+
+	return &subscriptionsv1.DeleteSubscriptionResponse{ServiceName: serviceName}, nil
+}
+
+func (s *Server) get(id uuid.UUID) (*subscriptionsv1.Subscription, error) {
+	sub, err := s.dataWizard.GetSubscription(id)
+	if err != nil {
+		return nil, s.toStatus("failed to get", err)
+	}
+
+	return toProto(sub), nil
+}
+
+// toStatus логирует ошибку слоя хранения и переводит ее в gRPC-статус
+func (s *Server) toStatus(msg string, err error) error {
+	s.log.Error(msg, sl.Err(err))
+
+	switch {
+	case errors.Is(err, myerrors.ErrNotFound):
+		return status.Error(codes.NotFound, "subscription not found")
+	case errors.Is(err, myerrors.ErrInvalidDateRange):
+		return status.Error(codes.InvalidArgument, myerrors.ErrInvalidDateRange.Error())
+	case errors.Is(err, myerrors.ErrUserRequired):
+		return status.Error(codes.InvalidArgument, myerrors.ErrUserRequired.Error())
+	case errors.Is(err, myerrors.ErrUnknownUser):
+		return status.Error(codes.InvalidArgument, myerrors.ErrUnknownUser.Error())
+	case errors.Is(err, myerrors.ErrServiceRequired):
+		return status.Error(codes.InvalidArgument, myerrors.ErrServiceRequired.Error())
+	case errors.Is(err, myerrors.ErrUnknownService):
+		return status.Error(codes.InvalidArgument, myerrors.ErrUnknownService.Error())
+	case errors.Is(err, myerrors.ErrUnknownCategory):
+		return status.Error(codes.InvalidArgument, myerrors.ErrUnknownCategory.Error())
+	case errors.Is(err, myerrors.ErrInvalidTag):
+		return status.Error(codes.InvalidArgument, myerrors.ErrInvalidTag.Error())
+	case errors.Is(err, myerrors.ErrInvalidRenewalTerm):
+		return status.Error(codes.InvalidArgument, myerrors.ErrInvalidRenewalTerm.Error())
+	case errors.Is(err, myerrors.ErrInvalidTrial):
+		return status.Error(codes.InvalidArgument, myerrors.ErrInvalidTrial.Error())
+	case errors.Is(err, myerrors.ErrInvalidIntro):
+		return status.Error(codes.InvalidArgument, myerrors.ErrInvalidIntro.Error())
+	case errors.Is(err, myerrors.ErrInvalidStatus):
+		return status.Error(codes.InvalidArgument, myerrors.ErrInvalidStatus.Error())
+	case errors.Is(err, myerrors.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, myerrors.ErrInvalidTransition.Error())
+	case errors.Is(err, myerrors.ErrSubscriptionOverlap):
+		return status.Error(codes.AlreadyExists, myerrors.ErrSubscriptionOverlap.Error())
+	case errors.Is(err, myerrors.ErrInvalidConflictPolicy):
+		return status.Error(codes.InvalidArgument, myerrors.ErrInvalidConflictPolicy.Error())
+	case errors.Is(err, myerrors.ErrProjectionDrift):
+		return status.Error(codes.FailedPrecondition, myerrors.ErrProjectionDrift.Error())
+	case errors.Is(err, myerrors.ErrInvalidRequest):
+		return status.Error(codes.InvalidArgument, "invalid request")
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+
+func parseID(strID string) (uuid.UUID, error) {
+	id, err := uuid.Parse(strID)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "failed to parse id")
+	}
+
+	return id, nil
+}
+
+// parseOptionalID разбирает необязательное поле-UUID запроса
+func parseOptionalID(value *string, field string) (*uuid.UUID, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(*value)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid "+field)
+	}
+
+	return &id, nil
+}
+
+func toProto(sub *storage.Subscription) *subscriptionsv1.Subscription {
+	var serviceID, categoryID, trialEndsAt string
+	if sub.ServiceID != nil {
+		serviceID = sub.ServiceID.String()
+	}
+	if sub.CategoryID != nil {
+		categoryID = sub.CategoryID.String()
+	}
+	if sub.TrialEndsAt != nil {
+		trialEndsAt = sub.TrialEndsAt.Format(storage.DateLayout)
+	}
+	var introPrice, introMonths int
+	if sub.IntroPrice != nil && sub.IntroMonths != nil {
+		introPrice, introMonths = *sub.IntroPrice, *sub.IntroMonths
+	}
+	var cancelReason string
+	if sub.CancelReason != nil {
+		cancelReason = *sub.CancelReason
+	}
+
+	return &subscriptionsv1.Subscription{
+		ServiceId:     serviceID,
+		CategoryId:    categoryID,
+		Tags:          sub.Tags,
+		AutoRenew:     sub.AutoRenew,
+		RenewalMonths: int32(sub.RenewalMonths),
+		TrialEndsAt:   trialEndsAt,
+		IntroPrice:    int64(introPrice),
+		IntroMonths:   int32(introMonths),
+		Status:        sub.Status,
+		CancelReason:  cancelReason,
+		Id:            sub.ID.String(),
+		ServiceName:   sub.ServiceName,
+		Price:         int64(sub.Price),
+		UserId:        sub.UserID.String(),
+		StartDate:     sub.StartDate.Format(storage.DateLayout),
+		EndDate:       sub.EndDate.Format(storage.DateLayout),
+	}
+}
+
+// pageTokenError называет ошибку курсора по имени поля запроса page_token, а не query-параметра after
+func pageTokenError(err *storage.FilterError) *storage.FilterError {
+	renamed := &storage.FilterError{Params: make([]storage.ParamError, len(err.Params))}
+	for i, p := range err.Params {
+		if p.Param == "after" {
+			p.Param, p.Message = "page_token", "invalid page_token"
+		}
+		renamed.Params[i] = p
+	}
+
+	return renamed
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+type AggregateWizard interface {
+	Aggregate(filter storage.SubscriptionFilter, groupBy, metrics []string) (*storage.AggregateResponse, error)
+}
+
+// listParam возвращает значения параметра, переданного несколько раз или через запятую
+func listParam(c *gin.Context, name string) []string {
+	var values []string
+	for _, value := range c.QueryArray(name) {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+
+	return values
+}
+
+// AggregateV1 godoc
+// @Summary Агрегация подписок
+// @Description Считает метрики по подпискам с фильтрами списка одним запросом в разрезе group_by.
+// @Description group_by: service, user, category (category_id и category_name), start_month - можно несколько; без него одна строка по всем подпискам.
+// @Description metrics: count, sum_price, avg_price, min_price, max_price (цена в текущем месяце), по умолчанию count.
+// @Description Ответ - таблица: columns с именами и типами (string, uuid, month, integer, number) и rows со значениями в том же порядке.
+// @Tags subscriptions v1
+// @Produce json
+// @Param group_by query []string false "Группировка, через запятую или несколько раз" collectionFormat(csv) Enums(service, user, category, start_month)
+// @Param metrics query []string false "Метрики, через запятую или несколько раз" collectionFormat(csv) Enums(count, sum_price, avg_price, min_price, max_price)
+// @Param user_id query string false "ID пользователя для фильтрации" format(uuid)
+// @Param service_name query []string false "Название сервиса, можно указать несколько раз" collectionFormat(multi)
+// @Param category_id query string false "ID категории, включая дочерние" format(uuid)
+// @Param tag query []string false "Тег, можно указать несколько раз" collectionFormat(multi)
+// @Param status query []string false "Статус, можно указать несколько раз" collectionFormat(multi)
+// @Param active_at query string false "Подписка действует и не на паузе в месяце YYYY-MM" example(2025-03)
+// @Param filter query string false "Выражение фильтра, как у списка" example(price > 500)
+// @Success 200 {object} storage.AggregateResponse "Таблица агрегации"
+// @Failure 400 {object} map[string]any "Некорректные параметры" example({"error": "unsupported group_by"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/aggregate [get]
+func AggregateV1(log *slog.Logger, aggregateWizard AggregateWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := aggregateWizard.Aggregate(subscriptionFilter(c), listParam(c, "group_by"), listParam(c, "metrics"))
+		if err != nil {
+			log.Error("error aggregating subscriptions", sl.Err(err))
+			writeFilterError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// BudgetsV1Path - базовый путь ресурса бюджетов
+const BudgetsV1Path = "/api/v1/budgets"
+
+type BudgetWizard interface {
+	CreateBudget(req storage.BudgetCreateRequest) (*storage.Budget, error)
+	GetBudget(id uuid.UUID) (*storage.Budget, error)
+	ListBudgets(userID string) ([]storage.Budget, error)
+	UpdateBudget(id uuid.UUID, req storage.BudgetUpdateRequest) (*storage.Budget, error)
+	DeleteBudget(id uuid.UUID) error
+	GetBudgetStatus(id uuid.UUID, month string) (*storage.BudgetStatus, error)
+}
+
+// CreateBudget godoc
+// @Summary Создать бюджет
+// @Description Создает месячный бюджет пользователя на все подписки или на категорию (вместе с дочерними)
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param input body storage.BudgetCreateRequest true "Данные бюджета"
+// @Success 201 {object} storage.Budget "Бюджет создан"
+// @Header 201 {string} Location "/api/v1/budgets/{id}"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "user_id does not reference an existing user"})
+// @Failure 409 {object} map[string]any "Бюджет уже есть" example({"error": "budget for this user and category already exists"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/budgets [post]
+func CreateBudget(log *slog.Logger, budgetWizard BudgetWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req storage.BudgetCreateRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		budget, err := budgetWizard.CreateBudget(req)
+		if err != nil {
+			log.Error("failed to create budget", sl.Err(err))
+			writeBudgetError(c, err)
+
+			return
+		}
+
+		log.Info("new budget created", slog.Any("budget id", budget.ID))
+		c.Header("Location", BudgetsV1Path+"/"+budget.ID.String())
+		c.JSON(http.StatusCreated, budget)
+	}
+}
+
+// ListBudgets godoc
+// @Summary Получить список бюджетов
+// @Tags budgets
+// @Produce json
+// @Param user_id query string false "ID пользователя для фильтрации" format(uuid)
+// @Success 200 {object} storage.BudgetsListResponse "Список бюджетов"
+// @Failure 400 {object} map[string]any "Неверный user_id" example({"error": "invalid user_id"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/budgets [get]
+func ListBudgets(log *slog.Logger, budgetWizard BudgetWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		budgets, err := budgetWizard.ListBudgets(c.Query("user_id"))
+		if err != nil {
+			log.Error("failed to list budgets", sl.Err(err))
+			writeBudgetError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.BudgetsListResponse{Budgets: budgets})
+	}
+}
+
+// GetBudget godoc
+// @Summary Получить бюджет по ID
+// @Tags budgets
+// @Produce json
+// @Param id path string true "ID бюджета" format(uuid)
+// @Success 200 {object} storage.Budget "Бюджет"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Бюджет не найден" example({"error": "budget not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/budgets/{id} [get]
+func GetBudget(log *slog.Logger, budgetWizard BudgetWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		budget, err := budgetWizard.GetBudget(id)
+		if err != nil {
+			log.Error("failed to get budget", sl.Err(err))
+			writeBudgetError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, budget)
+	}
+}
+
+// UpdateBudget godoc
+// @Summary Обновить бюджет
+// @Description Меняет сумму и/или валюту бюджета
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param id path string true "ID бюджета" format(uuid)
+// @Param input body storage.BudgetUpdateRequest true "Данные для обновления"
+// @Success 200 {object} storage.Budget "Бюджет обновлен"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "failed to decode request body"})
+// @Failure 404 {object} map[string]any "Бюджет не найден" example({"error": "budget not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/budgets/{id} [patch]
+func UpdateBudget(log *slog.Logger, budgetWizard BudgetWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.BudgetUpdateRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		budget, err := budgetWizard.UpdateBudget(id, req)
+		if err != nil {
+			log.Error("failed to update budget", sl.Err(err))
+			writeBudgetError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, budget)
+	}
+}
+
+// DeleteBudget godoc
+// @Summary Удалить бюджет
+// @Tags budgets
+// @Param id path string true "ID бюджета" format(uuid)
+// @Success 204 "Бюджет удален"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Бюджет не найден" example({"error": "budget not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/budgets/{id} [delete]
+func DeleteBudget(log *slog.Logger, budgetWizard BudgetWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		if err := budgetWizard.DeleteBudget(id); err != nil {
+			log.Error("failed to delete budget", sl.Err(err))
+			writeBudgetError(c, err)
+
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetBudgetStatus godoc
+// @Summary Состояние бюджета
+// @Description Возвращает расход за месяц (подписки, действующие в этом месяце), прогноз на следующий месяц, достигнутый порог (80 или 100%) и оповещения, отправленные за месяц
+// @Tags budgets
+// @Produce json
+// @Param id path string true "ID бюджета" format(uuid)
+// @Param month query string false "Месяц YYYY-MM, по умолчанию текущий в часовом поясе пользователя" example(2025-07)
+// @Success 200 {object} storage.BudgetStatus "Состояние бюджета"
+// @Failure 400 {object} map[string]any "Неверный месяц" example({"error": "invalid month"})
+// @Failure 404 {object} map[string]any "Бюджет не найден" example({"error": "budget not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/budgets/{id}/status [get]
+func GetBudgetStatus(log *slog.Logger, budgetWizard BudgetWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		status, err := budgetWizard.GetBudgetStatus(id, c.Query("month"))
+		if err != nil {
+			log.Error("failed to get budget status", sl.Err(err))
+			writeBudgetError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+func writeBudgetError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, myerrors.ErrBudgetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": myerrors.ErrBudgetNotFound.Error()})
+	case errors.Is(err, myerrors.ErrBudgetExists):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrBudgetExists.Error()})
+	case errors.Is(err, myerrors.ErrUnknownUser):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrUnknownUser.Error()})
+	case errors.Is(err, myerrors.ErrUnknownCategory):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrUnknownCategory.Error()})
+	case strings.Contains(err.Error(), "failed parse id"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+	case strings.Contains(err.Error(), "invalid month"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid month"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// CategoriesV1Path - базовый путь ресурса категорий
+const CategoriesV1Path = "/api/v1/categories"
+
+type CategoryWizard interface {
+	CreateCategory(req storage.CategoryCreateRequest) (*storage.Category, error)
+	GetCategory(id uuid.UUID) (*storage.Category, error)
+	ListCategories() ([]storage.Category, error)
+	UpdateCategory(id uuid.UUID, req storage.CategoryUpdateRequest) (*storage.Category, error)
+	DeleteCategory(id uuid.UUID) error
+}
+
+// CreateCategory godoc
+// @Summary Создать категорию
+// @Description Создает категорию. Без parent_id категория корневая.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param input body storage.CategoryCreateRequest true "Данные категории"
+// @Success 201 {object} storage.Category "Категория создана"
+// @Header 201 {string} Location "/api/v1/categories/{id}"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "category_id does not reference an existing category"})
+// @Failure 409 {object} map[string]any "Имя занято" example({"error": "category with this name already exists under the same parent"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/categories [post]
+func CreateCategory(log *slog.Logger, categoryWizard CategoryWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req storage.CategoryCreateRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		category, err := categoryWizard.CreateCategory(req)
+		if err != nil {
+			log.Error("failed to create category", sl.Err(err))
+			writeCategoryError(c, err)
+
+			return
+		}
+
+		log.Info("new category created", slog.Any("category id", category.ID))
+		c.Header("Location", CategoriesV1Path+"/"+category.ID.String())
+		c.JSON(http.StatusCreated, category)
+	}
+}
+
+// ListCategories godoc
+// @Summary Получить список категорий
+// @Description Возвращает все категории плоским списком, иерархия задается полем parent_id
+// @Tags categories
+// @Produce json
+// @Success 200 {object} storage.CategoriesListResponse "Список категорий"
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/categories [get]
+func ListCategories(log *slog.Logger, categoryWizard CategoryWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		categories, err := categoryWizard.ListCategories()
+		if err != nil {
+			log.Error("failed to list categories", sl.Err(err))
+			writeCategoryError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.CategoriesListResponse{Categories: categories})
+	}
+}
+
+// GetCategory godoc
+// @Summary Получить категорию по ID
+// @Tags categories
+// @Produce json
+// @Param id path string true "ID категории" format(uuid)
+// @Success 200 {object} storage.Category "Категория"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Категория не найдена" example({"error": "category not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/categories/{id} [get]
+func GetCategory(log *slog.Logger, categoryWizard CategoryWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		category, err := categoryWizard.GetCategory(id)
+		if err != nil {
+			log.Error("failed to get category", sl.Err(err))
+			writeCategoryError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, category)
+	}
+}
+
+// UpdateCategory godoc
+// @Summary Обновить категорию
+// @Description Переименовывает категорию и/или переносит ее под другого родителя. Нулевой UUID в parent_id делает категорию корневой.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "ID категории" format(uuid)
+// @Param input body storage.CategoryUpdateRequest true "Данные для обновления"
+// @Success 200 {object} storage.Category "Категория обновлена"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "category can not be moved under itself or its descendant"})
+// @Failure 404 {object} map[string]any "Категория не найдена" example({"error": "category not found"})
+// @Failure 409 {object} map[string]any "Имя занято" example({"error": "category with this name already exists under the same parent"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/categories/{id} [patch]
+func UpdateCategory(log *slog.Logger, categoryWizard CategoryWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.CategoryUpdateRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		category, err := categoryWizard.UpdateCategory(id, req)
+		if err != nil {
+			log.Error("failed to update category", sl.Err(err))
+			writeCategoryError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, category)
+	}
+}
+
+// DeleteCategory godoc
+// @Summary Удалить категорию
+// @Description Удаляет категорию. Подписки категории остаются без категории, категорию с дочерними удалить нельзя.
+// @Tags categories
+// @Param id path string true "ID категории" format(uuid)
+// @Success 204 "Категория удалена"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Категория не найдена" example({"error": "category not found"})
+// @Failure 409 {object} map[string]any "Есть дочерние категории" example({"error": "category still has child categories"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/categories/{id} [delete]
+func DeleteCategory(log *slog.Logger, categoryWizard CategoryWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		if err := categoryWizard.DeleteCategory(id); err != nil {
+			log.Error("failed to delete category", sl.Err(err))
+			writeCategoryError(c, err)
+
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func writeCategoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, myerrors.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": myerrors.ErrCategoryNotFound.Error()})
+	case errors.Is(err, myerrors.ErrCategoryExists):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrCategoryExists.Error()})
+	case errors.Is(err, myerrors.ErrCategoryHasChildren):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrCategoryHasChildren.Error()})
+	case errors.Is(err, myerrors.ErrUnknownCategory):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrUnknownCategory.Error()})
+	case errors.Is(err, myerrors.ErrCategoryCycle):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrCategoryCycle.Error()})
+	case errors.Is(err, myerrors.ErrProjectionDrift):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrProjectionDrift.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
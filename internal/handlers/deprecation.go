@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated помечает устаревшие маршруты заголовками Deprecation и Sunset (RFC 9745, RFC 8594)
+// и указывает на замену через Link с rel="successor-version"
+func Deprecated(since, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(since.Unix(), 10)
+	sunsetHeader := sunset.UTC().Format(http.TimeFormat)
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetHeader)
+		if successor != "" {
+			c.Header("Link", "<"+successor+">; rel=\"successor-version\"")
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// defaultForecastMonths - горизонт прогноза, если months не указан
+const defaultForecastMonths = 12
+
+type ForecastWizard interface {
+	GetForecast(filter storage.SubscriptionFilter, from string, months int, renewal string) (*storage.ForecastResponse, error)
+}
+
+// ForecastV1 godoc
+// @Summary Прогноз расходов
+// @Description Прогнозирует расходы на months месяцев начиная с from по неистекшим подпискам с теми же фильтрами, что у списка.
+// @Description Месяц стоит столько же, сколько в /total: с пробным периодом, вступительной ценой, паузами и ценой, действующей в этом месяце.
+// @Description После end_date подписка продолжается, только если продлевается: renewal=auto - подписки с auto_renew, all - все неотмененные, none - никакие.
+// @Description Возвращает итоги по месяцам и вклад каждой подписки с месяцами продления.
+// @Tags subscriptions v1
+// @Produce json
+// @Param from query string false "Первый месяц прогноза YYYY-MM, по умолчанию текущий" example(2025-10)
+// @Param months query int false "Горизонт в месяцах, от 1 до 120 (по умолчанию 12)" example(12)
+// @Param renewal query string false "Режим продления (по умолчанию auto)" Enums(auto, all, none)
+// @Param user_id query string false "ID пользователя для фильтрации" format(uuid)
+// @Param service_name query string false "Название сервиса для фильтрации" example(Netflix)
+// @Param category_id query string false "ID категории, включая дочерние" format(uuid)
+// @Param tag query []string false "Тег, можно указать несколько раз" collectionFormat(multi)
+// @Param status query []string false "Статус, можно указать несколько раз" collectionFormat(multi)
+// @Success 200 {object} storage.ForecastResponse "Прогноз"
+// @Failure 400 {object} map[string]any "Некорректные параметры" example({"error": "months must be between 1 and 120"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/forecast [get]
+func ForecastV1(log *slog.Logger, forecastWizard ForecastWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		months := defaultForecastMonths
+		if value := c.Query("months"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidHorizon.Error()})
+
+				return
+			}
+			months = parsed
+		}
+
+		forecast, err := forecastWizard.GetForecast(subscriptionFilter(c), c.Query("from"), months, c.Query("renewal"))
+		if err != nil {
+			log.Error("error building forecast", sl.Err(err))
+			switch {
+			case errors.Is(err, myerrors.ErrInvalidHorizon):
+				c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidHorizon.Error()})
+			case errors.Is(err, myerrors.ErrInvalidRenewalMode):
+				c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidRenewalMode.Error()})
+			default:
+				writeFilterError(c, err)
+			}
+
+			return
+		}
+
+		c.JSON(http.StatusOK, forecast)
+	}
+}
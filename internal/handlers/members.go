@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+type MemberWizard interface {
+	ListMembers(subscriptionID uuid.UUID) ([]storage.Member, error)
+	SetMember(subscriptionID, userID uuid.UUID, req storage.MemberRequest) (*storage.Member, error)
+	RemoveMember(subscriptionID, userID uuid.UUID) error
+}
+
+// parseMemberParams разбирает ID подписки и ID участника из пути
+func parseMemberParams(c *gin.Context, log *slog.Logger) (uuid.UUID, uuid.UUID, bool) {
+	id, ok := parseIDParam(c, log)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		log.Error("error parsing user id", sl.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to parse user_id"})
+
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, userID, true
+}
+
+// ListMembers godoc
+// @Summary Участники общей подписки
+// @Description Владелец участвует в оплате с весом 1, если его доля не задана явно, и попадает в список только в этом случае.
+// @Tags subscriptions v1
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Success 200 {object} storage.MembersListResponse "Участники"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/members [get]
+func ListMembers(log *slog.Logger, memberWizard MemberWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		members, err := memberWizard.ListMembers(id)
+		if err != nil {
+			log.Error("failed to list members", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.MembersListResponse{Members: members})
+	}
+}
+
+// SetMember godoc
+// @Summary Добавить участника или изменить его долю
+// @Description Доля задается весом (weight) или фиксированной суммой в месяц (amount), ровно одним из них.
+// @Description Фиксированные суммы вычитаются из стоимости месяца первыми, остаток делится между участниками по весам.
+// @Tags subscriptions v1
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Param user_id path string true "ID пользователя-участника" format(uuid)
+// @Param input body storage.MemberRequest true "Доля участника"
+// @Success 200 {object} storage.Member "Участник"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "exactly one of weight and amount must be positive"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/members/{user_id} [put]
+func SetMember(log *slog.Logger, memberWizard MemberWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, userID, ok := parseMemberParams(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.MemberRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		member, err := memberWizard.SetMember(id, userID, req)
+		if err != nil {
+			log.Error("failed to set member", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, member)
+	}
+}
+
+// RemoveMember godoc
+// @Summary Убрать участника из подписки
+// @Description Доля участника переходит к остальным участникам по весам.
+// @Tags subscriptions v1
+// @Param id path string true "ID подписки" format(uuid)
+// @Param user_id path string true "ID пользователя-участника" format(uuid)
+// @Success 204 "Участник удален"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse user_id"})
+// @Failure 404 {object} map[string]any "Подписка или участник не найдены" example({"error": "member not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/members/{user_id} [delete]
+func RemoveMember(log *slog.Logger, memberWizard MemberWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, userID, ok := parseMemberParams(c, log)
+		if !ok {
+			return
+		}
+
+		if err := memberWizard.RemoveMember(id, userID); err != nil {
+			log.Error("failed to remove member", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+type OverlapWizard interface {
+	OverlapsReport(userID string) ([]storage.OverlapPair, error)
+}
+
+// OverlapsReportV1 godoc
+// @Summary Отчет о пересекающихся подписках
+// @Description Возвращает пары подписок одного пользователя на один сервис (по каталогу или по названию без учета регистра), периоды которых пересекаются.
+// @Description from..to - общие месяцы пары, пустой to - пересечение не ограничено.
+// @Tags subscriptions v1
+// @Produce json
+// @Param user_id query string false "ID владельца подписок" format(uuid)
+// @Success 200 {object} storage.OverlapsReportResponse "Пересечения"
+// @Failure 400 {object} map[string]any "Неверный user_id" example({"error": "invalid user_id"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/overlaps [get]
+func OverlapsReportV1(log *slog.Logger, overlapWizard OverlapWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		overlaps, err := overlapWizard.OverlapsReport(c.Query("user_id"))
+		if err != nil {
+			log.Error("failed to build overlaps report", sl.Err(err))
+			writeFilterError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.OverlapsReportResponse{Overlaps: overlaps})
+	}
+}
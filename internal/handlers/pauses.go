@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+type PauseWizard interface {
+	PauseSubscription(id uuid.UUID, req storage.PauseRequest) ([]storage.Pause, error)
+	ResumeSubscription(id uuid.UUID, req storage.ResumeRequest) ([]storage.Pause, error)
+	ListPauses(subscriptionID uuid.UUID) ([]storage.Pause, error)
+}
+
+// bindOptionalJSON разбирает тело запроса, пустое тело оставляет req без изменений
+func bindOptionalJSON(c *gin.Context, log *slog.Logger, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil && !errors.Is(err, io.EOF) {
+		log.Error("failed to decode request body", sl.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+		return false
+	}
+
+	return true
+}
+
+// PauseSubscription godoc
+// @Summary Поставить подписку на паузу
+// @Description Месяцы паузы с from по until включительно не учитываются в расходах, бюджетах и не считаются активными.
+// @Description Без from пауза начинается с текущего месяца пользователя, без until длится до возобновления. Тело можно не передавать.
+// @Tags subscriptions v1
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Param input body storage.PauseRequest false "Период паузы"
+// @Success 200 {object} storage.PausesListResponse "Паузы подписки"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "pause must be within start_date and end_date"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 409 {object} map[string]any "Пересечение с другой паузой" example({"error": "pause overlaps an existing pause"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/pause [post]
+func PauseSubscription(log *slog.Logger, pauseWizard PauseWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.PauseRequest
+		if !bindOptionalJSON(c, log, &req) {
+			return
+		}
+
+		pauses, err := pauseWizard.PauseSubscription(id, req)
+		if err != nil {
+			log.Error("failed to pause subscription", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.PausesListResponse{Pauses: pauses})
+	}
+}
+
+// ResumeSubscription godoc
+// @Summary Возобновить подписку
+// @Description Пауза, действующая в месяце from (по умолчанию текущий месяц пользователя), заканчивается месяцем раньше.
+// @Description Если пауза начинается с from, она удаляется. Тело можно не передавать.
+// @Tags subscriptions v1
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Param input body storage.ResumeRequest false "Месяц возобновления"
+// @Success 200 {object} storage.PausesListResponse "Паузы подписки"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "invalid request"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 409 {object} map[string]any "Подписка не на паузе" example({"error": "subscription is not paused"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/resume [post]
+func ResumeSubscription(log *slog.Logger, pauseWizard PauseWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.ResumeRequest
+		if !bindOptionalJSON(c, log, &req) {
+			return
+		}
+
+		pauses, err := pauseWizard.ResumeSubscription(id, req)
+		if err != nil {
+			log.Error("failed to resume subscription", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.PausesListResponse{Pauses: pauses})
+	}
+}
+
+// ListPauses godoc
+// @Summary Паузы подписки
+// @Description Возвращает паузы по возрастанию start_month. Пауза без end_month длится до возобновления.
+// @Tags subscriptions v1
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Success 200 {object} storage.PausesListResponse "Паузы подписки"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/pauses [get]
+func ListPauses(log *slog.Logger, pauseWizard PauseWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		pauses, err := pauseWizard.ListPauses(id)
+		if err != nil {
+			log.Error("failed to list pauses", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.PausesListResponse{Pauses: pauses})
+	}
+}
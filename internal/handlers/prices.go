@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+type PriceWizard interface {
+	ListPrices(subscriptionID uuid.UUID) ([]storage.SubscriptionPrice, error)
+}
+
+// ListPrices godoc
+// @Summary История цены подписки
+// @Description Возвращает периоды цены по возрастанию effective_from. Каждая цена действует с effective_from до начала следующего периода.
+// @Description Новый период создается, когда в PATCH или PUT передана цена, отличная от действующей.
+// @Tags subscriptions v1
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Success 200 {object} storage.PricesListResponse "Периоды цены"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/prices [get]
+func ListPrices(log *slog.Logger, priceWizard PriceWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		prices, err := priceWizard.ListPrices(id)
+		if err != nil {
+			log.Error("failed to list prices", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.PricesListResponse{Prices: prices})
+	}
+}
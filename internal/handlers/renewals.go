@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+type RenewalWizard interface {
+	ListRenewals(subscriptionID uuid.UUID) ([]storage.Renewal, error)
+}
+
+// ListRenewals godoc
+// @Summary История автопродлений подписки
+// @Description Возвращает продления подписки с auto_renew, новые первыми. Подписка продлевается на renewal_months, когда заканчивается месяц end_date.
+// @Tags subscriptions v1
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Success 200 {object} storage.RenewalsListResponse "Продления"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/renewals [get]
+func ListRenewals(log *slog.Logger, renewalWizard RenewalWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		renewals, err := renewalWizard.ListRenewals(id)
+		if err != nil {
+			log.Error("failed to list renewals", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.RenewalsListResponse{Renewals: renewals})
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+type SearchWizard interface {
+	SuggestServiceNames(filter storage.SubscriptionFilter, limit int) ([]string, error)
+}
+
+// SuggestServiceNamesV1 godoc
+// @Summary Подсказки названий сервисов
+// @Description Возвращает различные названия сервисов подписок, подходящие под q: сначала совпадения по началу названия, затем похожие (триграммы) по убыванию похожести.
+// @Description Регистр не учитывается. Остальные фильтры такие же, как у списка.
+// @Tags subscriptions v1
+// @Produce json
+// @Param q query string true "Начало или часть названия сервиса" example(netf)
+// @Param limit query int false "Число подсказок, от 1 до 50 (по умолчанию 10)" example(10)
+// @Param user_id query string false "ID пользователя для фильтрации" format(uuid)
+// @Param category_id query string false "ID категории, включая дочерние" format(uuid)
+// @Param tag query []string false "Тег, можно указать несколько раз" collectionFormat(multi)
+// @Param status query []string false "Статус, можно указать несколько раз" collectionFormat(multi)
+// @Success 200 {object} storage.ServiceNamesResponse "Подсказки"
+// @Failure 400 {object} map[string]any "Некорректные параметры" example({"error": "q is required"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/suggest [get]
+func SuggestServiceNamesV1(log *slog.Logger, searchWizard SearchWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := storage.DefaultSuggestLimit
+		if value := c.Query("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidLimit.Error()})
+
+				return
+			}
+			limit = parsed
+		}
+
+		names, err := searchWizard.SuggestServiceNames(subscriptionFilter(c), limit)
+		if err != nil {
+			log.Error("error suggesting service names", sl.Err(err))
+			switch {
+			case errors.Is(err, myerrors.ErrSearchQueryRequired):
+				c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrSearchQueryRequired.Error()})
+			case errors.Is(err, myerrors.ErrInvalidLimit):
+				c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidLimit.Error()})
+			default:
+				writeFilterError(c, err)
+			}
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.ServiceNamesResponse{ServiceNames: names})
+	}
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// ServicesV1Path - базовый путь каталога сервисов
+const ServicesV1Path = "/api/v1/services"
+
+type ServiceWizard interface {
+	CreateService(req storage.ServiceCreateRequest) (*storage.Service, error)
+	GetService(id uuid.UUID) (*storage.Service, error)
+	ListServices() ([]storage.Service, error)
+	UpdateService(id uuid.UUID, req storage.ServiceUpdateRequest) (*storage.Service, error)
+	DeleteService(id uuid.UUID) error
+}
+
+// CreateService godoc
+// @Summary Добавить сервис в каталог
+// @Description Добавляет сервис с каноническим названием и псевдонимами. Существующие подписки без service_id, чье название совпадает с названием или псевдонимом, привязываются к сервису.
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param input body storage.ServiceCreateRequest true "Данные сервиса"
+// @Success 201 {object} storage.Service "Сервис добавлен"
+// @Header 201 {string} Location "/api/v1/services/{id}"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "failed to decode request body"})
+// @Failure 409 {object} map[string]any "Название или псевдоним заняты" example({"error": "service name or alias is already used by another service"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/services [post]
+func CreateService(log *slog.Logger, serviceWizard ServiceWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req storage.ServiceCreateRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		svc, err := serviceWizard.CreateService(req)
+		if err != nil {
+			log.Error("failed to create service", sl.Err(err))
+			writeServiceError(c, err)
+
+			return
+		}
+
+		log.Info("new service created", slog.Any("service id", svc.ID))
+		c.Header("Location", ServicesV1Path+"/"+svc.ID.String())
+		c.JSON(http.StatusCreated, svc)
+	}
+}
+
+// ListServices godoc
+// @Summary Получить каталог сервисов
+// @Tags services
+// @Produce json
+// @Success 200 {object} storage.ServicesListResponse "Каталог сервисов"
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/services [get]
+func ListServices(log *slog.Logger, serviceWizard ServiceWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		services, err := serviceWizard.ListServices()
+		if err != nil {
+			log.Error("failed to list services", sl.Err(err))
+			writeServiceError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.ServicesListResponse{Services: services})
+	}
+}
+
+// GetService godoc
+// @Summary Получить сервис по ID
+// @Tags services
+// @Produce json
+// @Param id path string true "ID сервиса" format(uuid)
+// @Success 200 {object} storage.Service "Сервис"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Сервис не найден" example({"error": "service not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/services/{id} [get]
+func GetService(log *slog.Logger, serviceWizard ServiceWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		svc, err := serviceWizard.GetService(id)
+		if err != nil {
+			log.Error("failed to get service", sl.Err(err))
+			writeServiceError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, svc)
+	}
+}
+
+// UpdateService godoc
+// @Summary Обновить сервис
+// @Description Обновляет переданные поля сервиса. Новое название проставляется всем привязанным подпискам, aliases заменяются целиком.
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path string true "ID сервиса" format(uuid)
+// @Param input body storage.ServiceUpdateRequest true "Данные для обновления"
+// @Success 200 {object} storage.Service "Сервис обновлен"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "failed to decode request body"})
+// @Failure 404 {object} map[string]any "Сервис не найден" example({"error": "service not found"})
+// @Failure 409 {object} map[string]any "Название или псевдоним заняты" example({"error": "service name or alias is already used by another service"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/services/{id} [patch]
+func UpdateService(log *slog.Logger, serviceWizard ServiceWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.ServiceUpdateRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		svc, err := serviceWizard.UpdateService(id, req)
+		if err != nil {
+			log.Error("failed to update service", sl.Err(err))
+			writeServiceError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, svc)
+	}
+}
+
+// DeleteService godoc
+// @Summary Удалить сервис из каталога
+// @Description Удаляет сервис. Подписки сохраняют service_name, service_id у них обнуляется.
+// @Tags services
+// @Param id path string true "ID сервиса" format(uuid)
+// @Success 204 "Сервис удален"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Сервис не найден" example({"error": "service not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/services/{id} [delete]
+func DeleteService(log *slog.Logger, serviceWizard ServiceWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		if err := serviceWizard.DeleteService(id); err != nil {
+			log.Error("failed to delete service", sl.Err(err))
+			writeServiceError(c, err)
+
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func writeServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, myerrors.ErrServiceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": myerrors.ErrServiceNotFound.Error()})
+	case errors.Is(err, myerrors.ErrServiceExists):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrServiceExists.Error()})
+	case errors.Is(err, myerrors.ErrProjectionDrift):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrProjectionDrift.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+type StatusWizard interface {
+	CancelSubscription(id uuid.UUID, req storage.CancelRequest) (*storage.Subscription, error)
+	ReactivateSubscription(id uuid.UUID, req storage.ReactivateRequest) (*storage.Subscription, error)
+}
+
+// CancelSubscription godoc
+// @Summary Отменить подписку
+// @Description Подписка получает статус cancelled и действует по месяц effective_date включительно (по умолчанию текущий месяц пользователя).
+// @Description Автопродление выключается, паузы после effective_date удаляются. Отменить можно подписку в статусе trial, active или paused. Тело можно не передавать.
+// @Tags subscriptions v1
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Param input body storage.CancelRequest false "Причина и дата отмены"
+// @Success 200 {object} storage.SubscriptionR "Отмененная подписка"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "effective_date must be between start_date and end_date"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 409 {object} map[string]any "Переход не разрешен" example({"error": "subscription status transition is not allowed"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/cancel [post]
+func CancelSubscription(log *slog.Logger, statusWizard StatusWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.CancelRequest
+		if !bindOptionalJSON(c, log, &req) {
+			return
+		}
+
+		sub, err := statusWizard.CancelSubscription(id, req)
+		if err != nil {
+			log.Error("failed to cancel subscription", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, SubToFormatTime(sub))
+	}
+}
+
+// ReactivateSubscription godoc
+// @Summary Возобновить отмененную или истекшую подписку
+// @Description Снимает отмену; статус снова следует из дат и пауз. Если end_date не передан, сохраняется еще не прошедший end_date.
+// @Description Подписке, чей end_date уже прошел, нужен новый end_date, не раньше текущего месяца; без него 400.
+// @Tags subscriptions v1
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Param input body storage.ReactivateRequest false "Новый срок"
+// @Success 200 {object} storage.SubscriptionR "Возобновленная подписка"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "end_date can not be earlier than start_date"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 409 {object} map[string]any "Подписка не отменена и не истекла" example({"error": "subscription status transition is not allowed"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/reactivate [post]
+func ReactivateSubscription(log *slog.Logger, statusWizard StatusWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.ReactivateRequest
+		if !bindOptionalJSON(c, log, &req) {
+			return
+		}
+
+		sub, err := statusWizard.ReactivateSubscription(id, req)
+		if err != nil {
+			log.Error("failed to reactivate subscription", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, SubToFormatTime(sub))
+	}
+}
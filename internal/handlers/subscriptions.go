@@ -4,7 +4,6 @@ package handlers
 import (
 	"log/slog"
 	"net/http"
-	"strings"
 
 	"errors"
 
@@ -20,14 +19,18 @@ const DateLayout = "2006-01"
 type DataWizard interface {
 	CreateSubscription(sub *storage.SubscriptionR) (uuid.UUID, error)
 	GetSubscription(id uuid.UUID) (*storage.Subscription, error)
+	GetSubscriptionAsOf(id uuid.UUID, asOf string) (*storage.Subscription, error)
 	DeleteSubscription(id uuid.UUID) (string, error)
 	UpdateSubscription(id uuid.UUID, req storage.UpdateSubscriptionRequest) error
-	GetListSubscriptions(userID, name string) ([]storage.Subscription, error)
+	ReplaceSubscription(id uuid.UUID, sub *storage.SubscriptionR) error
+	GetListSubscriptions(filter storage.SubscriptionFilter) ([]storage.Subscription, error)
+	GetTotalCost(filter storage.SubscriptionFilter, from, to, groupBy string) (*storage.TotalCostResponse, error)
+	SubscriptionOverlaps(id uuid.UUID) ([]storage.Overlap, error)
 }
 
 // CreateSubscription godoc
 // @Summary Создать подписку
-// @Description Добавляет новую подписку для пользователя. Поля user_id и end_date опциональны, если не указать user_id - сгенерируется автоматически, если не указать end_date - прибавиться + 1 год от начала подписки.
+// @Description Добавляет новую подписку для существующего пользователя (user_id обязателен, см. /api/v1/users). Поле end_date опционально, если не указать end_date - прибавиться + 1 год от начала подписки.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
@@ -35,6 +38,7 @@ type DataWizard interface {
 // @Success 201 {object} map[string]interface{} "Успешное создание"
 // @Failure 400 {object} map[string]interface{} "Ошибка валидации"
 // @Failure 500 {object} map[string]interface{} "Внутрення ошибка сервера"
+// @Deprecated
 // @Router /new [post]
 func CreateSubscription(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -58,6 +62,12 @@ func CreateSubscription(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc
 
 			if errors.Is(err, myerrors.ErrInvalidDateRange) {
 				c.JSON(http.StatusBadRequest, gin.H{"error": "end_date can not be earlier than start_date"})
+			} else if errors.Is(err, myerrors.ErrUserRequired) || errors.Is(err, myerrors.ErrUnknownUser) ||
+				errors.Is(err, myerrors.ErrServiceRequired) || errors.Is(err, myerrors.ErrUnknownService) ||
+				errors.Is(err, myerrors.ErrUnknownCategory) || errors.Is(err, myerrors.ErrInvalidTag) ||
+				errors.Is(err, myerrors.ErrInvalidRenewalTerm) || errors.Is(err, myerrors.ErrInvalidTrial) ||
+				errors.Is(err, myerrors.ErrInvalidIntro) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			} else {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create new subscription"/*, "details": err.Error()*/})
 			}
@@ -78,6 +88,7 @@ func CreateSubscription(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc
 // @Failure 400 {object} map[string]any "Неверный UUID" example({"error": "failed to parse UUID"})
 // @Failure 404 {object} map[string]any "Подписка не найдена" example({"subscription": "not found"})
 // @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "failed to get subscription, internal error"})
+// @Deprecated
 // @Router /get/{id} [get]
 func GetSubscription(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -124,6 +135,7 @@ func GetSubscription(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
 // @Failure 400 {object} map[string]interface{} "Неверный ID" example({"error":"failed to parse id","details":"invalid UUID format"})
 // @Failure 404 {object} map[string]interface{} "Подписка не найдена" example({"error":"subscription not found"})
 // @Failure 500 {object} map[string]interface{} "Внутренняя ошибка сервера" example({"error":"internal server error"})
+// @Deprecated
 // @Router /delete/{id} [delete]
 func DeleteSubscription(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -172,7 +184,9 @@ func DeleteSubscription(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc
 // @Failure 400 {object} map[string]interface{} "Неверный ID" example({"error":"failed to parse id"})
 // @Failure 400 {object} map[string]interface{} "Некорректный запрос" example({"error": "failed to decode request body"})
 // @Failure 400 {object} map[string]interface{} "Некорретный диапазон дат" example({"error": "invalid request"})
+// @Failure 404 {object} map[string]interface{} "Подписка не найдена" example({"error": "subscription not found"})
 // @Failure 500 {object} map[string]interface{} "Внутренняя ошибка сервера" example({"error": "internal error"})
+// @Deprecated
 // @Router /update/{id} [patch]
 func UpdateSubscription(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -204,8 +218,10 @@ func UpdateSubscription(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc
 		if err != nil {
 			log.Error("update error", sl.Err(err))
 
-			if errors.Is(err, myerrors.ErrInvalidDateRange) || strings.Contains(err.Error(), "invalid") {
+			if errors.Is(err, myerrors.ErrInvalidDateRange) || errors.Is(err, myerrors.ErrUnknownService) || errors.Is(err, myerrors.ErrInvalidRequest) {
 				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			} else if errors.Is(err, myerrors.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
 			} else {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 			}
@@ -226,8 +242,9 @@ func UpdateSubscription(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc
 // @Param service_name query string false "Название сервиса для фильтрации" example(Netflix)
 // @Success 200 {object} map[string]interface{} "Успешный запрос" example({"subscriptions": [{"id": "550e8400-e29b-41d4-a716-446655440000", "service_name": "Netflix", ...}]})
 // @Success 200 {object} map[string]interface{} "Если подписок нет" example({"subscriptions": "not found"})
-// @Failure 400 {object} map[string]interface{} "Неверный user_id" example({"error": "invalid user_id"})
+// @Failure 400 {object} map[string]interface{} "Некорректные параметры фильтра, по одной ошибке на параметр в params" example({"error": "invalid user_id", "params": [{"param": "user_id", "error": "invalid user_id"}]})
 // @Failure 500 {object} map[string]interface{} "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Deprecated
 // @Router /get/list [get]
 func GetListSubscriptions(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -238,13 +255,14 @@ func GetListSubscriptions(log *slog.Logger, dataWizard DataWizard) gin.HandlerFu
 		log.Info("query parameters received", "userID", userID, "service_name", serviceName)
 		
 
-		subs, err := dataWizard.GetListSubscriptions(userID, serviceName)
+		filter := storage.SubscriptionFilter{UserID: userID}
+		if serviceName != "" {
+			filter.ServiceNames = []string{serviceName}
+		}
+		subs, err := dataWizard.GetListSubscriptions(filter)
 		if err != nil {
 			log.Error("error getting list subscriptions", sl.Err(err))
-			if strings.Contains(err.Error(), "failed parse id") {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			writeFilterError(c, err)
 
 			return
 		}
@@ -259,27 +277,40 @@ func GetListSubscriptions(log *slog.Logger, dataWizard DataWizard) gin.HandlerFu
 }
 
 func SubToFormatTime(sub *storage.Subscription) storage.SubscriptionR {
-	return storage.SubscriptionR{
-		ID:          sub.ID,
-		ServiceName: sub.ServiceName,
-		Price:       sub.Price,
-		UserID:      sub.UserID,
-		StartDate:   sub.StartDate.Format(DateLayout),
-		EndDate:     sub.EndDate.Format(DateLayout),
-	}													
+	subR := storage.SubscriptionR{
+		ID:            sub.ID,
+		ServiceName:   sub.ServiceName,
+		Price:         sub.Price,
+		UserID:        sub.UserID,
+		StartDate:     sub.StartDate.Format(DateLayout),
+		EndDate:       sub.EndDate.Format(DateLayout),
+		ServiceID:     sub.ServiceID,
+		CategoryID:    sub.CategoryID,
+		Tags:          sub.Tags,
+		AutoRenew:     sub.AutoRenew,
+		RenewalMonths: sub.RenewalMonths,
+		Status:        sub.Status,
+		CancelledAt:   sub.CancelledAt,
+	}
+	if sub.CancelReason != nil {
+		subR.CancelReason = *sub.CancelReason
+	}
+	if sub.TrialEndsAt != nil {
+		subR.TrialEndsAt = sub.TrialEndsAt.Format(DateLayout)
+	}
+	if sub.IntroPrice != nil && sub.IntroMonths != nil {
+		subR.IntroPrice = *sub.IntroPrice
+		subR.IntroMonths = *sub.IntroMonths
+	}
+
+	return subR
 }
 
+// SubsToFormatTime - SubToFormatTime для каждой подписки списка
 func SubsToFormatTime(subs []storage.Subscription) []storage.SubscriptionR {
 	result := make([]storage.SubscriptionR, len(subs))
-	for i, sub := range subs {
-		result[i] = storage.SubscriptionR{
-			ID:          sub.ID,
-			ServiceName: sub.ServiceName,
-			Price:       sub.Price,
-			UserID:      sub.UserID,
-			StartDate:   sub.StartDate.Format(DateLayout),
-			EndDate:     sub.EndDate.Format(DateLayout),
-		}
+	for i := range subs {
+		result[i] = SubToFormatTime(&subs[i])
 	}
 	return result
 }
@@ -0,0 +1,489 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// SubscriptionsV1Path - базовый путь ресурса подписок в версионированном API
+const SubscriptionsV1Path = "/api/v1/subscriptions"
+
+// CreateSubscriptionV1 godoc
+// @Summary Создать подписку
+// @Description Создает подписку и возвращает ее представление. Адрес нового ресурса передается в заголовке Location.
+// @Tags subscriptions v1
+// @Accept json
+// @Produce json
+// @Param input body storage.SubscriptionCreateRequest true "Данные подписки"
+// @Param conflict_policy query string false "Пересечение с подпиской того же пользователя на тот же сервис: warn - записать и вернуть conflicts, reject - 409" Enums(warn, reject)
+// @Success 201 {object} storage.SubscriptionR "Подписка создана"
+// @Header 201 {string} Location "/api/v1/subscriptions/{id}"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "failed to decode request body"})
+// @Failure 409 {object} map[string]any "Пересечение при conflict_policy=reject" example({"error": "subscription overlaps another subscription to the same service"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions [post]
+func CreateSubscriptionV1(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req storage.SubscriptionCreateRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		sub := createRequestToSubR(req)
+		sub.ConflictPolicy = c.Query("conflict_policy")
+
+		id, err := dataWizard.CreateSubscription(sub)
+		if err != nil {
+			log.Error("failed to create new subscription", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		log.Info("new subscription created!", slog.Any("susbcription id", id))
+		c.Header("Location", SubscriptionsV1Path+"/"+id.String())
+		respondWithConflicts(c, log, dataWizard, id, http.StatusCreated)
+	}
+}
+
+// ListSubscriptionsV1 godoc
+// @Summary Получить список подписок
+// @Description Возвращает список подписок с фильтрацией по user_id, названию сервиса, категории (вместе с дочерними) и тегам. Пустой результат - пустой массив, а не 404.
+// @Description С q подписки ищутся по названию сервиса и упорядочены по релевантности.
+// @Description С limit список отдается страницами по (start_date, id): заполненная страница содержит next_cursor, который передается в after за следующей.
+// @Description Диапазоны start_from..start_to и end_from..end_to, active_at и price_min..price_max включительны.
+// @Description filter принимает выражение над полями service_name, price, status, user_id, service_id, category_id, start_date, end_date, trial_ends_at, auto_renew, renewal_months, intro_price, cancel_reason; ошибка разбора указывает position.
+// @Tags subscriptions v1
+// @Produce json
+// @Param user_id query string false "ID пользователя для фильтрации" format(uuid)
+// @Param service_name query []string false "Название сервиса, можно указать несколько раз - подписка на любой из них" collectionFormat(multi)
+// @Param category_id query string false "ID категории, подписки дочерних категорий тоже попадают в выборку" format(uuid)
+// @Param tag query []string false "Тег, можно указать несколько раз - подписка должна иметь все" collectionFormat(multi)
+// @Param status query []string false "Статус (trial, active, paused, cancelled, expired), можно указать несколько раз" collectionFormat(multi)
+// @Param q query string false "Поиск по названию сервиса: начало названия или похожее название, без учета регистра. Результаты упорядочены по релевантности" example(netf)
+// @Param start_from query string false "start_date не раньше месяца YYYY-MM" example(2025-01)
+// @Param start_to query string false "start_date не позже месяца YYYY-MM" example(2025-06)
+// @Param end_from query string false "end_date не раньше месяца YYYY-MM, бессрочные подписки не попадают" example(2025-01)
+// @Param end_to query string false "end_date не позже месяца YYYY-MM, бессрочные подписки не попадают" example(2025-12)
+// @Param active_at query string false "Подписка действует и не на паузе в месяце YYYY-MM" example(2025-03)
+// @Param price_min query int false "Цена не меньше, с active_at - цена в этом месяце" example(300)
+// @Param price_max query int false "Цена не больше, с active_at - цена в этом месяце" example(1000)
+// @Param filter query string false "Выражение фильтра: сравнения полей (=, !=, <, <=, >, >=, ~ - содержит, !~, in, is null), функции active_at, paused_at, has_tag, and, or, not и скобки" example(price > 500 and service_name ~ "net" and active_at("2025-06"))
+// @Param as_of query string false "Список на момент в прошлом (RFC 3339 или YYYY-MM-DD) по истории версий; не сочетается с active_at, price_min, price_max и filter" example(2025-03-01)
+// @Param limit query int false "Размер страницы, 1-1000; без него возвращается весь список" example(50)
+// @Param after query string false "Курсор next_cursor предыдущей страницы; не сочетается с q, с q next_cursor не возвращается"
+// @Success 200 {object} storage.SubscriptionsListResponse "Список подписок"
+// @Failure 400 {object} map[string]any "Некорректные параметры фильтра, по одной ошибке на параметр в params" example({"error": "price_min must be a non-negative integer", "params": [{"param": "price_min", "error": "price_min must be a non-negative integer"}]})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions [get]
+func ListSubscriptionsV1(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := subscriptionFilter(c)
+		filter.Limit, filter.After = c.Query("limit"), c.Query("after")
+
+		subs, err := dataWizard.GetListSubscriptions(filter)
+		if err != nil {
+			log.Error("error getting list subscriptions", sl.Err(err))
+			writeFilterError(c, err)
+
+			return
+		}
+
+		resp := storage.SubscriptionsListResponse{Subscriptions: SubsToFormatTime(subs)}
+		// заполненная страница может быть не последней; limit уже проверен хранилищем.
+		// С q список упорядочен по релевантности и after не принимается, поэтому курсора нет
+		if limit, _ := strconv.Atoi(filter.Limit); limit > 0 && len(subs) == limit && strings.TrimSpace(filter.Query) == "" {
+			resp.NextCursor = storage.EncodeCursor(subs[len(subs)-1])
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// TotalCostV1 godoc
+// @Summary Суммарная стоимость подписок за период
+// @Description Считает стоимость всех подписок за каждый месяц периода from..to (включительно) с теми же фильтрами, что и список.
+// @Description С group_by=category дополнительно возвращает суммы по категориям.
+// @Tags subscriptions v1
+// @Produce json
+// @Param from query string true "Начало периода YYYY-MM" example(2025-01)
+// @Param to query string true "Конец периода YYYY-MM" example(2025-12)
+// @Param user_id query string false "ID пользователя для фильтрации" format(uuid)
+// @Param service_name query string false "Название сервиса для фильтрации" example(Netflix)
+// @Param category_id query string false "ID категории, включая дочерние" format(uuid)
+// @Param tag query []string false "Тег, можно указать несколько раз" collectionFormat(multi)
+// @Param status query []string false "Статус, можно указать несколько раз" collectionFormat(multi)
+// @Param group_by query string false "Группировка" Enums(category)
+// @Success 200 {object} storage.TotalCostResponse "Суммарная стоимость"
+// @Failure 400 {object} map[string]any "Некорректные параметры" example({"error": "from and to are required"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/total [get]
+func TotalCostV1(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from := c.Query("from")
+		to := c.Query("to")
+		if from == "" || to == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required"})
+
+			return
+		}
+
+		total, err := dataWizard.GetTotalCost(subscriptionFilter(c), from, to, c.Query("group_by"))
+		if err != nil {
+			log.Error("error counting total cost", sl.Err(err))
+			switch {
+			case errors.Is(err, myerrors.ErrInvalidPeriod):
+				c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidPeriod.Error()})
+			case errors.Is(err, myerrors.ErrInvalidGroupBy):
+				c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidGroupBy.Error()})
+			default:
+				writeFilterError(c, err)
+			}
+
+			return
+		}
+
+		c.JSON(http.StatusOK, total)
+	}
+}
+
+// GetSubscriptionV1 godoc
+// @Summary Получить подписку по ID
+// @Tags subscriptions v1
+// @Produce json
+// @Description С as_of возвращает подписку в том виде, в каком она была в этот момент, по истории версий; 404 - подписки тогда не было.
+// @Param id path string true "ID подписки" format(uuid)
+// @Param as_of query string false "Момент в прошлом: RFC 3339 или YYYY-MM-DD" example(2025-03-01T12:00:00Z)
+// @Success 200 {object} storage.SubscriptionR "Подписка"
+// @Failure 400 {object} map[string]any "Неверный ID или as_of" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id} [get]
+func GetSubscriptionV1(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var sub *storage.Subscription
+		var err error
+		if asOf := c.Query("as_of"); asOf != "" {
+			sub, err = dataWizard.GetSubscriptionAsOf(id, asOf)
+		} else {
+			sub, err = dataWizard.GetSubscription(id)
+		}
+		if err != nil {
+			log.Error("failed to get", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, SubToFormatTime(sub))
+	}
+}
+
+// ReplaceSubscriptionV1 godoc
+// @Summary Заменить подписку
+// @Description Полностью перезаписывает подписку. Если не указан end_date - ставится start_date + 1 год, если не указан user_id - остается прежний.
+// @Description Отличающаяся price действует с текущего месяца, история цены сохраняется.
+// @Tags subscriptions v1
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Param input body storage.SubscriptionCreateRequest true "Новое состояние подписки"
+// @Param conflict_policy query string false "Пересечение с подпиской того же пользователя на тот же сервис: warn - записать и вернуть conflicts, reject - 409" Enums(warn, reject)
+// @Success 200 {object} storage.SubscriptionR "Подписка обновлена"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "failed to decode request body"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 409 {object} map[string]any "Пересечение при conflict_policy=reject" example({"error": "subscription overlaps another subscription to the same service"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id} [put]
+func ReplaceSubscriptionV1(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.SubscriptionCreateRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		sub := createRequestToSubR(req)
+		sub.ConflictPolicy = c.Query("conflict_policy")
+
+		if err := dataWizard.ReplaceSubscription(id, sub); err != nil {
+			log.Error("replace error", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		respondWithConflicts(c, log, dataWizard, id, http.StatusOK)
+	}
+}
+
+// PatchSubscriptionV1 godoc
+// @Summary Частично обновить подписку
+// @Description Обновляет только переданные поля подписки. Новая price не переписывает историю:
+// @Description она действует с месяца price_effective_from (по умолчанию текущего), прежние месяцы считаются по старой цене.
+// @Tags subscriptions v1
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Param input body storage.UpdateSubscriptionRequest true "Данные для обновления"
+// @Param conflict_policy query string false "Пересечение с подпиской того же пользователя на тот же сервис: warn - записать и вернуть conflicts, reject - 409" Enums(warn, reject)
+// @Success 200 {object} storage.SubscriptionR "Подписка обновлена"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "failed to decode request body"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 409 {object} map[string]any "Пересечение при conflict_policy=reject" example({"error": "subscription overlaps another subscription to the same service"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id} [patch]
+func PatchSubscriptionV1(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.UpdateSubscriptionRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		req.ConflictPolicy = c.Query("conflict_policy")
+
+		if err := dataWizard.UpdateSubscription(id, req); err != nil {
+			log.Error("update error", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		respondWithConflicts(c, log, dataWizard, id, http.StatusOK)
+	}
+}
+
+// DeleteSubscriptionV1 godoc
+// @Summary Удалить подписку
+// @Tags subscriptions v1
+// @Param id path string true "ID подписки" format(uuid)
+// @Success 204 "Подписка удалена"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id} [delete]
+func DeleteSubscriptionV1(log *slog.Logger, dataWizard DataWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		if _, err := dataWizard.DeleteSubscription(id); err != nil {
+			log.Error("failed to delete", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func respondWithSubscription(c *gin.Context, log *slog.Logger, dataWizard DataWizard, id uuid.UUID) {
+	sub, err := dataWizard.GetSubscription(id)
+	if err != nil {
+		log.Error("failed to get subscription", sl.Err(err))
+		writeStorageError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, SubToFormatTime(sub))
+}
+
+// respondWithConflicts отвечает подпиской после записи вместе с пересекающимися подписками
+// на тот же сервис; при пересечениях добавляет заголовок Warning
+func respondWithConflicts(c *gin.Context, log *slog.Logger, dataWizard DataWizard, id uuid.UUID, status int) {
+	sub, err := dataWizard.GetSubscription(id)
+	if err != nil {
+		log.Error("failed to get subscription", sl.Err(err))
+		writeStorageError(c, err)
+
+		return
+	}
+
+	subR := SubToFormatTime(sub)
+	// запись уже выполнена, поэтому ошибка поиска пересечений только логируется
+	subR.Conflicts, err = dataWizard.SubscriptionOverlaps(id)
+	if err != nil {
+		log.Error("failed to find overlapping subscriptions", sl.Err(err))
+	}
+	if len(subR.Conflicts) > 0 {
+		c.Header("Warning", fmt.Sprintf(`299 - "overlaps %d subscription(s) to the same service"`, len(subR.Conflicts)))
+	}
+
+	c.JSON(status, subR)
+}
+
+// parseIDParam разбирает параметр пути id, при ошибке сам отвечает 400
+func parseIDParam(c *gin.Context, log *slog.Logger) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Error("error parsing id", sl.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to parse id"})
+
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// subscriptionFilter собирает фильтр подписок из query-параметров; tag может повторяться
+func subscriptionFilter(c *gin.Context) storage.SubscriptionFilter {
+	return storage.SubscriptionFilter{
+		UserID:       c.Query("user_id"),
+		ServiceNames: c.QueryArray("service_name"),
+		CategoryID:   c.Query("category_id"),
+		Tags:         c.QueryArray("tag"),
+		Statuses:     c.QueryArray("status"),
+		Query:        c.Query("q"),
+		StartFrom:    c.Query("start_from"),
+		StartTo:      c.Query("start_to"),
+		EndFrom:      c.Query("end_from"),
+		EndTo:        c.Query("end_to"),
+		ActiveAt:     c.Query("active_at"),
+		PriceMin:     c.Query("price_min"),
+		PriceMax:     c.Query("price_max"),
+		Expr:         c.Query("filter"),
+		AsOf:         c.Query("as_of"),
+	}
+}
+
+// writeFilterError отвечает 400 на некорректный фильтр, остальное передает writeStorageError.
+// Ошибки параметров фильтра перечисляются в params
+func writeFilterError(c *gin.Context, err error) {
+	var filterErr *storage.FilterError
+	switch {
+	case errors.As(err, &filterErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": filterErr.Error(), "params": filterErr.Params})
+	case strings.Contains(err.Error(), "failed parse id"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+	case strings.Contains(err.Error(), "failed parse category id"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id"})
+	default:
+		writeStorageError(c, err)
+	}
+}
+
+// writeStorageError переводит ошибку слоя хранения в HTTP-ответ
+func writeStorageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, myerrors.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+	case errors.Is(err, myerrors.ErrInvalidDateRange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidDateRange.Error()})
+	case errors.Is(err, myerrors.ErrUserRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrUserRequired.Error()})
+	case errors.Is(err, myerrors.ErrUnknownUser):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrUnknownUser.Error()})
+	case errors.Is(err, myerrors.ErrServiceRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrServiceRequired.Error()})
+	case errors.Is(err, myerrors.ErrUnknownService):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrUnknownService.Error()})
+	case errors.Is(err, myerrors.ErrUnknownCategory):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrUnknownCategory.Error()})
+	case errors.Is(err, myerrors.ErrInvalidTag):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidTag.Error()})
+	case errors.Is(err, myerrors.ErrInvalidGroupBy):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidGroupBy.Error()})
+	case errors.Is(err, myerrors.ErrInvalidAsOf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidAsOf.Error()})
+	case errors.Is(err, myerrors.ErrInvalidMetric):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidMetric.Error()})
+	case errors.Is(err, myerrors.ErrInvalidRenewalTerm):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidRenewalTerm.Error()})
+	case errors.Is(err, myerrors.ErrInvalidTrial):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidTrial.Error()})
+	case errors.Is(err, myerrors.ErrInvalidIntro):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidIntro.Error()})
+	case errors.Is(err, myerrors.ErrInvalidPause):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidPause.Error()})
+	case errors.Is(err, myerrors.ErrPauseOverlap):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrPauseOverlap.Error()})
+	case errors.Is(err, myerrors.ErrNotPaused):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrNotPaused.Error()})
+	case errors.Is(err, myerrors.ErrInvalidStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidStatus.Error()})
+	case errors.Is(err, myerrors.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrInvalidTransition.Error()})
+	case errors.Is(err, myerrors.ErrInvalidCancelDate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidCancelDate.Error()})
+	case errors.Is(err, myerrors.ErrMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": myerrors.ErrMemberNotFound.Error()})
+	case errors.Is(err, myerrors.ErrInvalidShare):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidShare.Error()})
+	case errors.Is(err, myerrors.ErrSubscriptionOverlap):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrSubscriptionOverlap.Error()})
+	case errors.Is(err, myerrors.ErrInvalidConflictPolicy):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidConflictPolicy.Error()})
+	case errors.Is(err, myerrors.ErrProjectionDrift):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrProjectionDrift.Error()})
+	case errors.Is(err, myerrors.ErrInvalidRequest):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
+
+func createRequestToSubR(req storage.SubscriptionCreateRequest) *storage.SubscriptionR {
+	sub := &storage.SubscriptionR{
+		ServiceName:   req.ServiceName,
+		ServiceID:     req.ServiceID,
+		Price:         req.Price,
+		StartDate:     req.StartDate,
+		CategoryID:    req.CategoryID,
+		Tags:          req.Tags,
+		AutoRenew:     req.AutoRenew,
+		RenewalMonths: req.RenewalMonths,
+		IntroPrice:    req.IntroPrice,
+		IntroMonths:   req.IntroMonths,
+	}
+	if req.UserID != nil {
+		sub.UserID = *req.UserID
+	}
+	if req.EndDate != nil {
+		sub.EndDate = *req.EndDate
+	}
+	if req.TrialEndsAt != nil {
+		sub.TrialEndsAt = *req.TrialEndsAt
+	}
+
+	return sub
+}
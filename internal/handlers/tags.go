@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// TagsV1Path - базовый путь справочника тегов
+const TagsV1Path = "/api/v1/tags"
+
+type TagWizard interface {
+	CreateTag(name string) (*storage.Tag, error)
+	ListTags() ([]storage.Tag, error)
+	RenameTag(id uuid.UUID, name string) (*storage.Tag, error)
+	DeleteTag(id uuid.UUID) error
+	SetSubscriptionTags(id uuid.UUID, tags []string) error
+	AddSubscriptionTag(id uuid.UUID, tag string) error
+	RemoveSubscriptionTag(id uuid.UUID, tag string) error
+}
+
+// CreateTag godoc
+// @Summary Создать тег
+// @Description Теги хранятся в нижнем регистре. Обычно создавать тег заранее не нужно: он создается при добавлении к подписке.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param input body storage.TagRequest true "Тег"
+// @Success 201 {object} storage.Tag "Тег создан"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "tag must be non-empty and at most 64 characters"})
+// @Failure 409 {object} map[string]any "Тег уже есть" example({"error": "tag already exists"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/tags [post]
+func CreateTag(log *slog.Logger, tagWizard TagWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req storage.TagRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		tag, err := tagWizard.CreateTag(req.Name)
+		if err != nil {
+			log.Error("failed to create tag", sl.Err(err))
+			writeTagError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusCreated, tag)
+	}
+}
+
+// ListTags godoc
+// @Summary Получить список тегов
+// @Description Возвращает все теги с числом подписок у каждого
+// @Tags tags
+// @Produce json
+// @Success 200 {object} storage.TagsListResponse "Список тегов"
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/tags [get]
+func ListTags(log *slog.Logger, tagWizard TagWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tags, err := tagWizard.ListTags()
+		if err != nil {
+			log.Error("failed to list tags", sl.Err(err))
+			writeTagError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.TagsListResponse{Tags: tags})
+	}
+}
+
+// RenameTag godoc
+// @Summary Переименовать тег
+// @Description Новое имя сразу видно у всех подписок с этим тегом
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "ID тега" format(uuid)
+// @Param input body storage.TagRequest true "Новое имя"
+// @Success 200 {object} storage.Tag "Тег переименован"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "tag must be non-empty and at most 64 characters"})
+// @Failure 404 {object} map[string]any "Тег не найден" example({"error": "tag not found"})
+// @Failure 409 {object} map[string]any "Имя занято" example({"error": "tag already exists"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/tags/{id} [patch]
+func RenameTag(log *slog.Logger, tagWizard TagWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.TagRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		tag, err := tagWizard.RenameTag(id, req.Name)
+		if err != nil {
+			log.Error("failed to rename tag", sl.Err(err))
+			writeTagError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, tag)
+	}
+}
+
+// DeleteTag godoc
+// @Summary Удалить тег
+// @Description Удаляет тег из справочника и снимает его со всех подписок
+// @Tags tags
+// @Param id path string true "ID тега" format(uuid)
+// @Success 204 "Тег удален"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Тег не найден" example({"error": "tag not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/tags/{id} [delete]
+func DeleteTag(log *slog.Logger, tagWizard TagWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		if err := tagWizard.DeleteTag(id); err != nil {
+			log.Error("failed to delete tag", sl.Err(err))
+			writeTagError(c, err)
+
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// SetSubscriptionTags godoc
+// @Summary Заменить теги подписки
+// @Description Заменяет набор тегов подписки целиком, пустой массив снимает все теги
+// @Tags subscriptions v1
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Param input body storage.SubscriptionTagsRequest true "Новый набор тегов"
+// @Success 200 {object} storage.SubscriptionR "Подписка с новыми тегами"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "tag must be non-empty and at most 64 characters"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/tags [put]
+func SetSubscriptionTags(log *slog.Logger, tagWizard TagWizard, dataWizard DataWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.SubscriptionTagsRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		if err := tagWizard.SetSubscriptionTags(id, req.Tags); err != nil {
+			log.Error("failed to set subscription tags", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		respondWithSubscription(c, log, dataWizard, id)
+	}
+}
+
+// AddSubscriptionTag godoc
+// @Summary Добавить тег подписке
+// @Description Добавляет один тег, повторное добавление ничего не меняет
+// @Tags subscriptions v1
+// @Accept json
+// @Produce json
+// @Param id path string true "ID подписки" format(uuid)
+// @Param input body storage.TagRequest true "Тег"
+// @Success 200 {object} storage.SubscriptionR "Подписка с новым тегом"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "tag must be non-empty and at most 64 characters"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/tags [post]
+func AddSubscriptionTag(log *slog.Logger, tagWizard TagWizard, dataWizard DataWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.TagRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		if err := tagWizard.AddSubscriptionTag(id, req.Name); err != nil {
+			log.Error("failed to add subscription tag", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		respondWithSubscription(c, log, dataWizard, id)
+	}
+}
+
+// RemoveSubscriptionTag godoc
+// @Summary Снять тег с подписки
+// @Tags subscriptions v1
+// @Param id path string true "ID подписки" format(uuid)
+// @Param tag path string true "Тег" example(family)
+// @Success 204 "Тег снят"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Подписка не найдена" example({"error": "subscription not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/{id}/tags/{tag} [delete]
+func RemoveSubscriptionTag(log *slog.Logger, tagWizard TagWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		if err := tagWizard.RemoveSubscriptionTag(id, c.Param("tag")); err != nil {
+			log.Error("failed to remove subscription tag", sl.Err(err))
+			writeStorageError(c, err)
+
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func writeTagError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, myerrors.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": myerrors.ErrTagNotFound.Error()})
+	case errors.Is(err, myerrors.ErrTagExists):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrTagExists.Error()})
+	case errors.Is(err, myerrors.ErrInvalidTag):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidTag.Error()})
+	case errors.Is(err, myerrors.ErrProjectionDrift):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrProjectionDrift.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
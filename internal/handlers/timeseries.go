@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+type TimeSeriesWizard interface {
+	GetTimeSeries(filter storage.SubscriptionFilter, from, to, groupBy string) (*storage.TimeSeriesResponse, error)
+}
+
+// TimeSeriesV1 godoc
+// @Summary Расходы по месяцам
+// @Description Для каждого месяца периода from..to (включительно, не больше 240 месяцев) возвращает стоимость подписок и число действующих подписок (подписки на паузе не считаются).
+// @Description Фильтры те же, что у списка. С group_by месяц дополнительно разбит по сервисам, категориям или пользователям; при group_by=user общая подписка делится между участниками по долям.
+// @Tags subscriptions v1
+// @Produce json
+// @Param from query string true "Начало периода YYYY-MM" example(2025-01)
+// @Param to query string true "Конец периода YYYY-MM" example(2025-12)
+// @Param user_id query string false "ID пользователя для фильтрации" format(uuid)
+// @Param service_name query string false "Название сервиса для фильтрации" example(Netflix)
+// @Param category_id query string false "ID категории, включая дочерние" format(uuid)
+// @Param tag query []string false "Тег, можно указать несколько раз" collectionFormat(multi)
+// @Param status query []string false "Статус, можно указать несколько раз" collectionFormat(multi)
+// @Param group_by query string false "Группировка" Enums(service, category, user)
+// @Success 200 {object} storage.TimeSeriesResponse "Расходы по месяцам"
+// @Failure 400 {object} map[string]any "Некорректные параметры" example({"error": "period must not exceed 240 months"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/subscriptions/timeseries [get]
+func TimeSeriesV1(log *slog.Logger, seriesWizard TimeSeriesWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from := c.Query("from")
+		to := c.Query("to")
+		if from == "" || to == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required"})
+
+			return
+		}
+
+		series, err := seriesWizard.GetTimeSeries(subscriptionFilter(c), from, to, c.Query("group_by"))
+		if err != nil {
+			log.Error("error building time series", sl.Err(err))
+			switch {
+			case errors.Is(err, myerrors.ErrInvalidPeriod):
+				c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidPeriod.Error()})
+			case errors.Is(err, myerrors.ErrPeriodTooLong):
+				c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrPeriodTooLong.Error()})
+			case errors.Is(err, myerrors.ErrInvalidGroupBy):
+				c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidGroupBy.Error()})
+			default:
+				writeFilterError(c, err)
+			}
+
+			return
+		}
+
+		c.JSON(http.StatusOK, series)
+	}
+}
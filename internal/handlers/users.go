@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// UsersV1Path - базовый путь ресурса пользователей
+const UsersV1Path = "/api/v1/users"
+
+type UserWizard interface {
+	CreateUser(req storage.UserCreateRequest) (*storage.User, error)
+	GetUser(id uuid.UUID) (*storage.User, error)
+	ListUsers() ([]storage.User, error)
+	UpdateUser(id uuid.UUID, req storage.UserUpdateRequest) (*storage.User, error)
+	DeleteUser(id uuid.UUID) error
+}
+
+// CreateUser godoc
+// @Summary Создать пользователя
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body storage.UserCreateRequest true "Данные пользователя"
+// @Success 201 {object} storage.User "Пользователь создан"
+// @Header 201 {string} Location "/api/v1/users/{id}"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "failed to decode request body"})
+// @Failure 409 {object} map[string]any "Email уже занят" example({"error": "user with this email already exists"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/users [post]
+func CreateUser(log *slog.Logger, userWizard UserWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req storage.UserCreateRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		user, err := userWizard.CreateUser(req)
+		if err != nil {
+			log.Error("failed to create user", sl.Err(err))
+			writeUserError(c, err)
+
+			return
+		}
+
+		log.Info("new user created", slog.Any("user id", user.ID))
+		c.Header("Location", UsersV1Path+"/"+user.ID.String())
+		c.JSON(http.StatusCreated, user)
+	}
+}
+
+// ListUsers godoc
+// @Summary Получить список пользователей
+// @Tags users
+// @Produce json
+// @Success 200 {object} storage.UsersListResponse "Список пользователей"
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/users [get]
+func ListUsers(log *slog.Logger, userWizard UserWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		users, err := userWizard.ListUsers()
+		if err != nil {
+			log.Error("failed to list users", sl.Err(err))
+			writeUserError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, storage.UsersListResponse{Users: users})
+	}
+}
+
+// GetUser godoc
+// @Summary Получить пользователя по ID
+// @Tags users
+// @Produce json
+// @Param id path string true "ID пользователя" format(uuid)
+// @Success 200 {object} storage.User "Пользователь"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Пользователь не найден" example({"error": "user not found"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/users/{id} [get]
+func GetUser(log *slog.Logger, userWizard UserWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		user, err := userWizard.GetUser(id)
+		if err != nil {
+			log.Error("failed to get user", sl.Err(err))
+			writeUserError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+// UpdateUser godoc
+// @Summary Обновить пользователя
+// @Description Обновляет только переданные поля пользователя
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID пользователя" format(uuid)
+// @Param input body storage.UserUpdateRequest true "Данные для обновления"
+// @Success 200 {object} storage.User "Пользователь обновлен"
+// @Failure 400 {object} map[string]any "Ошибка валидации" example({"error": "invalid timezone"})
+// @Failure 404 {object} map[string]any "Пользователь не найден" example({"error": "user not found"})
+// @Failure 409 {object} map[string]any "Email уже занят" example({"error": "user with this email already exists"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/users/{id} [patch]
+func UpdateUser(log *slog.Logger, userWizard UserWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		var req storage.UserUpdateRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to decode request body"})
+
+			return
+		}
+
+		user, err := userWizard.UpdateUser(id, req)
+		if err != nil {
+			log.Error("failed to update user", sl.Err(err))
+			writeUserError(c, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+// DeleteUser godoc
+// @Summary Удалить пользователя
+// @Description Удаляет пользователя. Пользователя с подписками удалить нельзя.
+// @Tags users
+// @Param id path string true "ID пользователя" format(uuid)
+// @Success 204 "Пользователь удален"
+// @Failure 400 {object} map[string]any "Неверный ID" example({"error": "failed to parse id"})
+// @Failure 404 {object} map[string]any "Пользователь не найден" example({"error": "user not found"})
+// @Failure 409 {object} map[string]any "У пользователя есть подписки" example({"error": "user still has subscriptions"})
+// @Failure 500 {object} map[string]any "Внутренняя ошибка сервера" example({"error": "internal server error"})
+// @Router /api/v1/users/{id} [delete]
+func DeleteUser(log *slog.Logger, userWizard UserWizard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, log)
+		if !ok {
+			return
+		}
+
+		if err := userWizard.DeleteUser(id); err != nil {
+			log.Error("failed to delete user", sl.Err(err))
+			writeUserError(c, err)
+
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func writeUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, myerrors.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": myerrors.ErrUserNotFound.Error()})
+	case errors.Is(err, myerrors.ErrUserExists):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrUserExists.Error()})
+	case errors.Is(err, myerrors.ErrUserHasSubscriptions):
+		c.JSON(http.StatusConflict, gin.H{"error": myerrors.ErrUserHasSubscriptions.Error()})
+	case errors.Is(err, myerrors.ErrInvalidTimezone):
+		c.JSON(http.StatusBadRequest, gin.H{"error": myerrors.ErrInvalidTimezone.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Log пишет уведомления в лог сервиса. Подходит для разработки и как канал по умолчанию
+type Log struct {
+	log *slog.Logger
+}
+
+func NewLog(log *slog.Logger) *Log {
+	return &Log{log: log}
+}
+
+func (l *Log) Notify(_ context.Context, n Notification) error {
+	l.log.Info("notification",
+		slog.String("event", n.Event),
+		slog.Any("user id", n.UserID),
+		slog.String("subject", n.Subject),
+		slog.String("text", n.Text))
+
+	return nil
+}
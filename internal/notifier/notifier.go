@@ -0,0 +1,52 @@
+// Package notifier delivers user notifications (reminders, alerts) through a pluggable channel
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/config"
+)
+
+// ErrNoRecipient - канал не может доставить уведомление этому пользователю (например, у него нет email).
+// Повторная попытка не поможет
+var ErrNoRecipient = errors.New("notification has no recipient for this channel")
+
+// Notification - уведомление пользователю. Data - подробности события для машинной обработки (webhook)
+type Notification struct {
+	Event   string    `json:"event"`
+	UserID  uuid.UUID `json:"user_id"`
+	Email   string    `json:"email,omitempty"`
+	Subject string    `json:"subject"`
+	Text    string    `json:"text"`
+	Data    any       `json:"data,omitempty"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// New создает Notifier по конфигурации: log (по умолчанию), webhook или smtp
+func New(log *slog.Logger, cfg config.Notifier) (Notifier, error) {
+	const op = "notifier.New"
+
+	switch cfg.NotifierType {
+	case "", "log":
+		return NewLog(log), nil
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("%s: webhook_url is required for webhook notifier", op)
+		}
+		return NewWebhook(cfg.WebhookURL, nil), nil
+	case "smtp":
+		if cfg.SMTP.Host == "" || cfg.SMTP.From == "" {
+			return nil, fmt.Errorf("%s: smtp host and from are required for smtp notifier", op)
+		}
+		return NewSMTP(cfg.SMTP), nil
+	default:
+		return nil, fmt.Errorf("%s: unknown notifier type %q", op, cfg.NotifierType)
+	}
+}
@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/odlev/subscriptions/internal/config"
+)
+
+// SMTP отправляет уведомление письмом на email пользователя.
+// Без username письмо уходит без авторизации - так работают локальные заглушки вроде Mailpit
+type SMTP struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTP(cfg config.SMTP) *SMTP {
+	s := &SMTP{
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		from: cfg.From,
+	}
+	if cfg.Username != "" {
+		s.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return s
+}
+
+func (s *SMTP) Notify(_ context.Context, n Notification) error {
+	const op = "notifier.smtp.Notify"
+
+	if n.Email == "" {
+		return fmt.Errorf("%s: %w", op, ErrNoRecipient)
+	}
+
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{n.Email}, s.message(n)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *SMTP) message(n Notification) []byte {
+	var msg bytes.Buffer
+
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", n.Email)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(n.Text)
+	msg.WriteString("\r\n")
+
+	return msg.Bytes()
+}
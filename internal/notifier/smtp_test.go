@@ -0,0 +1,183 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"mime"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/config"
+)
+
+// mail - письмо, принятое smtpStandIn
+type mail struct {
+	from string
+	to   []string
+	data string
+}
+
+// smtpStandIn - SMTP-сервер в процессе теста: принимает письма без TLS и авторизации, как Mailpit.
+// rejectRcpt отвечает 550 на RCPT TO
+type smtpStandIn struct {
+	addr       string
+	rejectRcpt bool
+	mails      chan mail
+}
+
+func newSMTPStandIn(t *testing.T, rejectRcpt bool) *smtpStandIn {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	s := &smtpStandIn{addr: ln.Addr().String(), rejectRcpt: rejectRcpt, mails: make(chan mail, 1)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP stand-in")
+
+	var m mail
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			_ = tp.PrintfLine("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			m = mail{from: strings.Trim(line[len("MAIL FROM:"):], "<> ")}
+			_ = tp.PrintfLine("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			if s.rejectRcpt {
+				_ = tp.PrintfLine("550 no such user")
+				continue
+			}
+			m.to = append(m.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			_ = tp.PrintfLine("250 OK")
+		case cmd == "DATA":
+			_ = tp.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			m.data = string(data)
+			s.mails <- m
+			_ = tp.PrintfLine("250 OK")
+		case cmd == "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("250 OK")
+		}
+	}
+}
+
+func standInConfig(t *testing.T, addr string) config.SMTP {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return config.SMTP{Host: host, Port: p, From: "noreply@example.com"}
+}
+
+func TestSMTPNotify(t *testing.T) {
+	standIn := newSMTPStandIn(t, false)
+	s := NewSMTP(standInConfig(t, standIn.addr))
+
+	n := Notification{
+		Event:   "subscription.expiring",
+		UserID:  uuid.New(),
+		Email:   "user@example.com",
+		Subject: "Подписка Netflix заканчивается 2025-12-31",
+		Text:    "Подписка Netflix (500 в месяц) действует до 2025-12-31 включительно.",
+	}
+	if err := s.Notify(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+
+	m := <-standIn.mails
+	if m.from != "noreply@example.com" {
+		t.Errorf("MAIL FROM: got %q", m.from)
+	}
+	if len(m.to) != 1 || m.to[0] != n.Email {
+		t.Errorf("RCPT TO: got %v", m.to)
+	}
+
+	header, body, ok := strings.Cut(m.data, "\n\n")
+	if !ok {
+		t.Fatalf("message has no header separator: %q", m.data)
+	}
+	msg, err := textproto.NewReader(bufio.NewReader(strings.NewReader(header + "\n\n"))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != n.Subject {
+		t.Errorf("Subject: got %q, want %q", subject, n.Subject)
+	}
+	if msg.Get("To") != n.Email || msg.Get("From") != "noreply@example.com" {
+		t.Errorf("To/From: got %q/%q", msg.Get("To"), msg.Get("From"))
+	}
+	if msg.Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type: got %q", msg.Get("Content-Type"))
+	}
+	if strings.TrimSpace(body) != n.Text {
+		t.Errorf("body: got %q, want %q", body, n.Text)
+	}
+}
+
+func TestSMTPNotifyErrors(t *testing.T) {
+	standIn := newSMTPStandIn(t, true)
+	s := NewSMTP(standInConfig(t, standIn.addr))
+
+	err := s.Notify(context.Background(), Notification{Subject: "s", Text: "t"})
+	if !errors.Is(err, ErrNoRecipient) {
+		t.Errorf("without email: got %v, want ErrNoRecipient", err)
+	}
+
+	err = s.Notify(context.Background(), Notification{Email: "missing@example.com", Subject: "s", Text: "t"})
+	if err == nil || errors.Is(err, ErrNoRecipient) {
+		t.Errorf("rejected recipient: got %v", err)
+	}
+	select {
+	case m := <-standIn.mails:
+		t.Errorf("message delivered to a rejected recipient: %+v", m)
+	default:
+	}
+}
@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// Webhook отправляет уведомление POST-запросом с телом Notification в JSON.
+// Ответ не 2xx считается ошибкой доставки
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook создает Webhook; client nil - http.Client с таймаутом 10 секунд
+func NewWebhook(url string, client *http.Client) *Webhook {
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+
+	return &Webhook{url: url, client: client}
+}
+
+func (w *Webhook) Notify(ctx context.Context, n Notification) error {
+	const op = "notifier.webhook.Notify"
+
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: webhook responded %d", op, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestWebhookNotify(t *testing.T) {
+	var got Notification
+	var method, contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, contentType = r.Method, r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := Notification{
+		Event:   "subscription.expiring",
+		UserID:  uuid.New(),
+		Email:   "user@example.com",
+		Subject: "Подписка Netflix заканчивается",
+		Text:    "Подписка Netflix действует до 2025-12-31 включительно.",
+	}
+	if err := NewWebhook(srv.URL, nil).Notify(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+
+	if method != http.MethodPost || contentType != "application/json" {
+		t.Errorf("request: got %s with %q", method, contentType)
+	}
+	if got != n {
+		t.Errorf("body: got %+v, want %+v", got, n)
+	}
+}
+
+func TestWebhookNotifyErrors(t *testing.T) {
+	for _, status := range []int{http.StatusMovedPermanently, http.StatusBadRequest, http.StatusInternalServerError} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		if err := NewWebhook(srv.URL, nil).Notify(context.Background(), Notification{}); err == nil {
+			t.Errorf("status %d: Notify succeeded", status)
+		}
+		srv.Close()
+	}
+
+	// ответ, который не приходит до отмены ctx
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := NewWebhook(srv.URL, nil).Notify(ctx, Notification{}); err == nil {
+		t.Error("Notify succeeded after the context was cancelled")
+	}
+}
@@ -0,0 +1,153 @@
+// Package reminders runs the background job that reminds users about subscriptions ending soon
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/odlev/subscriptions/internal/notifier"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// EventSubscriptionExpiring - тип уведомления о скором окончании подписки
+const EventSubscriptionExpiring = "subscription.expiring"
+
+// EventSubscriptionRenewing - тип уведомления о скором автопродлении подписки
+const EventSubscriptionRenewing = "subscription.renewing"
+
+// EventTrialEnding - тип уведомления о скором окончании пробного периода, после которого подписка станет платной
+const EventTrialEnding = "subscription.trial_ending"
+
+type ReminderStore interface {
+	DueReminders(windows []int, today time.Time) ([]storage.Reminder, error)
+	ClaimReminder(r storage.Reminder) (bool, error)
+	ReleaseReminder(r storage.Reminder) error
+}
+
+// Scheduler раз в interval ищет подписки, которые заканчиваются в пределах windows дней,
+// и отправляет напоминания через Notifier. Каждое напоминание отправляется один раз
+type Scheduler struct {
+	log      *slog.Logger
+	store    ReminderStore
+	notifier notifier.Notifier
+	windows  []int
+	interval time.Duration
+}
+
+func New(log *slog.Logger, store ReminderStore, n notifier.Notifier, windows []int, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		log:      log,
+		store:    store,
+		notifier: n,
+		windows:  windows,
+		interval: interval,
+	}
+}
+
+// Run выполняет проверку сразу и затем каждые interval, пока не отменен ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce отправляет напоминания, которые положено отправить на момент now
+func (s *Scheduler) RunOnce(ctx context.Context, now time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	due, err := s.store.DueReminders(s.windows, today)
+	if err != nil {
+		s.log.Error("failed to find due reminders", sl.Err(err))
+		return
+	}
+
+	for _, r := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		s.send(ctx, r)
+	}
+}
+
+func (s *Scheduler) send(ctx context.Context, r storage.Reminder) {
+	log := s.log.With(slog.String("kind", r.Kind), slog.Any("subscription id", r.SubscriptionID),
+		slog.Int("window days", r.WindowDays))
+
+	claimed, err := s.store.ClaimReminder(r)
+	if err != nil {
+		log.Error("failed to claim reminder", sl.Err(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	err = s.notifier.Notify(ctx, notification(r))
+	switch {
+	case err == nil:
+		log.Info("reminder sent")
+	case errors.Is(err, notifier.ErrNoRecipient):
+		// повтор не поможет, напоминание остается отмеченным
+		log.Warn("reminder has no recipient", sl.Err(err))
+	default:
+		log.Error("failed to send reminder", sl.Err(err))
+		if err := s.store.ReleaseReminder(r); err != nil {
+			log.Error("failed to release reminder", sl.Err(err))
+		}
+	}
+}
+
+func notification(r storage.Reminder) notifier.Notification {
+	lastDay := r.ExpiresAt.AddDate(0, 0, -1).Format(time.DateOnly)
+	data := map[string]any{
+		"subscription_id": r.SubscriptionID,
+		"service_name":    r.ServiceName,
+		"price":           r.Price,
+		"last_day":        lastDay,
+		"window_days":     r.WindowDays,
+		"auto_renew":      r.AutoRenew,
+	}
+
+	n := notifier.Notification{UserID: r.UserID, Data: data}
+	if r.Email != nil {
+		n.Email = *r.Email
+	}
+
+	switch {
+	case r.Kind == storage.ReminderTrialEnding:
+		data["trial_ends_at"] = r.EndDate.Format(storage.DateLayout)
+		n.Event = EventTrialEnding
+		n.Subject = fmt.Sprintf("Пробный период %s заканчивается %s", r.ServiceName, lastDay)
+		n.Text = fmt.Sprintf("Пробный период %s заканчивается %s, с %s подписка станет платной: %d в месяц.",
+			r.ServiceName, lastDay, r.ExpiresAt.Format(time.DateOnly), r.Price)
+	case r.AutoRenew:
+		newEnd := r.EndDate.AddDate(0, r.RenewalMonths, 0).Format(storage.DateLayout)
+		data["end_date"] = r.EndDate.Format(storage.DateLayout)
+		data["new_end_date"] = newEnd
+		n.Event = EventSubscriptionRenewing
+		n.Subject = fmt.Sprintf("Подписка %s будет продлена %s", r.ServiceName, r.ExpiresAt.Format(time.DateOnly))
+		n.Text = fmt.Sprintf("Подписка %s (%d в месяц) будет автоматически продлена до %s.",
+			r.ServiceName, r.Price, newEnd)
+	default:
+		data["end_date"] = r.EndDate.Format(storage.DateLayout)
+		n.Event = EventSubscriptionExpiring
+		n.Subject = fmt.Sprintf("Подписка %s заканчивается %s", r.ServiceName, lastDay)
+		n.Text = fmt.Sprintf("Подписка %s (%d в месяц) действует до %s включительно.",
+			r.ServiceName, r.Price, lastDay)
+	}
+
+	return n
+}
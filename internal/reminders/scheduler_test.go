@@ -0,0 +1,164 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/internal/notifier"
+	"github.com/odlev/subscriptions/internal/storage"
+)
+
+// memoryStore хранит отметки напоминаний в памяти так же, как subscription_reminders:
+// ClaimReminder проходит один раз на (подписка, вид, дата, окно).
+// DueReminders отдает все напоминания, даже отмеченные, как два экземпляра, прочитавшие список одновременно
+type memoryStore struct {
+	mu      sync.Mutex
+	due     []storage.Reminder
+	claimed map[storage.Reminder]bool
+}
+
+func (m *memoryStore) DueReminders(_ []int, _ time.Time) ([]storage.Reminder, error) {
+	return m.due, nil
+}
+
+func (m *memoryStore) ClaimReminder(r storage.Reminder) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := reminderKey(r)
+	if m.claimed[key] {
+		return false, nil
+	}
+	m.claimed[key] = true
+
+	return true, nil
+}
+
+func (m *memoryStore) ReleaseReminder(r storage.Reminder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.claimed, reminderKey(r))
+	return nil
+}
+
+// reminderKey - ключ subscription_reminders
+func reminderKey(r storage.Reminder) storage.Reminder {
+	return storage.Reminder{SubscriptionID: r.SubscriptionID, Kind: r.Kind, EndDate: r.EndDate, WindowDays: r.WindowDays}
+}
+
+// countingNotifier считает отправленные уведомления; err возвращается вместо доставки
+type countingNotifier struct {
+	mu   sync.Mutex
+	sent []notifier.Notification
+	err  error
+}
+
+func (c *countingNotifier) Notify(_ context.Context, n notifier.Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+	c.sent = append(c.sent, n)
+
+	return nil
+}
+
+func (c *countingNotifier) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.sent)
+}
+
+func testReminder() storage.Reminder {
+	email := "user@example.com"
+	return storage.Reminder{
+		Kind:           storage.ReminderExpiring,
+		SubscriptionID: uuid.New(),
+		UserID:         uuid.New(),
+		Email:          &email,
+		ServiceName:    "Netflix",
+		Price:          500,
+		EndDate:        time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:      time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		WindowDays:     7,
+	}
+}
+
+func newTestScheduler(store ReminderStore, n notifier.Notifier) *Scheduler {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), store, n, []int{7, 1}, time.Hour)
+}
+
+func TestSchedulerSendsReminderOnce(t *testing.T) {
+	store := &memoryStore{due: []storage.Reminder{testReminder()}, claimed: map[storage.Reminder]bool{}}
+	n := &countingNotifier{}
+	now := time.Date(2025, time.December, 26, 9, 0, 0, 0, time.UTC)
+
+	// повторный запуск и два экземпляра сервиса одновременно
+	first, second := newTestScheduler(store, n), newTestScheduler(store, n)
+	var wg sync.WaitGroup
+	for _, s := range []*Scheduler{first, second, first} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.RunOnce(context.Background(), now)
+		}()
+	}
+	wg.Wait()
+	first.RunOnce(context.Background(), now.Add(time.Hour))
+
+	if got := n.count(); got != 1 {
+		t.Fatalf("sent %d notifications, want 1", got)
+	}
+	if n.sent[0].Event != EventSubscriptionExpiring || n.sent[0].Email != "user@example.com" {
+		t.Errorf("notification: %+v", n.sent[0])
+	}
+
+	// напоминание для другого окна - отдельное
+	other := testReminder()
+	other.SubscriptionID, other.WindowDays = store.due[0].SubscriptionID, 1
+	store.due = append(store.due, other)
+	first.RunOnce(context.Background(), now.Add(2*time.Hour))
+	if got := n.count(); got != 2 {
+		t.Errorf("sent %d notifications after a new window, want 2", got)
+	}
+}
+
+func TestSchedulerRetriesFailedReminder(t *testing.T) {
+	store := &memoryStore{due: []storage.Reminder{testReminder()}, claimed: map[storage.Reminder]bool{}}
+	n := &countingNotifier{err: errors.New("connection refused")}
+	s := newTestScheduler(store, n)
+	now := time.Date(2025, time.December, 26, 9, 0, 0, 0, time.UTC)
+
+	s.RunOnce(context.Background(), now)
+	if len(store.claimed) != 0 {
+		t.Fatalf("failed reminder stays claimed: %v", store.claimed)
+	}
+
+	n.err = nil
+	s.RunOnce(context.Background(), now.Add(time.Hour))
+	s.RunOnce(context.Background(), now.Add(2*time.Hour))
+	if got := n.count(); got != 1 {
+		t.Errorf("sent %d notifications after retry, want 1", got)
+	}
+}
+
+func TestSchedulerKeepsReminderWithoutRecipient(t *testing.T) {
+	store := &memoryStore{due: []storage.Reminder{testReminder()}, claimed: map[storage.Reminder]bool{}}
+	n := &countingNotifier{err: notifier.ErrNoRecipient}
+	s := newTestScheduler(store, n)
+
+	s.RunOnce(context.Background(), time.Date(2025, time.December, 26, 9, 0, 0, 0, time.UTC))
+	if len(store.claimed) != 1 {
+		t.Errorf("reminder without recipient released: %v", store.claimed)
+	}
+}
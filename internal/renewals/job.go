@@ -0,0 +1,90 @@
+// Package renewals runs the daily job that extends auto-renewing subscriptions
+package renewals
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/odlev/subscriptions/internal/notifier"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// EventSubscriptionRenewed - тип уведомления о продлении подписки
+const EventSubscriptionRenewed = "subscription.renewed"
+
+type RenewalStore interface {
+	RenewDueSubscriptions(today time.Time) ([]storage.Renewal, error)
+}
+
+// Job раз в interval продлевает закончившиеся подписки с auto_renew и уведомляет о каждом продлении
+type Job struct {
+	log      *slog.Logger
+	store    RenewalStore
+	notifier notifier.Notifier
+	interval time.Duration
+}
+
+func New(log *slog.Logger, store RenewalStore, n notifier.Notifier, interval time.Duration) *Job {
+	return &Job{
+		log:      log,
+		store:    store,
+		notifier: n,
+		interval: interval,
+	}
+}
+
+// Run выполняет продление сразу и затем каждые interval, пока не отменен ctx
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.RunOnce(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce продлевает подписки, закончившиеся к моменту now.
+// Продление уже записано к моменту уведомления, поэтому ошибка доставки только логируется
+func (j *Job) RunOnce(ctx context.Context, now time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	renewed, err := j.store.RenewDueSubscriptions(today)
+	if err != nil {
+		j.log.Error("failed to renew subscriptions", sl.Err(err))
+		return
+	}
+
+	for _, r := range renewed {
+		log := j.log.With(slog.Any("subscription id", r.SubscriptionID), slog.String("new end date", r.NewEndDate))
+		log.Info("subscription renewed")
+
+		if err := j.notifier.Notify(ctx, notification(r)); err != nil {
+			log.Error("failed to send renewal notification", sl.Err(err))
+		}
+	}
+}
+
+func notification(r storage.Renewal) notifier.Notification {
+	n := notifier.Notification{
+		Event:   EventSubscriptionRenewed,
+		UserID:  r.UserID,
+		Subject: fmt.Sprintf("Подписка %s продлена до %s", r.ServiceName, r.NewEndDate),
+		Text: fmt.Sprintf("Подписка %s (%d в месяц) автоматически продлена: новый срок окончания - %s.",
+			r.ServiceName, r.Price, r.NewEndDate),
+		Data: r,
+	}
+	if r.Email != nil {
+		n.Email = *r.Email
+	}
+
+	return n
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+// GroupByStartMonth - группировка по месяцу start_date
+const GroupByStartMonth = "start_month"
+
+// типы столбцов AggregateResponse
+const (
+	ColumnString  = "string"
+	ColumnUUID    = "uuid"
+	ColumnMonth   = "month"
+	ColumnInteger = "integer"
+	ColumnNumber  = "number"
+)
+
+// метрики Aggregate, MetricCount - по умолчанию
+const (
+	MetricCount    = "count"
+	MetricSumPrice = "sum_price"
+	MetricAvgPrice = "avg_price"
+	MetricMinPrice = "min_price"
+	MetricMaxPrice = "max_price"
+)
+
+// aggregateGroup - столбцы группировки. selects - выражения столбцов, зависящие только от key
+type aggregateGroup struct {
+	columns []AggregateColumn
+	selects []string
+	key     string
+}
+
+var aggregateGroups = map[string]aggregateGroup{
+	GroupByService: {
+		columns: []AggregateColumn{{Name: "service_name", Type: ColumnString}},
+		selects: []string{"min(s.service_name)"},
+		key:     "lower(s.service_name)",
+	},
+	GroupByUser: {
+		columns: []AggregateColumn{{Name: "user_id", Type: ColumnUUID}},
+		selects: []string{"s.user_id::TEXT"},
+		key:     "s.user_id",
+	},
+	GroupByCategory: {
+		columns: []AggregateColumn{{Name: "category_id", Type: ColumnUUID}, {Name: "category_name", Type: ColumnString}},
+		selects: []string{"s.category_id::TEXT", "min(c.name)"},
+		key:     "s.category_id",
+	},
+	GroupByStartMonth: {
+		columns: []AggregateColumn{{Name: "start_month", Type: ColumnMonth}},
+		selects: []string{"to_char(date_trunc('month', s.start_date), 'YYYY-MM')"},
+		key:     "date_trunc('month', s.start_date)",
+	},
+}
+
+// aggregateMetrics - метрики по цене подписки в текущем месяце (p.price)
+var aggregateMetrics = map[string]struct {
+	kind string
+	sql  string
+}{
+	MetricCount:    {kind: ColumnInteger, sql: "COUNT(*)::BIGINT"},
+	MetricSumPrice: {kind: ColumnInteger, sql: "COALESCE(SUM(p.price), 0)::BIGINT"},
+	MetricAvgPrice: {kind: ColumnNumber, sql: "ROUND(AVG(p.price), 2)::FLOAT8"},
+	MetricMinPrice: {kind: ColumnInteger, sql: "MIN(p.price)::BIGINT"},
+	MetricMaxPrice: {kind: ColumnInteger, sql: "MAX(p.price)::BIGINT"},
+}
+
+// Aggregate считает метрики metrics по подпискам, подходящим под filter, в разрезе groupBy
+// (GroupByService, GroupByUser, GroupByCategory, GroupByStartMonth, можно несколько) одним запросом.
+// Без groupBy возвращает одну строку по всем подпискам, без metrics - только MetricCount.
+// Строки упорядочены по столбцам группировки
+func (s *Storage) Aggregate(filter SubscriptionFilter, groupBy, metrics []string) (*AggregateResponse, error) {
+	const op = "storage.aggregate.Aggregate"
+
+	if len(metrics) == 0 {
+		metrics = []string{MetricCount}
+	}
+
+	resp := &AggregateResponse{Columns: []AggregateColumn{}, Rows: [][]any{}}
+	var selects, keys, order []string
+	for i, name := range groupBy {
+		group, ok := aggregateGroups[name]
+		if !ok || slices.Contains(groupBy[:i], name) {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrInvalidGroupBy)
+		}
+		resp.Columns = append(resp.Columns, group.columns...)
+		selects = append(selects, group.selects...)
+		keys = append(keys, group.key)
+		order = append(order, group.key+" NULLS LAST")
+	}
+	for i, name := range metrics {
+		metric, ok := aggregateMetrics[name]
+		if !ok || slices.Contains(metrics[:i], name) {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrInvalidMetric)
+		}
+		resp.Columns = append(resp.Columns, AggregateColumn{Name: name, Type: metric.kind})
+		selects = append(selects, metric.sql)
+	}
+
+	conds, args, err := filter.where("s.", nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `SELECT ` + strings.Join(selects, ", ") + `
+	FROM subscriptions s
+	LEFT JOIN categories c ON c.id = s.category_id
+	CROSS JOIN LATERAL (SELECT subscription_price_at(s.id, date_trunc('month', NOW())::DATE) AS price) p
+	WHERE 1 = 1` + conds
+	if len(keys) > 0 {
+		query += `
+	GROUP BY ` + strings.Join(keys, ", ") + `
+	ORDER BY ` + strings.Join(order, ", ")
+	}
+
+	rows, err := s.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	// столбцы приведены в запросе к TEXT, BIGINT и FLOAT8, поэтому значения - string, int64, float64 или nil
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		resp.Rows = append(resp.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,377 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// budgetThresholds - пороги расхода в процентах от бюджета, при пересечении которых создается BudgetAlert
+var budgetThresholds = []int{80, 100}
+
+const budgetColumns = `id, user_id, category_id, currency, amount, created_at, updated_at`
+
+func scanBudget(row pgx.Row) (*Budget, error) {
+	var b Budget
+	if err := row.Scan(&b.ID, &b.UserID, &b.CategoryID, &b.Currency, &b.Amount, &b.CreatedAt, &b.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// budgetError переводит ошибки PostgreSQL при записи бюджета в ошибки myerrors
+func budgetError(err error) error {
+	switch {
+	case pgErrCode(err) == pgUniqueViolation:
+		return myerrors.ErrBudgetExists
+	case pgConstraint(err) == "budgets_user_id_fkey":
+		return myerrors.ErrUnknownUser
+	case pgConstraint(err) == "budgets_category_id_fkey":
+		return myerrors.ErrUnknownCategory
+	}
+
+	return err
+}
+
+// BudgetAlertHandler получает события пересечения порогов бюджета
+type BudgetAlertHandler func(alert BudgetAlert)
+
+// OnBudgetAlert регистрирует обработчик событий бюджета. Вызывать до начала работы с хранилищем
+func (s *Storage) OnBudgetAlert(handler BudgetAlertHandler) {
+	s.budgetAlertHandlers = append(s.budgetAlertHandlers, handler)
+}
+
+func (s *Storage) CreateBudget(req BudgetCreateRequest) (*Budget, error) {
+	const op = "storage.budgets.CreateBudget"
+
+	// по умолчанию валюта пользователя; для неизвестного пользователя 'RUB', вставку все равно отклонит внешний ключ
+	query := `INSERT INTO budgets (user_id, category_id, currency, amount)
+	VALUES ($1, $2, COALESCE(NULLIF($3, ''), (SELECT default_currency FROM users WHERE id = $1), 'RUB'), $4)
+	RETURNING ` + budgetColumns
+
+	budget, err := scanBudget(s.db.QueryRow(context.Background(), query,
+		req.UserID, req.CategoryID, strings.ToUpper(req.Currency), req.Amount))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, budgetError(err))
+	}
+
+	s.checkBudgets(budget.UserID)
+
+	return budget, nil
+}
+
+func (s *Storage) GetBudget(id uuid.UUID) (*Budget, error) {
+	const op = "storage.budgets.GetBudget"
+
+	budget, err := scanBudget(s.db.QueryRow(context.Background(), `SELECT `+budgetColumns+` FROM budgets WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrBudgetNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return budget, nil
+}
+
+// ListBudgets возвращает бюджеты пользователя или все, если userID пустой
+func (s *Storage) ListBudgets(userID string) ([]Budget, error) {
+	const op = "storage.budgets.ListBudgets"
+
+	query := `SELECT ` + budgetColumns + ` FROM budgets WHERE 1 = 1`
+	args := []any{}
+
+	if userID != "" {
+		if _, err := uuid.Parse(userID); err != nil {
+			return nil, fmt.Errorf("%s: failed parse id: %w", op, err)
+		}
+		args = append(args, userID)
+		query = query + " AND user_id = $1"
+	}
+	query = query + " ORDER BY created_at, id"
+
+	rows, err := s.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	budgets := []Budget{}
+	for rows.Next() {
+		budget, err := scanBudget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		budgets = append(budgets, *budget)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return budgets, nil
+}
+
+func (s *Storage) UpdateBudget(id uuid.UUID, req BudgetUpdateRequest) (*Budget, error) {
+	const op = "storage.budgets.UpdateBudget"
+
+	query := `UPDATE budgets SET
+		currency = COALESCE(NULLIF($1, ''), currency),
+		amount = COALESCE(NULLIF($2, 0), amount),
+		updated_at = NOW()
+	WHERE id = $3
+	RETURNING ` + budgetColumns
+
+	budget, err := scanBudget(s.db.QueryRow(context.Background(), query, strings.ToUpper(req.Currency), req.Amount, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrBudgetNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.checkBudgets(budget.UserID)
+
+	return budget, nil
+}
+
+func (s *Storage) DeleteBudget(id uuid.UUID) error {
+	const op = "storage.budgets.DeleteBudget"
+
+	tag, err := s.db.Exec(context.Background(), `DELETE FROM budgets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, myerrors.ErrBudgetNotFound)
+	}
+
+	return nil
+}
+
+// GetBudgetStatus считает расход по бюджету за месяц month (YYYY-MM).
+// Пустой month - текущий месяц в часовом поясе пользователя
+func (s *Storage) GetBudgetStatus(id uuid.UUID, month string) (*BudgetStatus, error) {
+	const op = "storage.budgets.GetBudgetStatus"
+
+	ctx := context.Background()
+
+	budget, err := s.GetBudget(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var timezone string
+	if err := s.db.QueryRow(ctx, `SELECT timezone FROM users WHERE id = $1`, budget.UserID).Scan(&timezone); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	monthDate := currentMonth(timezone)
+	if month != "" {
+		if monthDate, err = time.Parse(DateLayout, month); err != nil {
+			return nil, fmt.Errorf("%s: invalid month: %w", op, err)
+		}
+	}
+
+	spent, err := s.budgetSpend(ctx, budget, monthDate)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	projected, err := s.budgetSpend(ctx, budget, monthDate.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	status := &BudgetStatus{
+		Budget:    *budget,
+		Month:     monthDate.Format(DateLayout),
+		Spent:     spent,
+		Projected: projected,
+		Percent:   spent * 100 / budget.Amount,
+		Alerts:    []BudgetAlert{},
+	}
+	for _, threshold := range budgetThresholds {
+		if status.Percent >= threshold {
+			status.Threshold = threshold
+		}
+	}
+
+	rows, err := s.db.Query(ctx, `SELECT threshold, spent, amount, created_at FROM budget_alerts
+	WHERE budget_id = $1 AND month = $2 ORDER BY threshold`, id, monthDate)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		alert := newBudgetAlert(budget, monthDate)
+		if err := rows.Scan(&alert.Threshold, &alert.Spent, &alert.Amount, &alert.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		status.Alerts = append(status.Alerts, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return status, nil
+}
+
+// subscriptionCurrency - валюта цены подписки: валюта сервиса из каталога, иначе валюта владельца
+const subscriptionCurrency = `COALESCE(
+	(SELECT default_currency FROM services WHERE services.id = subscriptions.service_id),
+	(SELECT default_currency FROM users WHERE users.id = subscriptions.user_id))`
+
+// budgetSpend - стоимость подписок бюджета, действующих в месяце month.
+// Курсов валют в сервисе нет, поэтому суммируются только подписки в валюте бюджета,
+// подписки в других валютах в расход не попадают
+func (s *Storage) budgetSpend(ctx context.Context, budget *Budget, month time.Time) (int, error) {
+	filter := SubscriptionFilter{UserID: budget.UserID.String()}
+	if budget.CategoryID != nil {
+		filter.CategoryID = budget.CategoryID.String()
+	}
+
+	conds, args, err := filter.where("subscriptions.", []any{month})
+	if err != nil {
+		return 0, err
+	}
+	args = append(args, budget.Currency)
+	conds += fmt.Sprintf(" AND %s = $%d", subscriptionCurrency, len(args))
+
+	// пользователь платит только свою долю общих подписок
+	cost, args := filter.costExpr("id", "$1::DATE", args)
+
+	var spent int
+	err = s.db.QueryRow(ctx, `SELECT ROUND(COALESCE(SUM(`+cost+`), 0))::BIGINT FROM subscriptions
+	WHERE start_date <= $1 AND (end_date IS NULL OR end_date >= $1)`+conds, args...).Scan(&spent)
+
+	return spent, err
+}
+
+// checkBudgets пересчитывает бюджеты пользователя за текущий месяц и передает обработчикам
+// пороги, пересеченные впервые за этот месяц. Вызывается после записи подписки или бюджета;
+// ошибки только логируются, чтобы не отменять уже выполненную запись
+func (s *Storage) checkBudgets(userID uuid.UUID) {
+	const op = "storage.budgets.checkBudgets"
+
+	ctx := context.Background()
+
+	var timezone string
+	if err := s.db.QueryRow(ctx, `SELECT timezone FROM users WHERE id = $1`, userID).Scan(&timezone); err != nil {
+		s.log.Error("failed to check budgets", slog.String("op", op), sl.Err(err))
+		return
+	}
+
+	budgets, err := s.ListBudgets(userID.String())
+	if err != nil {
+		s.log.Error("failed to check budgets", slog.String("op", op), sl.Err(err))
+		return
+	}
+
+	month := currentMonth(timezone)
+	for i := range budgets {
+		if err := s.checkBudget(ctx, &budgets[i], month); err != nil {
+			s.log.Error("failed to check budget", slog.String("op", op), slog.Any("budget id", budgets[i].ID), sl.Err(err))
+		}
+	}
+}
+
+func (s *Storage) checkBudget(ctx context.Context, budget *Budget, month time.Time) error {
+	spent, err := s.budgetSpend(ctx, budget, month)
+	if err != nil {
+		return err
+	}
+
+	for _, threshold := range budgetThresholds {
+		if spent*100 < budget.Amount*threshold {
+			break
+		}
+
+		alert := newBudgetAlert(budget, month)
+		alert.Threshold = threshold
+		alert.Spent = spent
+
+		err := s.db.QueryRow(ctx, `INSERT INTO budget_alerts (budget_id, month, threshold, spent, amount)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING
+		RETURNING created_at`, budget.ID, month, threshold, spent, budget.Amount).Scan(&alert.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// порог в этом месяце уже пересекался
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, handler := range s.budgetAlertHandlers {
+			handler(alert)
+		}
+	}
+
+	return nil
+}
+
+// checkSubscriptionBudgets пересчитывает бюджеты владельца и участников подписки
+func (s *Storage) checkSubscriptionBudgets(subscriptionID uuid.UUID) {
+	const op = "storage.budgets.checkSubscriptionBudgets"
+
+	rows, err := s.db.Query(context.Background(), `SELECT user_id FROM subscriptions WHERE id = $1
+	UNION
+	SELECT user_id FROM subscription_members WHERE subscription_id = $1`, subscriptionID)
+	if err != nil {
+		s.log.Error("failed to check budgets", slog.String("op", op), sl.Err(err))
+		return
+	}
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			s.log.Error("failed to check budgets", slog.String("op", op), sl.Err(err))
+			return
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		s.log.Error("failed to check budgets", slog.String("op", op), sl.Err(err))
+		return
+	}
+
+	for _, userID := range userIDs {
+		s.checkBudgets(userID)
+	}
+}
+
+func newBudgetAlert(budget *Budget, month time.Time) BudgetAlert {
+	return BudgetAlert{
+		BudgetID:   budget.ID,
+		UserID:     budget.UserID,
+		CategoryID: budget.CategoryID,
+		Month:      month.Format(DateLayout),
+		Amount:     budget.Amount,
+		Currency:   budget.Currency,
+	}
+}
+
+// currentMonth - первое число текущего месяца в часовом поясе timezone (UTC, если пояс неизвестен)
+func currentMonth(timezone string) time.Time {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+const categoryColumns = `id, name, parent_id, created_at, updated_at`
+
+func scanCategory(row pgx.Row) (*Category, error) {
+	var c Category
+	if err := row.Scan(&c.ID, &c.Name, &c.ParentID, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// categoryError переводит ошибки PostgreSQL при записи категории в ошибки myerrors
+func categoryError(err error) error {
+	switch pgErrCode(err) {
+	case pgUniqueViolation:
+		return myerrors.ErrCategoryExists
+	case pgForeignKeyViolation:
+		return myerrors.ErrUnknownCategory
+	}
+
+	return err
+}
+
+func (s *Storage) CreateCategory(req CategoryCreateRequest) (*Category, error) {
+	const op = "storage.categories.CreateCategory"
+
+	query := `INSERT INTO categories (name, parent_id) VALUES ($1, $2) RETURNING ` + categoryColumns
+
+	category, err := scanCategory(s.db.QueryRow(context.Background(), query, strings.TrimSpace(req.Name), req.ParentID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, categoryError(err))
+	}
+
+	return category, nil
+}
+
+func (s *Storage) GetCategory(id uuid.UUID) (*Category, error) {
+	const op = "storage.categories.GetCategory"
+
+	category, err := scanCategory(s.db.QueryRow(context.Background(), `SELECT `+categoryColumns+` FROM categories WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrCategoryNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return category, nil
+}
+
+// ListCategories возвращает все категории плоским списком, дерево строится по parent_id
+func (s *Storage) ListCategories() ([]Category, error) {
+	const op = "storage.categories.ListCategories"
+
+	rows, err := s.db.Query(context.Background(), `SELECT `+categoryColumns+` FROM categories ORDER BY name, id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	categories := []Category{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		categories = append(categories, *category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return categories, nil
+}
+
+// UpdateCategory переименовывает и/или переносит категорию.
+// Перенос внутрь собственного поддерева запрещен
+func (s *Storage) UpdateCategory(id uuid.UUID, req CategoryUpdateRequest) (*Category, error) {
+	const op = "storage.categories.UpdateCategory"
+
+	ctx := context.Background()
+
+	var category *Category
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		current, err := scanCategory(tx.QueryRow(ctx, `SELECT `+categoryColumns+` FROM categories WHERE id = $1 FOR UPDATE`, id))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return myerrors.ErrCategoryNotFound
+			}
+			return err
+		}
+
+		parentID := current.ParentID
+		if req.ParentID != nil {
+			parentID = nil
+			if *req.ParentID != uuid.Nil {
+				parentID = req.ParentID
+			}
+		}
+
+		if parentID != nil {
+			var cycle bool
+			err := tx.QueryRow(ctx, `WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE id = $1
+				UNION ALL
+				SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id)
+			SELECT EXISTS (SELECT 1 FROM tree WHERE id = $2)`, id, *parentID).Scan(&cycle)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return myerrors.ErrCategoryCycle
+			}
+		}
+
+		category, err = scanCategory(tx.QueryRow(ctx, `UPDATE categories SET
+			name = COALESCE(NULLIF($1, ''), name),
+			parent_id = $2,
+			updated_at = NOW()
+		WHERE id = $3
+		RETURNING `+categoryColumns,
+			strings.TrimSpace(req.Name), parentID, id))
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, categoryError(err))
+	}
+
+	return category, nil
+}
+
+// DeleteCategory удаляет категорию без дочерних. Подписки категории остаются без категории
+func (s *Storage) DeleteCategory(id uuid.UUID) error {
+	const op = "storage.categories.DeleteCategory"
+
+	err := s.cascade(catalogChange{
+		before: true,
+		affected: func(ctx context.Context, tx pgx.Tx) ([]uuid.UUID, error) {
+			return subscriptionIDs(ctx, tx, `SELECT id FROM subscriptions WHERE category_id = $1 ORDER BY id`, id)
+		},
+		write: func(ctx context.Context, tx pgx.Tx) error {
+			tag, err := tx.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+			if err != nil {
+				if pgErrCode(err) == pgForeignKeyViolation {
+					return myerrors.ErrCategoryHasChildren
+				}
+				return err
+			}
+			if tag.RowsAffected() == 0 {
+				return myerrors.ErrCategoryNotFound
+			}
+			return nil
+		},
+		decide: func(strm *stream) error { return strm.emit(categoryChangedEvent{}) },
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxListLimit - наибольший размер страницы списка подписок
+const MaxListLimit = 1000
+
+// cursorDateLayout - start_date в курсоре
+const cursorDateLayout = "2006-01-02"
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor возвращает курсор следующей страницы списка после подписки sub: страницы идут
+// по (start_date, id), поэтому курсор - эта пара, а не номер страницы, и вставки не сдвигают страницы
+func EncodeCursor(sub Subscription) string {
+	raw := sub.StartDate.Format(cursorDateLayout) + "|" + sub.ID.String()
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor разбирает курсор EncodeCursor
+func decodeCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+	date, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+
+	start, err := time.Parse(cursorDateLayout, date)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+
+	return start, parsed, nil
+}
+
+// limit разбирает размер страницы, пустой дает nil
+func (e *FilterError) limit(param, value string) *int {
+	if value == "" {
+		return nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > MaxListLimit {
+		e.add(param, param+" must be an integer between 1 and "+strconv.Itoa(MaxListLimit), nil)
+		return nil
+	}
+
+	return &limit
+}
+
+// limitClause - LIMIT страницы списка, Limit уже проверен в where
+func (f SubscriptionFilter) limitClause() string {
+	limit, err := strconv.Atoi(f.Limit)
+	if f.Limit == "" || err != nil {
+		return ""
+	}
+
+	return " LIMIT " + strconv.Itoa(limit)
+}
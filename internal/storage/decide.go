@@ -0,0 +1,423 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+// decideCreate записывает событие created и статус, который следует из дат новой подписки в месяце month.
+// Начальный статус выставляется без проверки перехода
+func decideCreate(s *stream, created createdEvent, month time.Time) error {
+	created.Status = StatusActive
+	if err := s.emit(created); err != nil {
+		return err
+	}
+
+	derived, err := s.statusAt(month)
+	if err != nil || derived == StatusActive {
+		return err
+	}
+
+	return s.emit(statusChangedEvent{From: StatusActive, To: derived})
+}
+
+// subscriptionChange - изменение подписки для decideChange; nil, false и 0 - поле не меняется
+type subscriptionChange struct {
+	startDate, endDate *time.Time
+	service            *serviceChangedEvent
+	owner              *uuid.UUID
+	categorySet        bool
+	categoryID         *uuid.UUID
+	autoRenew          *bool
+	renewalMonths      int
+	trialSet           bool
+	trial              *time.Time
+	// introPrice и introMonths - как в UpdateSubscription: 0 убирает вступительную цену
+	introPrice, introMonths *int
+	tagsSet                 bool
+	tags                    []string
+	price                   *int
+	// priceFrom - месяц, с которого действует price, по умолчанию текущий
+	priceFrom *time.Time
+}
+
+// decideChange записывает события изменившихся групп полей: dates_changed, service_changed, owner_changed,
+// category_changed, renewal_changed, intro_changed, tags_changed, price_changed и смену статуса,
+// если она следует из новых дат. month - текущий месяц владельца
+func decideChange(s *stream, change subscriptionChange, month time.Time) error {
+	st := s.state
+	if st == nil {
+		return myerrors.ErrNotFound
+	}
+
+	dates := datesChangedEvent{StartDate: st.StartDate, EndDate: st.EndDate, TrialEndsAt: st.TrialEndsAt}
+	if change.startDate != nil {
+		dates.StartDate = *change.startDate
+	}
+	if change.endDate != nil {
+		dates.EndDate = change.endDate
+	}
+	if change.trialSet {
+		dates.TrialEndsAt = change.trial
+	}
+	if dates.EndDate != nil && dates.EndDate.Before(dates.StartDate) {
+		return myerrors.ErrInvalidDateRange
+	}
+	if !dates.StartDate.Equal(st.StartDate) || !sameMonth(dates.EndDate, st.EndDate) || !sameMonth(dates.TrialEndsAt, st.TrialEndsAt) {
+		if err := s.emit(dates); err != nil {
+			return err
+		}
+	}
+
+	if change.service != nil && (change.service.ServiceName != st.ServiceName || !sameID(change.service.ServiceID, st.ServiceID)) {
+		if err := s.emit(*change.service); err != nil {
+			return err
+		}
+	}
+	if change.owner != nil && *change.owner != st.UserID {
+		if err := s.emit(ownerChangedEvent{UserID: *change.owner}); err != nil {
+			return err
+		}
+	}
+	if change.categorySet && !sameID(change.categoryID, st.CategoryID) {
+		if err := s.emit(categoryChangedEvent{CategoryID: change.categoryID}); err != nil {
+			return err
+		}
+	}
+
+	renewal := renewalChangedEvent{AutoRenew: st.AutoRenew, RenewalMonths: st.RenewalMonths}
+	if change.autoRenew != nil {
+		renewal.AutoRenew = *change.autoRenew
+	}
+	if change.renewalMonths != 0 {
+		renewal.RenewalMonths = change.renewalMonths
+	}
+	if renewal.AutoRenew != st.AutoRenew || renewal.RenewalMonths != st.RenewalMonths {
+		if err := s.emit(renewal); err != nil {
+			return err
+		}
+	}
+
+	intro := introChangedEvent{IntroPrice: st.IntroPrice, IntroMonths: st.IntroMonths}
+	if change.introPrice != nil {
+		intro.IntroPrice = nonZero(*change.introPrice)
+	}
+	if change.introMonths != nil {
+		intro.IntroMonths = nonZero(*change.introMonths)
+	}
+	// нулевая вступительная цена убирает и ее срок
+	if change.introPrice != nil && *change.introPrice == 0 {
+		intro.IntroMonths = nil
+	}
+	if (intro.IntroPrice == nil) != (intro.IntroMonths == nil) {
+		return myerrors.ErrInvalidIntro
+	}
+	if !sameInt(intro.IntroPrice, st.IntroPrice) || !sameInt(intro.IntroMonths, st.IntroMonths) {
+		if err := s.emit(intro); err != nil {
+			return err
+		}
+	}
+
+	if change.tagsSet {
+		if err := decideTags(s, func([]string) []string { return change.tags }); err != nil {
+			return err
+		}
+	}
+
+	if change.price != nil {
+		// период не начинается раньше подписки; если в этом месяце и так действует price, его нет
+		from := month
+		if change.priceFrom != nil {
+			from = *change.priceFrom
+		}
+		if from.Before(s.state.StartDate) {
+			from = s.state.StartDate
+		}
+		current, err := s.priceAt(from)
+		if err != nil {
+			return err
+		}
+		if current != *change.price {
+			if err := s.emit(priceChangedEvent{EffectiveFrom: from, Price: *change.price}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return decideStatus(s, month)
+}
+
+// decideStatus переводит подписку в статус, который следует из ее дат и пауз в месяце month.
+// Отмененную и истекшую подписку не трогает: статус остается до ReactivateSubscription
+func decideStatus(s *stream, month time.Time) error {
+	st := s.state
+	if st.Status == StatusCancelled || st.Status == StatusExpired {
+		return nil
+	}
+	derived, err := s.statusAt(month)
+	if err != nil || st.Status == derived {
+		return err
+	}
+	if err := checkTransition(st.Status, derived); err != nil {
+		return err
+	}
+
+	return s.emit(statusChangedEvent{From: st.Status, To: derived})
+}
+
+// decideTags записывает tags_changed, если set меняет набор тегов подписки
+func decideTags(s *stream, set func(current []string) []string) error {
+	if s.state == nil {
+		return myerrors.ErrNotFound
+	}
+
+	tags := sortedTags(set(slices.Clone(s.state.Tags)))
+	if slices.Equal(tags, s.state.Tags) {
+		return nil
+	}
+
+	return s.emit(tagsChangedEvent{Tags: tags})
+}
+
+// decideCancel записывает cancelled с последним месяцем effective_date, по умолчанию month.
+// Паузы, начинающиеся после этого месяца, удаляются, остальные обрезаются по нему событиями resumed
+func decideCancel(s *stream, req CancelRequest, month, cancelledAt time.Time) error {
+	st := s.state
+	if st == nil {
+		return myerrors.ErrNotFound
+	}
+	if err := checkTransition(st.Status, StatusCancelled); err != nil {
+		return err
+	}
+
+	effective, err := parseMonthOr(req.EffectiveDate, month, "effective_date")
+	if err != nil {
+		return err
+	}
+	if effective.Before(st.StartDate) || (st.EndDate != nil && effective.After(*st.EndDate)) {
+		return myerrors.ErrInvalidCancelDate
+	}
+
+	for _, p := range st.Pauses {
+		resumed := resumedEvent{PauseID: p.ID}
+		switch {
+		case p.StartMonth.After(effective):
+			// end_month nil - пауза удаляется целиком
+		case p.EndMonth == nil || p.EndMonth.After(effective):
+			resumed.EndMonth = &effective
+		default:
+			continue
+		}
+		if err := s.emit(resumed); err != nil {
+			return err
+		}
+	}
+
+	var reason *string
+	if req.Reason != "" {
+		reason = &req.Reason
+	}
+
+	return s.emit(cancelledEvent{EndDate: effective, Reason: reason, CancelledAt: cancelledAt})
+}
+
+// decideReactivate записывает reactivated и статус, который следует из новых дат и пауз в месяце month
+func decideReactivate(s *stream, req ReactivateRequest, month time.Time) error {
+	st := s.state
+	if st == nil {
+		return myerrors.ErrNotFound
+	}
+	if st.Status != StatusCancelled && st.Status != StatusExpired {
+		return fmt.Errorf("%w: %s is not cancelled or expired", myerrors.ErrInvalidTransition, st.Status)
+	}
+
+	end, err := reactivationEnd(st.StartDate, st.EndDate, month, req.EndDate)
+	if err != nil {
+		return err
+	}
+
+	from := st.Status
+	if err := s.emit(reactivatedEvent{EndDate: end}); err != nil {
+		return err
+	}
+	derived, err := s.statusAt(month)
+	if err != nil {
+		return err
+	}
+	if err := checkTransition(from, derived); err != nil {
+		return err
+	}
+
+	return s.emit(statusChangedEvent{From: from, To: derived})
+}
+
+// decidePause записывает paused с месяца from (по умолчанию month) по until и смену статуса
+func decidePause(s *stream, req PauseRequest, month time.Time) error {
+	st := s.state
+	if st == nil {
+		return myerrors.ErrNotFound
+	}
+
+	from, err := parseMonthOr(req.From, month, "from")
+	if err != nil {
+		return err
+	}
+	var until *time.Time
+	if req.Until != "" {
+		parsed, err := time.Parse(DateLayout, req.Until)
+		if err != nil {
+			return invalidRequest("until", err)
+		}
+		until = &parsed
+	}
+
+	end := st.EndDate
+	if from.Before(st.StartDate) || (end != nil && from.After(*end)) ||
+		(until != nil && (until.Before(from) || (end != nil && until.After(*end)))) {
+		return myerrors.ErrInvalidPause
+	}
+
+	overlaps := slices.ContainsFunc(st.Pauses, func(p statePause) bool {
+		return (until == nil || !p.StartMonth.After(*until)) && (p.EndMonth == nil || !p.EndMonth.Before(from))
+	})
+	if overlaps {
+		return myerrors.ErrPauseOverlap
+	}
+
+	if err := s.emit(pausedEvent{PauseID: uuid.New(), StartMonth: from, EndMonth: until}); err != nil {
+		return err
+	}
+
+	return decideStatus(s, month)
+}
+
+// decideResume записывает resumed для паузы, в которую попадает from (по умолчанию month), и смену статуса
+func decideResume(s *stream, req ResumeRequest, month time.Time) error {
+	st := s.state
+	if st == nil {
+		return myerrors.ErrNotFound
+	}
+
+	from, err := parseMonthOr(req.From, month, "from")
+	if err != nil {
+		return err
+	}
+
+	// паузы не пересекаются, но берется последняя начавшаяся, как в ResumeSubscription
+	var pause *statePause
+	for i, p := range st.Pauses {
+		if !p.StartMonth.After(from) && (p.EndMonth == nil || !p.EndMonth.Before(from)) {
+			pause = &st.Pauses[i]
+		}
+	}
+	if pause == nil {
+		return myerrors.ErrNotPaused
+	}
+
+	resumed := resumedEvent{PauseID: pause.ID}
+	if pause.StartMonth.Before(from) {
+		end := from.AddDate(0, -1, 0)
+		resumed.EndMonth = &end
+	}
+	if err := s.emit(resumed); err != nil {
+		return err
+	}
+
+	return decideStatus(s, month)
+}
+
+// decideRenewals записывает renewed за каждый срок, пропущенный подпиской с auto_renew к дате today,
+// но не больше maxRenewalPasses
+func decideRenewals(s *stream, today time.Time) error {
+	due := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for range maxRenewalPasses {
+		st := s.state
+		if st == nil || !st.AutoRenew || st.Status == StatusCancelled || st.EndDate == nil || !st.EndDate.Before(due) {
+			return nil
+		}
+
+		err := s.emit(renewedEvent{
+			RenewalID:       uuid.New(),
+			PreviousEndDate: *st.EndDate,
+			NewEndDate:      st.EndDate.AddDate(0, st.RenewalMonths, 0),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decideService записывает service_changed, если сервис подписки или его название в каталоге изменились
+func decideService(s *stream, name string, id *uuid.UUID) error {
+	if s.state == nil || (s.state.ServiceName == name && sameID(s.state.ServiceID, id)) {
+		return nil
+	}
+
+	return s.emit(serviceChangedEvent{ServiceName: name, ServiceID: id})
+}
+
+// decideMember записывает member_set, если участника нет или его доля другая
+func decideMember(s *stream, set memberSetEvent) error {
+	if s.state == nil {
+		return myerrors.ErrNotFound
+	}
+
+	i := slices.IndexFunc(s.state.Members, func(m stateMember) bool { return m.UserID == set.UserID })
+	if i >= 0 && sameInt(s.state.Members[i].Weight, set.Weight) && sameInt(s.state.Members[i].Amount, set.Amount) {
+		return nil
+	}
+
+	return s.emit(set)
+}
+
+// decideRemoveMember записывает member_removed; ErrMemberNotFound - пользователь не участник подписки
+func decideRemoveMember(s *stream, userID uuid.UUID) error {
+	if s.state == nil {
+		return myerrors.ErrNotFound
+	}
+	if !slices.ContainsFunc(s.state.Members, func(m stateMember) bool { return m.UserID == userID }) {
+		return myerrors.ErrMemberNotFound
+	}
+
+	return s.emit(memberRemovedEvent{UserID: userID})
+}
+
+func sameMonth(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+func sameID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func sameInt(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// nonZero - nil для нуля, иначе указатель на value
+func nonZero(value int) *int {
+	if value == 0 {
+		return nil
+	}
+
+	return &value
+}
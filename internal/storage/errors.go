@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+// коды ошибок PostgreSQL, которые переводятся в ошибки myerrors
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgCheckViolation      = "23514"
+)
+
+// pgErrCode возвращает SQLSTATE ошибки PostgreSQL или пустую строку
+func pgErrCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+
+	return ""
+}
+
+// pgConstraint возвращает имя нарушенного ограничения, по нему различаются внешние ключи одной таблицы
+func pgConstraint(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+
+	return ""
+}
+
+// subscriptionFKError переводит нарушение внешнего ключа или проверки subscriptions и ее участников в ошибку myerrors
+func subscriptionFKError(err error) error {
+	if code := pgErrCode(err); code != pgForeignKeyViolation && code != pgCheckViolation {
+		return err
+	}
+
+	switch pgConstraint(err) {
+	case "subscriptions_user_id_fkey", "subscription_members_user_id_fkey":
+		return myerrors.ErrUnknownUser
+	case "subscriptions_category_id_fkey":
+		return myerrors.ErrUnknownCategory
+	case "subscriptions_trial_check":
+		return myerrors.ErrInvalidTrial
+	case "subscriptions_intro_check", "subscriptions_intro_price_check", "subscriptions_intro_months_check":
+		return myerrors.ErrInvalidIntro
+	}
+
+	return err
+}
+
+// invalidRequest помечает ошибку разбора поля запроса как myerrors.ErrInvalidRequest
+func invalidRequest(field string, err error) error {
+	return fmt.Errorf("%w: %s: %w", myerrors.ErrInvalidRequest, field, err)
+}
@@ -0,0 +1,485 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы событий подписки. Каждая команда над подпиской решает, какие события с ней произошли,
+// и проекция subscriptions строится только из них; EventStore, кроме того, записывает их в журнал subscription_events
+const (
+	EventCreated         = "created"
+	EventImported        = "imported"
+	EventPriceChanged    = "price_changed"
+	EventDatesChanged    = "dates_changed"
+	EventServiceChanged  = "service_changed"
+	EventCategoryChanged = "category_changed"
+	EventOwnerChanged    = "owner_changed"
+	EventRenewalChanged  = "renewal_changed"
+	EventIntroChanged    = "intro_changed"
+	EventTagsChanged     = "tags_changed"
+	EventStatusChanged   = "status_changed"
+	EventCancelled       = "cancelled"
+	EventReactivated     = "reactivated"
+	EventPaused          = "paused"
+	EventResumed         = "resumed"
+	EventRenewed         = "renewed"
+	EventMemberSet       = "member_set"
+	EventMemberRemoved   = "member_removed"
+	EventDeleted         = "deleted"
+)
+
+// eventData - данные события одного из типов выше
+type eventData interface {
+	eventType() string
+}
+
+// createdEvent - подписка создана; price - первый период цены с start_date. Статус новой подписки
+// active, статус по ее датам записывается следом событием status_changed
+type createdEvent struct {
+	ServiceName   string     `json:"service_name"`
+	ServiceID     *uuid.UUID `json:"service_id"`
+	Price         int        `json:"price"`
+	UserID        uuid.UUID  `json:"user_id"`
+	StartDate     time.Time  `json:"start_date"`
+	EndDate       *time.Time `json:"end_date"`
+	CategoryID    *uuid.UUID `json:"category_id"`
+	Tags          []string   `json:"tags"`
+	AutoRenew     bool       `json:"auto_renew"`
+	RenewalMonths int        `json:"renewal_months"`
+	TrialEndsAt   *time.Time `json:"trial_ends_at"`
+	IntroPrice    *int       `json:"intro_price"`
+	IntroMonths   *int       `json:"intro_months"`
+	Status        string     `json:"status"`
+}
+
+// importedEvent - подписка, созданная до журнала, записана в него целиком командой cmd/projections -import
+type importedEvent struct {
+	subscriptionState
+}
+
+// priceChangedEvent - период цены с effective_from; период с того же месяца заменяется
+type priceChangedEvent struct {
+	EffectiveFrom time.Time `json:"effective_from"`
+	Price         int       `json:"price"`
+}
+
+// datesChangedEvent - новые даты подписки, все три сразу
+type datesChangedEvent struct {
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	TrialEndsAt *time.Time `json:"trial_ends_at"`
+}
+
+// serviceChangedEvent - сменился сервис подписки или его название в каталоге; service_id nil - сервиса нет в каталоге
+type serviceChangedEvent struct {
+	ServiceName string     `json:"service_name"`
+	ServiceID   *uuid.UUID `json:"service_id"`
+}
+
+// categoryChangedEvent - категория назначена или снята (nil)
+type categoryChangedEvent struct {
+	CategoryID *uuid.UUID `json:"category_id"`
+}
+
+type ownerChangedEvent struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type renewalChangedEvent struct {
+	AutoRenew     bool `json:"auto_renew"`
+	RenewalMonths int  `json:"renewal_months"`
+}
+
+// introChangedEvent - вступительная цена задана или снята (оба nil)
+type introChangedEvent struct {
+	IntroPrice  *int `json:"intro_price"`
+	IntroMonths *int `json:"intro_months"`
+}
+
+// tagsChangedEvent - новый набор тегов целиком
+type tagsChangedEvent struct {
+	Tags []string `json:"tags"`
+}
+
+type statusChangedEvent struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// cancelledEvent - подписка отменена и действует по end_date включительно. Паузы после end_date
+// обрезаются событиями resumed перед ним
+type cancelledEvent struct {
+	EndDate     time.Time `json:"end_date"`
+	Reason      *string   `json:"reason"`
+	CancelledAt time.Time `json:"cancelled_at"`
+}
+
+// reactivatedEvent - отмененная или истекшая подписка возобновлена до end_date; статус следом меняет status_changed
+type reactivatedEvent struct {
+	EndDate time.Time `json:"end_date"`
+}
+
+type pausedEvent struct {
+	PauseID    uuid.UUID  `json:"pause_id"`
+	StartMonth time.Time  `json:"start_month"`
+	EndMonth   *time.Time `json:"end_month"`
+}
+
+// resumedEvent - пауза заканчивается месяцем end_month; end_month nil - пауза удалена целиком
+type resumedEvent struct {
+	PauseID  uuid.UUID  `json:"pause_id"`
+	EndMonth *time.Time `json:"end_month"`
+}
+
+// renewedEvent - подписка продлена на срок; renewal_id - запись в subscription_renewals
+type renewedEvent struct {
+	RenewalID       uuid.UUID `json:"renewal_id"`
+	PreviousEndDate time.Time `json:"previous_end_date"`
+	NewEndDate      time.Time `json:"new_end_date"`
+}
+
+// memberSetEvent - участник добавлен в общую подписку или его доля изменилась; задан ровно один из weight и amount
+type memberSetEvent struct {
+	UserID uuid.UUID `json:"user_id"`
+	Weight *int      `json:"weight"`
+	Amount *int      `json:"amount"`
+}
+
+// memberRemovedEvent - участник убран из подписки сам или вместе с пользователем
+type memberRemovedEvent struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type deletedEvent struct{}
+
+func (createdEvent) eventType() string         { return EventCreated }
+func (importedEvent) eventType() string        { return EventImported }
+func (priceChangedEvent) eventType() string    { return EventPriceChanged }
+func (datesChangedEvent) eventType() string    { return EventDatesChanged }
+func (serviceChangedEvent) eventType() string  { return EventServiceChanged }
+func (categoryChangedEvent) eventType() string { return EventCategoryChanged }
+func (ownerChangedEvent) eventType() string    { return EventOwnerChanged }
+func (renewalChangedEvent) eventType() string  { return EventRenewalChanged }
+func (introChangedEvent) eventType() string    { return EventIntroChanged }
+func (tagsChangedEvent) eventType() string     { return EventTagsChanged }
+func (statusChangedEvent) eventType() string   { return EventStatusChanged }
+func (cancelledEvent) eventType() string       { return EventCancelled }
+func (reactivatedEvent) eventType() string     { return EventReactivated }
+func (pausedEvent) eventType() string          { return EventPaused }
+func (resumedEvent) eventType() string         { return EventResumed }
+func (renewedEvent) eventType() string         { return EventRenewed }
+func (memberSetEvent) eventType() string       { return EventMemberSet }
+func (memberRemovedEvent) eventType() string   { return EventMemberRemoved }
+func (deletedEvent) eventType() string         { return EventDeleted }
+
+// decodeEvent разбирает данные события из журнала по его типу
+func decodeEvent(typ string, data []byte) (eventData, error) {
+	switch typ {
+	case EventCreated:
+		return decodeAs[createdEvent](data)
+	case EventImported:
+		return decodeAs[importedEvent](data)
+	case EventPriceChanged:
+		return decodeAs[priceChangedEvent](data)
+	case EventDatesChanged:
+		return decodeAs[datesChangedEvent](data)
+	case EventServiceChanged:
+		return decodeAs[serviceChangedEvent](data)
+	case EventCategoryChanged:
+		return decodeAs[categoryChangedEvent](data)
+	case EventOwnerChanged:
+		return decodeAs[ownerChangedEvent](data)
+	case EventRenewalChanged:
+		return decodeAs[renewalChangedEvent](data)
+	case EventIntroChanged:
+		return decodeAs[introChangedEvent](data)
+	case EventTagsChanged:
+		return decodeAs[tagsChangedEvent](data)
+	case EventStatusChanged:
+		return decodeAs[statusChangedEvent](data)
+	case EventCancelled:
+		return decodeAs[cancelledEvent](data)
+	case EventReactivated:
+		return decodeAs[reactivatedEvent](data)
+	case EventPaused:
+		return decodeAs[pausedEvent](data)
+	case EventResumed:
+		return decodeAs[resumedEvent](data)
+	case EventRenewed:
+		return decodeAs[renewedEvent](data)
+	case EventMemberSet:
+		return decodeAs[memberSetEvent](data)
+	case EventMemberRemoved:
+		return decodeAs[memberRemovedEvent](data)
+	case EventDeleted:
+		return decodeAs[deletedEvent](data)
+	}
+
+	return nil, fmt.Errorf("unknown event type %q", typ)
+}
+
+func decodeAs[T eventData](data []byte) (eventData, error) {
+	var event T
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// subscriptionState - состояние подписки, свернутое из ее событий: строка subscriptions, теги,
+// периоды цен, паузы и участники. Проекция хранит то же самое, loadState читает его обратно для сверки
+type subscriptionState struct {
+	ServiceName string     `json:"service_name"`
+	ServiceID   *uuid.UUID `json:"service_id"`
+	// Price - цена последнего периода, как subscriptions.price
+	Price         int           `json:"price"`
+	UserID        uuid.UUID     `json:"user_id"`
+	StartDate     time.Time     `json:"start_date"`
+	EndDate       *time.Time    `json:"end_date"`
+	CategoryID    *uuid.UUID    `json:"category_id"`
+	Tags          []string      `json:"tags"`
+	AutoRenew     bool          `json:"auto_renew"`
+	RenewalMonths int           `json:"renewal_months"`
+	TrialEndsAt   *time.Time    `json:"trial_ends_at"`
+	IntroPrice    *int          `json:"intro_price"`
+	IntroMonths   *int          `json:"intro_months"`
+	Status        string        `json:"status"`
+	CancelReason  *string       `json:"cancel_reason"`
+	CancelledAt   *time.Time    `json:"cancelled_at"`
+	Prices        []statePrice  `json:"prices"`
+	Pauses        []statePause  `json:"pauses"`
+	Members       []stateMember `json:"members"`
+}
+
+// statePrice - период цены в состоянии подписки
+type statePrice struct {
+	EffectiveFrom time.Time `json:"effective_from"`
+	Price         int       `json:"price"`
+}
+
+// statePause - пауза в состоянии подписки; EndMonth nil - до возобновления
+type statePause struct {
+	ID         uuid.UUID  `json:"id"`
+	StartMonth time.Time  `json:"start_month"`
+	EndMonth   *time.Time `json:"end_month"`
+}
+
+// stateMember - участник в состоянии подписки; участники упорядочены по UserID
+type stateMember struct {
+	UserID uuid.UUID `json:"user_id"`
+	Weight *int      `json:"weight"`
+	Amount *int      `json:"amount"`
+}
+
+// clone копирует состояние; указатели на значения не копируются, события их только заменяют
+func (st *subscriptionState) clone() *subscriptionState {
+	next := *st
+	next.Tags = slices.Clone(st.Tags)
+	next.Prices = slices.Clone(st.Prices)
+	next.Pauses = slices.Clone(st.Pauses)
+	next.Members = slices.Clone(st.Members)
+
+	return &next
+}
+
+// applyEvent возвращает состояние подписки после события, st не меняется.
+// nil - подписки нет: она еще не создана или удалена
+func applyEvent(st *subscriptionState, data eventData) (*subscriptionState, error) {
+	switch event := data.(type) {
+	case createdEvent:
+		if st != nil {
+			return nil, fmt.Errorf("%s: subscription already exists", EventCreated)
+		}
+		return &subscriptionState{
+			ServiceName:   event.ServiceName,
+			ServiceID:     event.ServiceID,
+			Price:         event.Price,
+			UserID:        event.UserID,
+			StartDate:     event.StartDate,
+			EndDate:       event.EndDate,
+			CategoryID:    event.CategoryID,
+			Tags:          sortedTags(event.Tags),
+			AutoRenew:     event.AutoRenew,
+			RenewalMonths: event.RenewalMonths,
+			TrialEndsAt:   event.TrialEndsAt,
+			IntroPrice:    event.IntroPrice,
+			IntroMonths:   event.IntroMonths,
+			Status:        event.Status,
+			Prices:        []statePrice{{EffectiveFrom: event.StartDate, Price: event.Price}},
+			Pauses:        []statePause{},
+			Members:       []stateMember{},
+		}, nil
+	case importedEvent:
+		if st != nil {
+			return nil, fmt.Errorf("%s: subscription already exists", EventImported)
+		}
+		return event.subscriptionState.clone(), nil
+	}
+
+	if st == nil {
+		return nil, fmt.Errorf("%s: subscription does not exist", data.eventType())
+	}
+	next := st.clone()
+
+	switch event := data.(type) {
+	case priceChangedEvent:
+		next.setPrice(event.EffectiveFrom, event.Price)
+	case datesChangedEvent:
+		next.StartDate, next.EndDate, next.TrialEndsAt = event.StartDate, event.EndDate, event.TrialEndsAt
+	case serviceChangedEvent:
+		next.ServiceName, next.ServiceID = event.ServiceName, event.ServiceID
+	case categoryChangedEvent:
+		next.CategoryID = event.CategoryID
+	case ownerChangedEvent:
+		next.UserID = event.UserID
+	case renewalChangedEvent:
+		next.AutoRenew, next.RenewalMonths = event.AutoRenew, event.RenewalMonths
+	case introChangedEvent:
+		next.IntroPrice, next.IntroMonths = event.IntroPrice, event.IntroMonths
+	case tagsChangedEvent:
+		next.Tags = sortedTags(event.Tags)
+	case statusChangedEvent:
+		next.Status = event.To
+	case cancelledEvent:
+		end, cancelledAt := event.EndDate, event.CancelledAt
+		next.EndDate = &end
+		// пробный период не может закончиться позже подписки
+		if next.TrialEndsAt != nil && next.TrialEndsAt.After(end) {
+			next.TrialEndsAt = &end
+		}
+		next.AutoRenew = false
+		next.Status = StatusCancelled
+		next.CancelReason = event.Reason
+		next.CancelledAt = &cancelledAt
+	case reactivatedEvent:
+		next.EndDate = &event.EndDate
+		next.CancelReason, next.CancelledAt = nil, nil
+	case pausedEvent:
+		next.Pauses = append(next.Pauses, statePause{ID: event.PauseID, StartMonth: event.StartMonth, EndMonth: event.EndMonth})
+		slices.SortFunc(next.Pauses, func(a, b statePause) int { return a.StartMonth.Compare(b.StartMonth) })
+	case resumedEvent:
+		i := slices.IndexFunc(next.Pauses, func(p statePause) bool { return p.ID == event.PauseID })
+		if i < 0 {
+			return nil, fmt.Errorf("%s: pause %s not found", EventResumed, event.PauseID)
+		}
+		if event.EndMonth == nil {
+			next.Pauses = slices.Delete(next.Pauses, i, i+1)
+		} else {
+			next.Pauses[i].EndMonth = event.EndMonth
+		}
+	case renewedEvent:
+		end := event.NewEndDate
+		next.EndDate = &end
+	case memberSetEvent:
+		member := stateMember{UserID: event.UserID, Weight: event.Weight, Amount: event.Amount}
+		i, found := slices.BinarySearchFunc(next.Members, event.UserID, compareMember)
+		if found {
+			next.Members[i] = member
+		} else {
+			next.Members = slices.Insert(next.Members, i, member)
+		}
+	case memberRemovedEvent:
+		i, found := slices.BinarySearchFunc(next.Members, event.UserID, compareMember)
+		if !found {
+			return nil, fmt.Errorf("%s: member %s not found", EventMemberRemoved, event.UserID)
+		}
+		next.Members = slices.Delete(next.Members, i, i+1)
+	case deletedEvent:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown event %T", data)
+	}
+
+	return next, nil
+}
+
+func compareMember(m stateMember, userID uuid.UUID) int {
+	return bytes.Compare(m.UserID[:], userID[:])
+}
+
+// sortedTags - теги в порядке, в котором их хранит состояние
+func sortedTags(tags []string) []string {
+	sorted := slices.Clone(tags)
+	if sorted == nil {
+		sorted = []string{}
+	}
+	slices.Sort(sorted)
+
+	return slices.Compact(sorted)
+}
+
+// setPrice начинает период цены с месяца from или заменяет его цену; Price - цена последнего периода
+func (st *subscriptionState) setPrice(from time.Time, price int) {
+	i := slices.IndexFunc(st.Prices, func(p statePrice) bool { return p.EffectiveFrom.Equal(from) })
+	if i >= 0 {
+		st.Prices[i].Price = price
+	} else {
+		st.Prices = append(st.Prices, statePrice{EffectiveFrom: from, Price: price})
+		slices.SortFunc(st.Prices, func(a, b statePrice) int { return a.EffectiveFrom.Compare(b.EffectiveFrom) })
+	}
+	st.Price = st.Prices[len(st.Prices)-1].Price
+}
+
+// diffStates возвращает поля, которые различаются в состояниях, по именам в JSON
+func diffStates(a, b *subscriptionState) ([]string, error) {
+	left, err := stateFields(a)
+	if err != nil {
+		return nil, err
+	}
+	right, err := stateFields(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for key, value := range left {
+		if string(right[key]) != string(value) {
+			fields = append(fields, key)
+		}
+	}
+	slices.Sort(fields)
+
+	return fields, nil
+}
+
+// stateFields раскладывает состояние на поля в JSON. Моменты времени приводятся к UTC,
+// чтобы сравнение не зависело от часового пояса соединения
+func stateFields(st *subscriptionState) (map[string]json.RawMessage, error) {
+	normalized := st.clone()
+	// пустой список и его отсутствие - одно и то же
+	if normalized.Tags == nil {
+		normalized.Tags = []string{}
+	}
+	if normalized.Prices == nil {
+		normalized.Prices = []statePrice{}
+	}
+	if normalized.Pauses == nil {
+		normalized.Pauses = []statePause{}
+	}
+	if normalized.Members == nil {
+		normalized.Members = []stateMember{}
+	}
+	if st.CancelledAt != nil {
+		utc := st.CancelledAt.UTC()
+		normalized.CancelledAt = &utc
+	}
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
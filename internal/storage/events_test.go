@@ -0,0 +1,668 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+var (
+	testUser    = uuid.MustParse("5b0c7a4e-3f7e-4d6b-9a43-2f1c0e3d8a11")
+	testOwner   = uuid.MustParse("0e9d8c7b-6a5f-4e3d-8c2b-1a0f9e8d7c6b")
+	testService = uuid.MustParse("7f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0")
+)
+
+// month разбирает месяц YYYY-MM
+func month(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(DateLayout, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed
+}
+
+func monthPtr(t *testing.T, value string) *time.Time {
+	t.Helper()
+
+	parsed := month(t, value)
+	return &parsed
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+// record сворачивает события так, как их прочитает replaySubscription: через JSON журнала
+func record(t *testing.T, events []eventData) *subscriptionState {
+	t.Helper()
+
+	var st *subscriptionState
+	for i, event := range events {
+		raw, err := json.Marshal(event)
+		if err != nil {
+			t.Fatalf("event %d: %v", i+1, err)
+		}
+		decoded, err := decodeEvent(event.eventType(), raw)
+		if err != nil {
+			t.Fatalf("event %d: %v", i+1, err)
+		}
+		if st, err = applyEvent(st, decoded); err != nil {
+			t.Fatalf("event %d %s: %v", i+1, event.eventType(), err)
+		}
+	}
+
+	return st
+}
+
+// testRules - subscriptionRules без базы: проекции нет, а ответы subscription_status_at
+// и subscription_price_at задает тест
+type testRules struct {
+	state *subscriptionState
+	// status - статус по датам и паузам, пустой - текущий статус подписки
+	status string
+}
+
+func (r *testRules) project(_ uuid.UUID, _ eventData, st *subscriptionState) error {
+	r.state = st
+	return nil
+}
+
+func (r *testRules) statusAt(uuid.UUID, time.Time) (string, error) {
+	if r.status != "" {
+		return r.status, nil
+	}
+
+	return r.state.Status, nil
+}
+
+// priceAt - цена последнего периода: тестам хватает подписки с одной ценой
+func (r *testRules) priceAt(uuid.UUID, time.Time) (int, error) {
+	return r.state.Price, nil
+}
+
+// newStream - поток подписки, уже записанной событиями events; status - статус по ее датам и паузам
+func newStream(t *testing.T, status string, events ...eventData) *stream {
+	t.Helper()
+
+	st := record(t, events)
+	return &stream{id: uuid.New(), version: len(events), state: st, rules: &testRules{state: st, status: status}}
+}
+
+// derive задает статус, который следует из дат и пауз подписки потока
+func derive(s *stream, status string) {
+	s.rules.(*testRules).status = status
+}
+
+// pendingTypes - типы новых событий потока
+func pendingTypes(s *stream) []string {
+	types := []string{}
+	for _, data := range s.pending {
+		types = append(types, data.eventType())
+	}
+
+	return types
+}
+
+func testCreated(t *testing.T) createdEvent {
+	return createdEvent{
+		ServiceName:   "Netflix",
+		ServiceID:     &testService,
+		Price:         500,
+		UserID:        testUser,
+		StartDate:     month(t, "2025-01"),
+		EndDate:       monthPtr(t, "2025-12"),
+		Tags:          []string{"work", "family"},
+		AutoRenew:     true,
+		RenewalMonths: 12,
+		TrialEndsAt:   monthPtr(t, "2025-02"),
+		Status:        StatusTrial,
+	}
+}
+
+// TestReplayRebuildsProjection проводит подписку через команды и сверяет состояние, свернутое из журнала,
+// с тем, что должно лежать в таблицах после проекции тех же событий
+func TestReplayRebuildsProjection(t *testing.T) {
+	s := newStream(t, "")
+	var log []eventData
+	step := func(name, status string, decide func() error) {
+		t.Helper()
+		derive(s, status)
+		if err := decide(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		log = append(log, s.pending...)
+		s.version += len(s.pending)
+		s.pending = nil
+	}
+
+	created := testCreated(t)
+	created.Status = ""
+	cancelledAt := time.Date(2025, time.June, 15, 10, 30, 0, 0, time.UTC)
+	category := uuid.New()
+
+	step("create", StatusTrial, func() error { return decideCreate(s, created, month(t, "2025-01")) })
+	step("update", StatusActive, func() error {
+		return decideChange(s, subscriptionChange{
+			price:       intPtr(700),
+			priceFrom:   monthPtr(t, "2025-04"),
+			categorySet: true,
+			categoryID:  &category,
+			introPrice:  intPtr(199),
+			introMonths: intPtr(3),
+		}, month(t, "2025-03"))
+	})
+	step("pause", StatusPaused, func() error {
+		return decidePause(s, PauseRequest{From: "2025-05", Until: "2025-06"}, month(t, "2025-05"))
+	})
+	step("pause later", StatusPaused, func() error {
+		return decidePause(s, PauseRequest{From: "2025-09"}, month(t, "2025-05"))
+	})
+	step("rename service", "", func() error { return decideService(s, "Netflix Premium", &testService) })
+	step("add tag", "", func() error {
+		return decideTags(s, func(current []string) []string { return append(current, "streaming") })
+	})
+	step("cancel", "", func() error {
+		return decideCancel(s, CancelRequest{Reason: "too expensive", EffectiveDate: "2025-07"}, month(t, "2025-06"), cancelledAt)
+	})
+
+	want := &subscriptionState{
+		ServiceName:   "Netflix Premium",
+		ServiceID:     &testService,
+		Price:         700,
+		UserID:        testUser,
+		StartDate:     month(t, "2025-01"),
+		EndDate:       monthPtr(t, "2025-07"),
+		CategoryID:    &category,
+		Tags:          []string{"family", "streaming", "work"},
+		AutoRenew:     false,
+		RenewalMonths: 12,
+		TrialEndsAt:   monthPtr(t, "2025-02"),
+		IntroPrice:    intPtr(199),
+		IntroMonths:   intPtr(3),
+		Status:        StatusCancelled,
+		CancelReason:  &[]string{"too expensive"}[0],
+		CancelledAt:   &cancelledAt,
+		Prices: []statePrice{
+			{EffectiveFrom: month(t, "2025-01"), Price: 500},
+			{EffectiveFrom: month(t, "2025-04"), Price: 700},
+		},
+		// пауза с сентября начиналась после отмены и удалена
+		Pauses: []statePause{{ID: s.state.Pauses[0].ID, StartMonth: month(t, "2025-05"), EndMonth: monthPtr(t, "2025-06")}},
+	}
+
+	replayed := record(t, log)
+	fields, err := projectionDrift(replayed, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) > 0 {
+		t.Errorf("replayed state differs from the table in %v", fields)
+	}
+	if fields, _ := diffStates(s.state, replayed); len(fields) > 0 {
+		t.Errorf("command state differs from the replayed one in %v", fields)
+	}
+
+	types := make([]string, 0, len(log))
+	for _, event := range log {
+		types = append(types, event.eventType())
+	}
+	wantTypes := []string{
+		EventCreated, EventStatusChanged, EventCategoryChanged, EventIntroChanged, EventPriceChanged, EventStatusChanged,
+		EventPaused, EventStatusChanged, EventPaused, EventServiceChanged, EventTagsChanged, EventResumed, EventCancelled,
+	}
+	if !slices.Equal(types, wantTypes) {
+		t.Errorf("events:\n got %v\nwant %v", types, wantTypes)
+	}
+
+	// проекция, измененная в обход журнала, восстанавливается по нему
+	table := want.clone()
+	table.ServiceName = "netflix"
+	table.Tags = []string{"family"}
+	fields, err = projectionDrift(replayed, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(fields, []string{"service_name", "tags"}) {
+		t.Errorf("drift: got %v, want [service_name tags]", fields)
+	}
+
+	// удаленная подписка убирается из проекции
+	s.pending = nil
+	if err := s.emit(deletedEvent{}); err != nil {
+		t.Fatal(err)
+	}
+	if fields, _ := projectionDrift(record(t, append(log, deletedEvent{})), want); !slices.Equal(fields, []string{"deleted"}) {
+		t.Errorf("drift after delete: got %v, want [deleted]", fields)
+	}
+}
+
+func TestEventJSONRoundTrip(t *testing.T) {
+	reason := "moving"
+	state := record(t, []eventData{testCreated(t)})
+	events := []eventData{
+		testCreated(t),
+		importedEvent{*state},
+		priceChangedEvent{EffectiveFrom: month(t, "2025-03"), Price: 900},
+		datesChangedEvent{StartDate: month(t, "2025-01"), EndDate: nil, TrialEndsAt: monthPtr(t, "2025-02")},
+		serviceChangedEvent{ServiceName: "Spotify"},
+		categoryChangedEvent{CategoryID: &testService},
+		ownerChangedEvent{UserID: testOwner},
+		renewalChangedEvent{AutoRenew: true, RenewalMonths: 1},
+		introChangedEvent{IntroPrice: intPtr(99), IntroMonths: intPtr(2)},
+		tagsChangedEvent{Tags: []string{"a", "b"}},
+		statusChangedEvent{From: StatusTrial, To: StatusActive},
+		cancelledEvent{EndDate: month(t, "2025-06"), Reason: &reason, CancelledAt: time.Date(2025, time.June, 1, 8, 0, 0, 123000, time.UTC)},
+		reactivatedEvent{EndDate: month(t, "2026-06")},
+		pausedEvent{PauseID: uuid.New(), StartMonth: month(t, "2025-04")},
+		resumedEvent{PauseID: uuid.New(), EndMonth: monthPtr(t, "2025-05")},
+		renewedEvent{RenewalID: uuid.New(), PreviousEndDate: month(t, "2025-12"), NewEndDate: month(t, "2026-12")},
+		memberSetEvent{UserID: testOwner, Amount: intPtr(150)},
+		memberRemovedEvent{UserID: testOwner},
+		deletedEvent{},
+	}
+
+	for _, event := range events {
+		t.Run(event.eventType(), func(t *testing.T) {
+			raw, err := json.Marshal(event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := decodeEvent(event.eventType(), raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, event) {
+				t.Errorf("got %#v\nwant %#v", decoded, event)
+			}
+		})
+	}
+
+	if _, err := decodeEvent("updated", []byte(`{}`)); err == nil {
+		t.Error("decodeEvent accepted an unknown event type")
+	}
+}
+
+func TestDecideChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		change subscriptionChange
+		month  string
+		// status - статус по новым датам, пустой - прежний
+		status string
+		want   []string
+		err    error
+	}{
+		{"nothing changes", subscriptionChange{
+			startDate: monthPtr(t, "2025-01"),
+			service:   &serviceChangedEvent{ServiceName: "Netflix", ServiceID: &testService},
+			price:     intPtr(500),
+			tagsSet:   true,
+			tags:      []string{"family", "work"},
+		}, "2025-02", "", []string{}, nil},
+		{"new price starts a period", subscriptionChange{price: intPtr(600)}, "2025-02", "",
+			[]string{EventPriceChanged}, nil},
+		{"trial ends with new dates", subscriptionChange{trialSet: true}, "2025-02", StatusActive,
+			[]string{EventDatesChanged, EventStatusChanged}, nil},
+		{"groups in order", subscriptionChange{
+			endDate:       monthPtr(t, "2026-12"),
+			owner:         &testOwner,
+			autoRenew:     new(bool),
+			introPrice:    intPtr(100),
+			introMonths:   intPtr(2),
+			tagsSet:       true,
+			tags:          []string{"home"},
+			service:       &serviceChangedEvent{ServiceName: "Kion"},
+			categorySet:   true,
+			renewalMonths: 12,
+		}, "2025-02", "", []string{EventDatesChanged, EventServiceChanged, EventOwnerChanged, EventRenewalChanged,
+			EventIntroChanged, EventTagsChanged}, nil},
+		{"end before start", subscriptionChange{endDate: monthPtr(t, "2024-12")}, "2025-02", "", nil, myerrors.ErrInvalidDateRange},
+		{"intro months without price", subscriptionChange{introMonths: intPtr(3)}, "2025-02", "", nil, myerrors.ErrInvalidIntro},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStream(t, tt.status, testCreated(t))
+			err := decideChange(s, tt.change, month(t, tt.month))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				if len(s.pending) > 0 {
+					t.Errorf("events recorded with an error: %v", pendingTypes(s))
+				}
+				return
+			}
+			if tt.want != nil && !slices.Equal(pendingTypes(s), tt.want) {
+				t.Errorf("events:\n got %v\nwant %v", pendingTypes(s), tt.want)
+			}
+		})
+	}
+
+	t.Run("zero intro price clears both", func(t *testing.T) {
+		created := testCreated(t)
+		created.IntroPrice, created.IntroMonths = intPtr(199), intPtr(3)
+		s := newStream(t, "", created)
+		if err := decideChange(s, subscriptionChange{introPrice: intPtr(0)}, month(t, "2025-02")); err != nil {
+			t.Fatal(err)
+		}
+		if s.state.IntroPrice != nil || s.state.IntroMonths != nil {
+			t.Errorf("intro left: %v %v", s.state.IntroPrice, s.state.IntroMonths)
+		}
+	})
+
+	t.Run("price before start starts with the subscription", func(t *testing.T) {
+		s := newStream(t, "", testCreated(t))
+		err := decideChange(s, subscriptionChange{price: intPtr(800), priceFrom: monthPtr(t, "2024-06")}, month(t, "2025-02"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(s.state.Prices, []statePrice{{EffectiveFrom: month(t, "2025-01"), Price: 800}}) {
+			t.Errorf("prices: %v", s.state.Prices)
+		}
+	})
+}
+
+func TestDecideStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []eventData
+		derived string
+		want    []string
+		err     error
+	}{
+		{"trial ends", nil, StatusActive, []string{EventStatusChanged}, nil},
+		{"trial goes on", nil, StatusTrial, []string{}, nil},
+		{"expires after end", nil, StatusExpired, []string{EventStatusChanged}, nil},
+		{"cancelled stays", []eventData{cancelledEvent{EndDate: month(t, "2025-03")}}, StatusActive, []string{}, nil},
+		{"expired stays", []eventData{statusChangedEvent{From: StatusTrial, To: StatusExpired}}, StatusActive, []string{}, nil},
+		{"active can not go back to trial", []eventData{
+			statusChangedEvent{From: StatusTrial, To: StatusPaused},
+			statusChangedEvent{From: StatusPaused, To: StatusActive},
+		}, StatusTrial, []string{}, myerrors.ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStream(t, tt.derived, append([]eventData{testCreated(t)}, tt.events...)...)
+			err := decideStatus(s, month(t, "2025-06"))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if !slices.Equal(pendingTypes(s), tt.want) {
+				t.Errorf("events: got %v, want %v", pendingTypes(s), tt.want)
+			}
+		})
+	}
+}
+
+func TestDecideCancel(t *testing.T) {
+	cancelledAt := time.Date(2025, time.May, 20, 0, 0, 0, 0, time.UTC)
+	s := newStream(t, "", testCreated(t),
+		pausedEvent{PauseID: uuid.New(), StartMonth: month(t, "2025-04")},
+		pausedEvent{PauseID: uuid.New(), StartMonth: month(t, "2025-08"), EndMonth: monthPtr(t, "2025-09")})
+
+	if err := decideCancel(s, CancelRequest{EffectiveDate: "2026-01"}, month(t, "2025-05"), cancelledAt); !errors.Is(err, myerrors.ErrInvalidCancelDate) {
+		t.Fatalf("cancel after end: got %v", err)
+	}
+	if err := decideCancel(s, CancelRequest{}, month(t, "2025-05"), cancelledAt); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{EventResumed, EventResumed, EventCancelled}; !slices.Equal(pendingTypes(s), want) {
+		t.Errorf("cancel: got %v, want %v", pendingTypes(s), want)
+	}
+
+	st := s.state
+	if st.Status != StatusCancelled || st.AutoRenew || !st.EndDate.Equal(month(t, "2025-05")) || st.CancelReason != nil {
+		t.Errorf("cancelled state: %+v", st)
+	}
+	// открытая пауза обрезается месяцем отмены, пауза после него удаляется
+	if len(st.Pauses) != 1 || !st.Pauses[0].EndMonth.Equal(month(t, "2025-05")) {
+		t.Errorf("pauses: %+v", st.Pauses)
+	}
+	if !st.TrialEndsAt.Equal(month(t, "2025-02")) {
+		t.Errorf("trial: %v", st.TrialEndsAt)
+	}
+
+	if err := decideCancel(s, CancelRequest{}, month(t, "2025-05"), cancelledAt); !errors.Is(err, myerrors.ErrInvalidTransition) {
+		t.Errorf("second cancel: got %v", err)
+	}
+
+	// end_date уже прошел: без нового срока подписка не возобновляется
+	s.pending = nil
+	if err := decideReactivate(s, ReactivateRequest{}, month(t, "2025-08")); !errors.Is(err, myerrors.ErrInvalidDateRange) || len(s.pending) > 0 {
+		t.Fatalf("reactivate without end_date: got %v, events %v", err, pendingTypes(s))
+	}
+	derive(s, StatusActive)
+	if err := decideReactivate(s, ReactivateRequest{EndDate: "2026-06"}, month(t, "2025-08")); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{EventReactivated, EventStatusChanged}; !slices.Equal(pendingTypes(s), want) {
+		t.Errorf("reactivate: got %v, want %v", pendingTypes(s), want)
+	}
+	if !s.state.EndDate.Equal(month(t, "2026-06")) || s.state.Status != StatusActive || s.state.CancelledAt != nil {
+		t.Errorf("reactivated state: %+v", s.state)
+	}
+	if err := decideReactivate(s, ReactivateRequest{}, month(t, "2025-08")); !errors.Is(err, myerrors.ErrInvalidTransition) {
+		t.Errorf("reactivate active: got %v", err)
+	}
+}
+
+func TestDecidePauseAndResume(t *testing.T) {
+	s := newStream(t, "", testCreated(t), statusChangedEvent{From: StatusTrial, To: StatusActive})
+
+	for _, tt := range []struct {
+		req PauseRequest
+		err error
+	}{
+		{PauseRequest{From: "2024-12"}, myerrors.ErrInvalidPause},
+		{PauseRequest{From: "2025-05", Until: "2025-04"}, myerrors.ErrInvalidPause},
+		{PauseRequest{From: "2025-05", Until: "2026-01"}, myerrors.ErrInvalidPause},
+	} {
+		if err := decidePause(s, tt.req, month(t, "2025-04")); !errors.Is(err, tt.err) {
+			t.Errorf("pause %+v: got %v, want %v", tt.req, err, tt.err)
+		}
+	}
+
+	derive(s, StatusPaused)
+	if err := decidePause(s, PauseRequest{From: "2025-04"}, month(t, "2025-04")); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(pendingTypes(s), []string{EventPaused, EventStatusChanged}) || s.state.Status != StatusPaused {
+		t.Errorf("pause: %v, status %s", pendingTypes(s), s.state.Status)
+	}
+	if err := decidePause(s, PauseRequest{From: "2025-08"}, month(t, "2025-04")); !errors.Is(err, myerrors.ErrPauseOverlap) {
+		t.Errorf("overlapping pause: got %v", err)
+	}
+
+	// возобновление с первого месяца паузы удаляет ее
+	s.pending = nil
+	derive(s, StatusActive)
+	if err := decideResume(s, ResumeRequest{From: "2025-04"}, month(t, "2025-04")); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.state.Pauses) != 0 || s.state.Status != StatusActive {
+		t.Errorf("resume: pauses %+v, status %s", s.state.Pauses, s.state.Status)
+	}
+	if err := decideResume(s, ResumeRequest{}, month(t, "2025-04")); !errors.Is(err, myerrors.ErrNotPaused) {
+		t.Errorf("resume without pause: got %v", err)
+	}
+
+	// возобновление посреди паузы заканчивает ее месяцем раньше
+	if err := decidePause(s, PauseRequest{From: "2025-05"}, month(t, "2025-04")); err != nil {
+		t.Fatal(err)
+	}
+	derive(s, StatusPaused)
+	if err := decideResume(s, ResumeRequest{From: "2025-07"}, month(t, "2025-06")); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.state.Pauses) != 1 || !s.state.Pauses[0].EndMonth.Equal(month(t, "2025-06")) || s.state.Status != StatusPaused {
+		t.Errorf("partial resume: pauses %+v, status %s", s.state.Pauses, s.state.Status)
+	}
+}
+
+func TestDecideRenewals(t *testing.T) {
+	created := testCreated(t)
+	created.RenewalMonths = 3
+	s := newStream(t, "", created)
+
+	if err := decideRenewals(s, time.Date(2026, time.August, 10, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	var ends []string
+	for _, data := range s.pending {
+		r := data.(renewedEvent)
+		ends = append(ends, r.PreviousEndDate.Format(DateLayout)+"->"+r.NewEndDate.Format(DateLayout))
+	}
+	want := []string{"2025-12->2026-03", "2026-03->2026-06", "2026-06->2026-09"}
+	if !slices.Equal(ends, want) {
+		t.Errorf("renewals:\n got %v\nwant %v", ends, want)
+	}
+
+	// отмененная и без автопродления не продлеваются
+	for _, events := range [][]eventData{
+		{created, cancelledEvent{EndDate: month(t, "2025-06")}},
+		{created, renewalChangedEvent{AutoRenew: false, RenewalMonths: 3}},
+	} {
+		s := newStream(t, "", events...)
+		if err := decideRenewals(s, time.Date(2026, time.August, 10, 0, 0, 0, 0, time.UTC)); err != nil || len(s.pending) > 0 {
+			t.Errorf("%s: got %v, %v", events[1].eventType(), pendingTypes(s), err)
+		}
+	}
+}
+
+func TestDecideTags(t *testing.T) {
+	s := newStream(t, "", testCreated(t))
+
+	if err := decideTags(s, func(current []string) []string { return append(current, "work") }); err != nil || len(s.pending) > 0 {
+		t.Errorf("adding an existing tag: %v, %v", pendingTypes(s), err)
+	}
+	if err := decideTags(s, func([]string) []string { return []string{"b", "a"} }); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(s.state.Tags, []string{"a", "b"}) {
+		t.Errorf("tags: %v", s.state.Tags)
+	}
+
+	if err := decideTags(&stream{}, func(current []string) []string { return current }); !errors.Is(err, myerrors.ErrNotFound) {
+		t.Errorf("tags of a missing subscription: got %v", err)
+	}
+}
+
+func TestDecideMembers(t *testing.T) {
+	s := newStream(t, "", testCreated(t))
+	alice, bob := uuid.MustParse("00000000-0000-0000-0000-00000000000a"), uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+
+	for _, set := range []memberSetEvent{
+		{UserID: bob, Weight: intPtr(2)},
+		{UserID: alice, Amount: intPtr(100)},
+		{UserID: bob, Weight: intPtr(2)},
+		{UserID: bob, Amount: intPtr(50)},
+	} {
+		if err := decideMember(s, set); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// та же доля ничего не записывает
+	if want := []string{EventMemberSet, EventMemberSet, EventMemberSet}; !slices.Equal(pendingTypes(s), want) {
+		t.Errorf("set: got %v, want %v", pendingTypes(s), want)
+	}
+	want := []stateMember{{UserID: alice, Amount: intPtr(100)}, {UserID: bob, Amount: intPtr(50)}}
+	if !reflect.DeepEqual(s.state.Members, want) {
+		t.Errorf("members: got %+v, want %+v", s.state.Members, want)
+	}
+
+	if err := decideRemoveMember(s, alice); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.state.Members) != 1 || s.state.Members[0].UserID != bob {
+		t.Errorf("members after remove: %+v", s.state.Members)
+	}
+	if err := decideRemoveMember(s, alice); !errors.Is(err, myerrors.ErrMemberNotFound) {
+		t.Errorf("remove twice: got %v", err)
+	}
+	if err := decideMember(&stream{}, memberSetEvent{UserID: alice, Weight: intPtr(1)}); !errors.Is(err, myerrors.ErrNotFound) {
+		t.Errorf("member of a missing subscription: got %v", err)
+	}
+}
+
+func TestCheckProjection(t *testing.T) {
+	st := record(t, []eventData{testCreated(t)})
+
+	if err := checkProjection(st, 1, st.clone()); err != nil {
+		t.Errorf("same state: %v", err)
+	}
+	if err := checkProjection(nil, 0, nil); err != nil {
+		t.Errorf("new subscription: %v", err)
+	}
+
+	renamed := st.clone()
+	renamed.ServiceName = "Renamed outside the store"
+	cancelled := st.clone()
+	cancelled.CancelledAt = &[]time.Time{time.Date(2025, time.June, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*3600))}[0]
+	sameMoment := cancelled.clone()
+	sameMoment.CancelledAt = &[]time.Time{cancelled.CancelledAt.UTC()}[0]
+
+	for _, tt := range []struct {
+		name    string
+		want    *subscriptionState
+		version int
+		current *subscriptionState
+		drifted bool
+	}{
+		{"bypass write", st, 1, renamed, true},
+		{"not in the log", nil, 0, st, true},
+		{"deleted in the log", nil, 2, st, true},
+		{"missing row", st, 1, nil, true},
+		{"time zone of the connection", sameMoment, 1, cancelled, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkProjection(tt.want, tt.version, tt.current)
+			if errors.Is(err, myerrors.ErrProjectionDrift) != tt.drifted {
+				t.Errorf("got %v, drifted %v", err, tt.drifted)
+			}
+		})
+	}
+}
+
+func TestApplyEventErrors(t *testing.T) {
+	st := record(t, []eventData{testCreated(t)})
+
+	for _, tt := range []struct {
+		name  string
+		state *subscriptionState
+		event eventData
+	}{
+		{"created twice", st, testCreated(t)},
+		{"imported over existing", st, importedEvent{*st}},
+		{"change before created", nil, priceChangedEvent{Price: 1}},
+		{"change after deleted", nil, tagsChangedEvent{}},
+		{"resume unknown pause", st, resumedEvent{PauseID: uuid.New()}},
+		{"remove unknown member", st, memberRemovedEvent{UserID: uuid.New()}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := applyEvent(tt.state, tt.event); err == nil {
+				t.Error("applyEvent accepted the event")
+			}
+		})
+	}
+
+	// событие не меняет исходное состояние
+	next, err := applyEvent(st, tagsChangedEvent{Tags: []string{"x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Equal(st.Tags, next.Tags) {
+		t.Errorf("tags: before %v, after %v", st.Tags, next.Tags)
+	}
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// EventStore - DataWizard, который записывает события каждой подписки в журнал subscription_events.
+// Команды те же, что у Storage, и проецируют те же события в subscriptions, ее теги, периоды цен,
+// паузы и продления. Поток подписки сворачивается из журнала и сверяется с проекцией: если строку
+// подписки изменили в обход журнала, команда отклоняется с ErrProjectionDrift, пока расхождение
+// не устранит cmd/projections
+type EventStore struct {
+	*Storage
+}
+
+// NewEventStore возвращает EventStore поверх соединения и обработчиков бюджетов s; сам s пишет без журнала
+func NewEventStore(s *Storage) *EventStore {
+	journal := *s
+	journal.journal = true
+
+	return &EventStore{Storage: &journal}
+}
+
+// RebuildReport - итог RebuildProjections
+type RebuildReport struct {
+	Events        int // прочитано событий
+	Subscriptions int // подписок в журнале
+	// Drifted - подписки, проекция которых расходилась с журналом; без dryRun они восстановлены по журналу
+	Drifted   []ProjectionDrift
+	Untracked int // подписок в проекции без событий
+	Imported  int // из них записано событием imported
+}
+
+// ProjectionDrift - подписка, проекция которой расходится с журналом, и расходящиеся поля, см. projectionDrift
+type ProjectionDrift struct {
+	SubscriptionID uuid.UUID
+	Fields         []string
+}
+
+// RebuildProjections сверяет проекцию каждой подписки журнала с ее событиями и восстанавливает
+// расходящиеся подписки по журналу; строки удаленных подписок удаляются. Каждая подписка сверяется
+// в своей транзакции под блокировкой только ее строки, поэтому команды EventStore над другими
+// подписками не ждут пересборки. dryRun - только найти расхождения. importUntracked - записать
+// событием imported подписки без событий (созданные до включения EventStore), иначе они только
+// считаются в Untracked, а их команды отклоняются с ErrProjectionDrift
+func (e *EventStore) RebuildProjections(dryRun, importUntracked bool) (*RebuildReport, error) {
+	const op = "storage.events.RebuildProjections"
+
+	ctx := context.Background()
+	report := &RebuildReport{}
+
+	rows, err := e.db.Query(ctx, `SELECT DISTINCT subscription_id FROM subscription_events ORDER BY subscription_id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	report.Subscriptions = len(ids)
+
+	for _, id := range ids {
+		err := pgx.BeginFunc(ctx, e.db, func(tx pgx.Tx) error {
+			current, err := loadState(ctx, tx, id, !dryRun)
+			if err != nil {
+				return err
+			}
+			want, version, err := replaySubscription(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			report.Events += version
+
+			fields, err := projectionDrift(want, current)
+			if err != nil || len(fields) == 0 {
+				return err
+			}
+			report.Drifted = append(report.Drifted, ProjectionDrift{SubscriptionID: id, Fields: fields})
+			if dryRun {
+				return nil
+			}
+
+			if want == nil {
+				_, err = tx.Exec(ctx, `DELETE FROM subscriptions WHERE id = $1`, id)
+				return err
+			}
+			return subscriptionFKError(projectState(ctx, tx, id, want))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: subscription %s: %w", op, id, err)
+		}
+	}
+
+	if err := e.importUntracked(ctx, report, dryRun || !importUntracked); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return report, nil
+}
+
+// importUntracked считает подписки проекции без событий и, если countOnly не задан,
+// записывает текущее состояние каждой событием imported
+func (e *EventStore) importUntracked(ctx context.Context, report *RebuildReport, countOnly bool) error {
+	rows, err := e.db.Query(ctx, `SELECT s.id FROM subscriptions s
+	WHERE NOT EXISTS (SELECT 1 FROM subscription_events e WHERE e.subscription_id = s.id)
+	ORDER BY s.id`)
+	if err != nil {
+		return err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return err
+	}
+	report.Untracked = len(ids)
+	if countOnly {
+		return nil
+	}
+
+	for _, id := range ids {
+		err := pgx.BeginFunc(ctx, e.db, func(tx pgx.Tx) error {
+			current, err := loadState(ctx, tx, id, true)
+			if err != nil {
+				return err
+			}
+			_, version, err := replaySubscription(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			// подписку успели удалить или записать в журнал
+			if current == nil || version > 0 {
+				return nil
+			}
+			if err := appendEvent(ctx, tx, id, 1, importedEvent{*current}); err != nil {
+				return err
+			}
+			report.Imported++
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("subscription %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
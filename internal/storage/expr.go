@@ -0,0 +1,664 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+// Выражение фильтра filter=, например
+//
+//	price > 500 and service_name ~ "net" and active_at("2025-06")
+//
+// Грамматика:
+//
+//	expr    = and { "or" and }
+//	and     = unary { "and" unary }
+//	unary   = "not" unary | "(" expr ")" | call | compare
+//	call    = name "(" [ value { "," value } ] ")"
+//	compare = field op value | field "in" "(" value { "," value } ")" | field "is" [ "not" ] "null" | field
+//	op      = "=" | "!=" | "<" | "<=" | ">" | ">=" | "~" | "!~"
+//	value   = string | integer | "true" | "false"
+//
+// Строки в двойных или одинарных кавычках, \ экранирует следующий символ. Ключевые слова, поля и функции
+// не зависят от регистра. Поле без оператора допустимо только для логических полей.
+// Разбор дает AST, который compileFilterExpr переводит в параметризованный SQL
+
+const (
+	maxFilterExprLength = 1000
+	maxFilterExprDepth  = 32
+)
+
+// ExprError - ошибка в выражении фильтра. Pos - номер символа (с 1), на котором она обнаружена
+type ExprError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	// text - значение строки без кавычек, идентификатор в нижнем регистре или сам оператор
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+
+	return fmt.Sprintf("%q", t.text)
+}
+
+var exprKeywords = map[string]bool{"and": true, "or": true, "not": true, "in": true, "is": true, "null": true, "true": true, "false": true}
+
+// exprOps - операторы сравнения, двухсимвольные проверяются первыми
+var exprOps = []string{"<=", ">=", "!=", "!~", "=", "<", ">", "~"}
+
+func lexFilterExpr(src string) ([]token, error) {
+	runes := []rune(src)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: pos})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: pos})
+			i++
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				} else if runes[i] == r {
+					closed = true
+					i++
+					break
+				}
+				b.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, &ExprError{Pos: pos, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, token{kind: tokString, text: b.String(), pos: pos})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: string(runes[i:j]), pos: pos})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: strings.ToLower(string(runes[i:j])), pos: pos})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range exprOps {
+				if strings.HasPrefix(string(runes[i:min(i+2, len(runes))]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, &ExprError{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: pos})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(runes) + 1}), nil
+}
+
+// exprNode - узел AST выражения фильтра
+type exprNode interface {
+	exprNode()
+}
+
+// logicalExpr - and или or
+type logicalExpr struct {
+	op          string
+	left, right exprNode
+}
+
+type notExpr struct {
+	operand exprNode
+}
+
+// callExpr - вызов функции, например active_at("2025-06")
+type callExpr struct {
+	name token
+	args []token
+}
+
+// compareExpr - сравнение поля. op - оператор, "in", "is null", "is not null"
+// или пустая строка для логического поля без оператора
+type compareExpr struct {
+	field  token
+	op     string
+	opPos  int
+	values []token
+}
+
+func (*logicalExpr) exprNode() {}
+func (*notExpr) exprNode()     {}
+func (*callExpr) exprNode()    {}
+func (*compareExpr) exprNode() {}
+
+type exprParser struct {
+	tokens []token
+	i      int
+	depth  int
+}
+
+// parseFilterExpr разбирает выражение фильтра в AST
+func parseFilterExpr(src string) (exprNode, error) {
+	if utf8.RuneCountInString(src) > maxFilterExprLength {
+		return nil, &ExprError{Pos: maxFilterExprLength + 1, Msg: fmt.Sprintf("expression is longer than %d characters", maxFilterExprLength)}
+	}
+
+	tokens, err := lexFilterExpr(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, &ExprError{Pos: 1, Msg: "empty expression"}
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, unexpected(t, "and, or or end of expression")
+	}
+
+	return node, nil
+}
+
+func unexpected(t token, expected string) error {
+	return &ExprError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s, expected %s", t, expected)}
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+
+	return t
+}
+
+func (p *exprParser) keyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && t.text == word
+}
+
+func (p *exprParser) expect(kind tokenKind, expected string) error {
+	if t := p.next(); t.kind != kind {
+		return unexpected(t, expected)
+	}
+
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "OR", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "AND", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxFilterExprDepth {
+		return nil, &ExprError{Pos: p.peek().pos, Msg: "expression is nested too deeply"}
+	}
+
+	t := p.peek()
+	switch {
+	case p.keyword("not"):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{operand: operand}, nil
+	case t.kind == tokLParen:
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRParen, "\")\""); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case t.kind == tokIdent && !exprKeywords[t.text]:
+		p.next()
+		if p.peek().kind == tokLParen {
+			return p.parseCall(t)
+		}
+		return p.parseCompare(t)
+	}
+
+	return nil, unexpected(t, "field, function, not or \"(\"")
+}
+
+func (p *exprParser) parseCall(name token) (exprNode, error) {
+	p.next()
+	call := &callExpr{name: name}
+	if p.peek().kind == tokRParen {
+		p.next()
+		return call, nil
+	}
+
+	values, err := p.parseValues()
+	if err != nil {
+		return nil, err
+	}
+	call.args = values
+
+	return call, nil
+}
+
+// parseValues разбирает список значений через запятую до закрывающей скобки включительно
+func (p *exprParser) parseValues() ([]token, error) {
+	var values []token
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		t := p.next()
+		switch t.kind {
+		case tokComma:
+			continue
+		case tokRParen:
+			return values, nil
+		}
+		return nil, unexpected(t, "\",\" or \")\"")
+	}
+}
+
+func (p *exprParser) parseValue() (token, error) {
+	t := p.next()
+	if t.kind == tokString || t.kind == tokNumber || (t.kind == tokIdent && (t.text == "true" || t.text == "false")) {
+		return t, nil
+	}
+
+	return token{}, unexpected(t, "value")
+}
+
+func (p *exprParser) parseCompare(field token) (exprNode, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokOp:
+		p.next()
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return &compareExpr{field: field, op: t.text, opPos: t.pos, values: []token{value}}, nil
+	case p.keyword("in"):
+		p.next()
+		if err := p.expect(tokLParen, "\"(\""); err != nil {
+			return nil, err
+		}
+		values, err := p.parseValues()
+		if err != nil {
+			return nil, err
+		}
+		return &compareExpr{field: field, op: "in", opPos: t.pos, values: values}, nil
+	case p.keyword("is"):
+		p.next()
+		op := "is null"
+		if p.keyword("not") {
+			p.next()
+			op = "is not null"
+		}
+		if !p.keyword("null") {
+			return nil, unexpected(p.peek(), "null")
+		}
+		p.next()
+		return &compareExpr{field: field, op: op, opPos: t.pos}, nil
+	}
+
+	return &compareExpr{field: field, opPos: field.pos}, nil
+}
+
+// exprType - тип поля или аргумента функции в выражении фильтра
+type exprType int
+
+const (
+	exprText exprType = iota
+	exprStatus
+	exprInt
+	exprMonth
+	exprUUID
+	exprBool
+)
+
+func (t exprType) String() string {
+	switch t {
+	case exprText:
+		return "a string"
+	case exprStatus:
+		return "a status string"
+	case exprInt:
+		return "an integer"
+	case exprMonth:
+		return "a \"YYYY-MM\" string"
+	case exprUUID:
+		return "a UUID string"
+	}
+
+	return "true or false"
+}
+
+// ops - операторы, допустимые для типа. Пустая строка - логическое поле без оператора
+func (t exprType) ops() []string {
+	switch t {
+	case exprText:
+		return []string{"=", "!=", "~", "!~", "in"}
+	case exprStatus, exprUUID:
+		return []string{"=", "!=", "in"}
+	case exprInt, exprMonth:
+		return []string{"=", "!=", "<", "<=", ">", ">=", "in"}
+	}
+
+	return []string{"=", "!=", ""}
+}
+
+// exprField - поле, доступное в выражении. column - SQL с %[1]s на месте префикса таблицы
+type exprField struct {
+	column   string
+	kind     exprType
+	nullable bool
+}
+
+// exprFields - белый список полей выражения фильтра
+var exprFields = map[string]exprField{
+	"service_name":   {column: "%[1]sservice_name", kind: exprText},
+	"price":          {column: "subscription_price_at(%[1]sid, date_trunc('month', NOW())::DATE)", kind: exprInt},
+	"status":         {column: "%[1]sstatus", kind: exprStatus},
+	"user_id":        {column: "%[1]suser_id", kind: exprUUID},
+	"service_id":     {column: "%[1]sservice_id", kind: exprUUID, nullable: true},
+	"category_id":    {column: "%[1]scategory_id", kind: exprUUID, nullable: true},
+	"start_date":     {column: "%[1]sstart_date", kind: exprMonth},
+	"end_date":       {column: "%[1]send_date", kind: exprMonth, nullable: true},
+	"trial_ends_at":  {column: "%[1]strial_ends_at", kind: exprMonth, nullable: true},
+	"auto_renew":     {column: "%[1]sauto_renew", kind: exprBool},
+	"renewal_months": {column: "%[1]srenewal_months", kind: exprInt},
+	"intro_price":    {column: "%[1]sintro_price", kind: exprInt, nullable: true},
+	"cancel_reason":  {column: "%[1]scancel_reason", kind: exprText, nullable: true},
+}
+
+// exprFunc - функция выражения. sql - условие с %[1]s на месте префикса таблицы и %[2]s... на месте аргументов
+type exprFunc struct {
+	args []exprType
+	sql  string
+}
+
+var exprFuncs = map[string]exprFunc{
+	// подписка действует и не на паузе в месяце
+	"active_at": {args: []exprType{exprMonth}, sql: "subscription_active_at(%[1]sid, %[2]s)"},
+	"paused_at": {args: []exprType{exprMonth}, sql: "subscription_paused_at(%[1]sid, %[2]s)"},
+	"has_tag": {args: []exprType{exprText}, sql: `EXISTS (SELECT 1 FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+		WHERE st.subscription_id = %[1]sid AND t.name = %[2]s)`},
+}
+
+func sortedKeys[V any](m map[string]V) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return strings.Join(keys, ", ")
+}
+
+// exprValue проверяет, что значение value подходит типу kind, и переводит его в аргумент запроса
+func exprValue(kind exprType, name string, value token) (any, error) {
+	switch {
+	case kind == exprText && value.kind == tokString:
+		return value.text, nil
+	case kind == exprStatus && value.kind == tokString:
+		if _, ok := statusTransitions[value.text]; !ok {
+			return nil, &ExprError{Pos: value.pos, Msg: myerrors.ErrInvalidStatus.Error()}
+		}
+		return value.text, nil
+	case kind == exprInt && value.kind == tokNumber:
+		if n, err := strconv.Atoi(value.text); err == nil {
+			return n, nil
+		}
+	case kind == exprMonth && value.kind == tokString:
+		if month, err := time.Parse(DateLayout, value.text); err == nil {
+			return month, nil
+		}
+	case kind == exprUUID && value.kind == tokString:
+		if id, err := uuid.Parse(value.text); err == nil {
+			return id, nil
+		}
+	case kind == exprBool && value.kind == tokIdent:
+		return value.text == "true", nil
+	}
+
+	return nil, &ExprError{Pos: value.pos, Msg: fmt.Sprintf("%s expects %s, got %s", name, kind, value)}
+}
+
+type exprCompiler struct {
+	prefix string
+	args   []any
+}
+
+// compileFilterExpr разбирает выражение фильтра и переводит его в условие SQL, дописывая значения в args.
+// prefix - как в SubscriptionFilter.where. Ошибки в выражении возвращаются как *ExprError
+func compileFilterExpr(src, prefix string, args []any) (string, []any, error) {
+	node, err := parseFilterExpr(src)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c := &exprCompiler{prefix: prefix, args: args}
+	cond, err := c.compile(node)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return cond, c.args, nil
+}
+
+func (c *exprCompiler) arg(value any) string {
+	c.args = append(c.args, value)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *exprCompiler) compile(node exprNode) (string, error) {
+	switch n := node.(type) {
+	case *logicalExpr:
+		left, err := c.compile(n.left)
+		if err != nil {
+			return "", err
+		}
+		right, err := c.compile(n.right)
+		if err != nil {
+			return "", err
+		}
+		return "(" + left + " " + n.op + " " + right + ")", nil
+	case *notExpr:
+		operand, err := c.compile(n.operand)
+		if err != nil {
+			return "", err
+		}
+		// сравнение с NULL дает NULL, а not должен выбрать такие подписки
+		return "NOT COALESCE(" + operand + ", FALSE)", nil
+	case *callExpr:
+		return c.compileCall(n)
+	case *compareExpr:
+		return c.compileCompare(n)
+	}
+
+	return "", fmt.Errorf("unknown expression node %T", node)
+}
+
+func (c *exprCompiler) compileCall(n *callExpr) (string, error) {
+	fn, ok := exprFuncs[n.name.text]
+	if !ok {
+		return "", &ExprError{Pos: n.name.pos, Msg: fmt.Sprintf("unknown function %q, allowed: %s", n.name.text, sortedKeys(exprFuncs))}
+	}
+	if len(n.args) != len(fn.args) {
+		return "", &ExprError{Pos: n.name.pos, Msg: fmt.Sprintf("%s takes %d argument(s), got %d", n.name.text, len(fn.args), len(n.args))}
+	}
+
+	params := []any{c.prefix}
+	for i, arg := range n.args {
+		value, err := exprValue(fn.args[i], n.name.text, arg)
+		if err != nil {
+			return "", err
+		}
+		if n.name.text == "has_tag" {
+			value = normalizeTag(value.(string))
+		}
+		params = append(params, c.arg(value))
+	}
+
+	return fmt.Sprintf(fn.sql, params...), nil
+}
+
+func (c *exprCompiler) compileCompare(n *compareExpr) (string, error) {
+	name := n.field.text
+	field, ok := exprFields[name]
+	if !ok {
+		return "", &ExprError{Pos: n.field.pos, Msg: fmt.Sprintf("unknown field %q, allowed: %s", name, sortedKeys(exprFields))}
+	}
+	column := fmt.Sprintf(field.column, c.prefix)
+
+	switch {
+	case n.op == "is null" || n.op == "is not null":
+		if !field.nullable {
+			return "", &ExprError{Pos: n.opPos, Msg: fmt.Sprintf("%s is never null", name)}
+		}
+		return column + " " + strings.ToUpper(n.op), nil
+	case n.op == "" && field.kind != exprBool:
+		return "", &ExprError{Pos: n.opPos, Msg: fmt.Sprintf("%s is not a boolean field, expected an operator after it", name)}
+	case !slices.Contains(field.kind.ops(), n.op):
+		return "", &ExprError{Pos: n.opPos, Msg: fmt.Sprintf("operator %s is not supported for %s", n.op, name)}
+	case n.op == "":
+		return column, nil
+	}
+
+	values := make([]any, 0, len(n.values))
+	for _, token := range n.values {
+		value, err := exprValue(field.kind, name, token)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+	}
+
+	// текст сравнивается без учета регистра
+	if field.kind == exprText {
+		switch n.op {
+		case "~":
+			return column + " ILIKE " + c.arg("%"+likeEscaper.Replace(values[0].(string))+"%"), nil
+		case "!~":
+			return "COALESCE(" + column + ", '') NOT ILIKE " + c.arg("%"+likeEscaper.Replace(values[0].(string))+"%"), nil
+		}
+		column = "lower(" + column + ")"
+		for i := range values {
+			values[i] = strings.ToLower(values[i].(string))
+		}
+	}
+
+	switch n.op {
+	case "in":
+		placeholders := make([]string, 0, len(values))
+		for _, value := range values {
+			placeholders = append(placeholders, c.arg(value))
+		}
+		return column + " IN (" + strings.Join(placeholders, ", ") + ")", nil
+	case "!=":
+		return column + " IS DISTINCT FROM " + c.arg(values[0]), nil
+	}
+
+	return column + " " + n.op + " " + c.arg(values[0]), nil
+}
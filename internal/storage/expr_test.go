@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// exprPrice - SQL поля price с префиксом "s."
+const exprPrice = "subscription_price_at(s.id, date_trunc('month', NOW())::DATE)"
+
+func TestCompileFilterExpr(t *testing.T) {
+	userID := uuid.MustParse("5b0c7a4e-3f7e-4d6b-9a43-2f1c0e3d8a11")
+
+	tests := []struct {
+		name string
+		expr string
+		sql  string
+		// args - значения после уже переданного "existing"
+		args []any
+	}{
+		// приоритет и группировка
+		{"and binds tighter than or", `auto_renew or renewal_months = 12 and service_name = "Netflix"`,
+			"(s.auto_renew OR (s.renewal_months = $2 AND lower(s.service_name) = $3))", []any{12, "netflix"}},
+		{"parentheses override precedence", `(auto_renew or renewal_months = 12) and price > 500`,
+			"((s.auto_renew OR s.renewal_months = $2) AND " + exprPrice + " > $3)", []any{12, 500}},
+		{"and is left associative", `auto_renew and price > 1 and price < 9`,
+			"((s.auto_renew AND " + exprPrice + " > $2) AND " + exprPrice + " < $3)", []any{1, 9}},
+		{"or is left associative", `auto_renew or price > 1 or price < 9`,
+			"((s.auto_renew OR " + exprPrice + " > $2) OR " + exprPrice + " < $3)", []any{1, 9}},
+		{"not binds tighter than and", `not auto_renew and status = "active"`,
+			"(NOT COALESCE(s.auto_renew, FALSE) AND s.status = $2)", []any{"active"}},
+		{"not applies to a group", `not (auto_renew or end_date is null)`,
+			"NOT COALESCE((s.auto_renew OR s.end_date IS NULL), FALSE)", nil},
+		{"double not", `not not auto_renew`,
+			"NOT COALESCE(NOT COALESCE(s.auto_renew, FALSE), FALSE)", nil},
+		{"keywords and names ignore case", `AUTO_RENEW And Price >= 100`,
+			"(s.auto_renew AND " + exprPrice + " >= $2)", []any{100}},
+
+		// операторы и типы
+		{"not equal matches null", `user_id != "` + userID.String() + `"`,
+			"s.user_id IS DISTINCT FROM $2", []any{userID}},
+		{"in list", `status in ("active", 'paused')`,
+			"s.status IN ($2, $3)", []any{"active", "paused"}},
+		{"text in list is lowercased", `service_name in ("Netflix", "Spotify")`,
+			"lower(s.service_name) IN ($2, $3)", []any{"netflix", "spotify"}},
+		{"is not null", `cancel_reason is not null`, "s.cancel_reason IS NOT NULL", nil},
+		{"month", `start_date >= "2025-01"`,
+			"s.start_date >= $2", []any{time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)}},
+		{"negative number", `intro_price > -1`, "s.intro_price > $2", []any{-1}},
+		{"boolean literal", `auto_renew = false`, "s.auto_renew = $2", []any{false}},
+		{"not like keeps null", `cancel_reason !~ "price"`,
+			"COALESCE(s.cancel_reason, '') NOT ILIKE $2", []any{"%price%"}},
+		{"function", `active_at("2025-06")`,
+			"subscription_active_at(s.id, $2)", []any{time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)}},
+		{"has_tag normalizes the tag", `has_tag("  Work   Stuff ")`,
+			`EXISTS (SELECT 1 FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+		WHERE st.subscription_id = s.id AND t.name = $2)`, []any{"work stuff"}},
+
+		// кавычки и экранирование
+		{"escaped double quote", `service_name = "Net\"flix"`,
+			"lower(s.service_name) = $2", []any{`net"flix`}},
+		{"escaped single quote", `service_name = 'it\'s'`,
+			"lower(s.service_name) = $2", []any{"it's"}},
+		{"other quote needs no escape", `service_name = "it's"`,
+			"lower(s.service_name) = $2", []any{"it's"}},
+		{"escaped backslash", `cancel_reason = "a\\b"`,
+			"lower(s.cancel_reason) = $2", []any{`a\b`}},
+		{"like wildcards are escaped", `service_name ~ "50%_off\\"`,
+			"s.service_name ILIKE $2", []any{`%50\%\_off\\%`}},
+		{"sql in a string stays a value", `service_name = "x'); DROP TABLE subscriptions; --"`,
+			"lower(s.service_name) = $2", []any{"x'); drop table subscriptions; --"}},
+		{"keyword in a string stays a value", `cancel_reason = "or 1 = 1"`,
+			"lower(s.cancel_reason) = $2", []any{"or 1 = 1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := compileFilterExpr(tt.expr, "s.", []any{"existing"})
+			if err != nil {
+				t.Fatalf("compileFilterExpr(%q): %v", tt.expr, err)
+			}
+			if sql != tt.sql {
+				t.Errorf("sql:\n got %s\nwant %s", sql, tt.sql)
+			}
+
+			want := append([]any{"existing"}, tt.args...)
+			if !reflect.DeepEqual(args, want) {
+				t.Errorf("args: got %#v, want %#v", args, want)
+			}
+		})
+	}
+}
+
+var exprPlaceholder = regexp.MustCompile(`\$(\d+)`)
+
+// TestCompileFilterExprBindsLiterals проверяет, что ни одно значение из выражения не попадает в текст SQL
+func TestCompileFilterExprBindsLiterals(t *testing.T) {
+	exprs := []string{
+		`service_name = "zzmarker"`,
+		`service_name ~ "zzmarker"`,
+		`cancel_reason !~ "zzmarker"`,
+		`service_name in ("zzmarker", "zzmarker2")`,
+		`has_tag("zzmarker")`,
+		`price = 7349`,
+		`renewal_months in (7349, 7350)`,
+		`intro_price <= -7349`,
+		`start_date < "2031-07" and end_date > "2031-07"`,
+		`active_at("2031-07") or paused_at("2031-07")`,
+		`user_id = "7349a0c1-0000-4000-8000-000000000000"`,
+		`status = "cancelled"`,
+		`auto_renew = true`,
+	}
+	literals := []string{"zzmarker", "7349", "2031", "7349a0c1", "cancelled", "true"}
+
+	for _, expr := range exprs {
+		t.Run(expr, func(t *testing.T) {
+			sql, args, err := compileFilterExpr(expr, "", nil)
+			if err != nil {
+				t.Fatalf("compileFilterExpr(%q): %v", expr, err)
+			}
+			lower := strings.ToLower(sql)
+			for _, literal := range literals {
+				if strings.Contains(lower, literal) {
+					t.Errorf("sql %q contains literal %q", sql, literal)
+				}
+			}
+
+			// плейсхолдеры идут подряд с $1, по одному на каждый аргумент
+			placeholders := exprPlaceholder.FindAllStringSubmatch(sql, -1)
+			if len(placeholders) != len(args) || len(args) == 0 {
+				t.Fatalf("sql %q has %d placeholders for %d args", sql, len(placeholders), len(args))
+			}
+			for i, match := range placeholders {
+				if match[1] != fmt.Sprint(i+1) {
+					t.Errorf("placeholder %d is $%s in %q", i+1, match[1], sql)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileFilterExprErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		pos  int
+		msg  string
+	}{
+		{"empty", "   ", 1, "empty expression"},
+		{"unknown field", `price > 1 and owner = "x"`, 15, `unknown field "owner", allowed: auto_renew, cancel_reason,`},
+		{"raw column name", `id = "x"`, 1, `unknown field "id"`},
+		{"unknown function", `price > 1 and drop_table("x")`, 15, `unknown function "drop_table", allowed: active_at, has_tag, paused_at`},
+		{"unknown function without args", `now()`, 1, `unknown function "now"`},
+		{"wrong number of arguments", `active_at()`, 1, "active_at takes 1 argument(s), got 0"},
+		{"unexpected character", `price > 1; drop table subscriptions`, 10, `unexpected character ';'`},
+		{"unterminated string", `service_name = "abc`, 16, "unterminated string"},
+		{"escaped closing quote", `service_name = "abc\"`, 16, "unterminated string"},
+		{"missing closing paren", `(auto_renew or price > 1`, 25, `unexpected end of expression, expected ")"`},
+		{"trailing tokens", `auto_renew auto_renew`, 12, `unexpected "auto_renew", expected and, or or end of expression`},
+		{"dangling operator", `auto_renew and`, 15, `unexpected end of expression, expected field, function, not or "("`},
+		{"field as value", `price = renewal_months`, 9, `unexpected "renewal_months", expected value`},
+		{"wrong value type", `price = "5"`, 9, `price expects an integer, got string "5"`},
+		{"bad month", `start_date = "2025-13"`, 14, `start_date expects a "YYYY-MM" string`},
+		{"bad uuid", `user_id = "nope"`, 11, "user_id expects a UUID string"},
+		{"bad status", `status = "gone"`, 10, "status must be one of"},
+		{"operator not supported", `status ~ "act"`, 8, "operator ~ is not supported for status"},
+		{"not a boolean field", `price and auto_renew`, 1, "price is not a boolean field"},
+		{"never null", `price is null`, 7, "price is never null"},
+		{"is without null", `end_date is 1`, 13, "unexpected \"1\", expected null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertExprError(t, tt.expr, tt.pos, tt.msg)
+		})
+	}
+}
+
+func TestCompileFilterExprDepthLimit(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		open  string
+		close string
+	}{
+		{"parentheses", "(", ")"},
+		{"not", "not ", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			nest := func(n int) string {
+				return strings.Repeat(tt.open, n) + "auto_renew" + strings.Repeat(tt.close, n)
+			}
+
+			// поле внутри maxFilterExprDepth-1 уровней - ровно maxFilterExprDepth вызовов parseUnary
+			if _, _, err := compileFilterExpr(nest(maxFilterExprDepth-1), "", nil); err != nil {
+				t.Fatalf("depth %d: %v", maxFilterExprDepth, err)
+			}
+			assertExprError(t, nest(maxFilterExprDepth), maxFilterExprDepth*len(tt.open)+1, "expression is nested too deeply")
+		})
+	}
+
+	// and и or не увеличивают вложенность
+	long := strings.Repeat("auto_renew and ", 2*maxFilterExprDepth) + "auto_renew"
+	if _, _, err := compileFilterExpr(long, "", nil); err != nil {
+		t.Fatalf("flat chain: %v", err)
+	}
+}
+
+func TestCompileFilterExprLengthLimit(t *testing.T) {
+	// длина считается в символах, а не в байтах
+	value := func(length int) string {
+		const head = `service_name = "`
+		return head + strings.Repeat("я", length-len(head)-1) + `"`
+	}
+
+	sql, args, err := compileFilterExpr(value(maxFilterExprLength), "", nil)
+	if err != nil {
+		t.Fatalf("%d characters: %v", maxFilterExprLength, err)
+	}
+	if sql != "lower(service_name) = $1" || len(args) != 1 {
+		t.Errorf("got %q with %d args", sql, len(args))
+	}
+
+	assertExprError(t, value(maxFilterExprLength+1), maxFilterExprLength+1, "expression is longer than 1000 characters")
+}
+
+func assertExprError(t *testing.T, expr string, pos int, msg string) {
+	t.Helper()
+
+	sql, args, err := compileFilterExpr(expr, "", []any{"existing"})
+	var exprErr *ExprError
+	if !errors.As(err, &exprErr) {
+		t.Fatalf("compileFilterExpr(%q) = %q, %v, %v; want *ExprError", expr, sql, args, err)
+	}
+	if exprErr.Pos != pos || !strings.Contains(exprErr.Msg, msg) {
+		t.Errorf("got error at %d: %s\nwant error at %d containing: %s", exprErr.Pos, exprErr.Msg, pos, msg)
+	}
+	if sql != "" || args != nil {
+		t.Errorf("got sql %q and args %v with an error", sql, args)
+	}
+}
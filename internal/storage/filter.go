@@ -0,0 +1,296 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionFilter - фильтры списка подписок и подсчета стоимости, пустые поля не применяются.
+// Значения приходят из query-параметров как есть и проверяются здесь
+type SubscriptionFilter struct {
+	// UserID - владелец или участник общей подписки
+	UserID string
+	// ServiceNames - подписка на любой из перечисленных сервисов
+	ServiceNames []string
+	// CategoryID включает и все дочерние категории
+	CategoryID string
+	// Tags - подписка должна иметь все перечисленные теги
+	Tags []string
+	// Statuses - подписка должна иметь один из перечисленных статусов
+	Statuses []string
+	// Query - поиск по названию сервиса: префикс или нечеткое совпадение без учета регистра
+	Query string
+	// StartFrom, StartTo - месяцы YYYY-MM, в которые (включительно) попадает start_date
+	StartFrom string
+	StartTo   string
+	// EndFrom, EndTo - то же для end_date; бессрочные подписки под этот фильтр не попадают
+	EndFrom string
+	EndTo   string
+	// ActiveAt - месяц YYYY-MM, в котором подписка действует и не стоит на паузе
+	ActiveAt string
+	// PriceMin, PriceMax - границы цены (включительно): цены в месяце ActiveAt, если он задан, иначе текущей
+	PriceMin string
+	PriceMax string
+	// Expr - выражение фильтра, см. expr.go
+	Expr string
+	// AsOf - момент (RFC 3339 или YYYY-MM-DD), на который список восстанавливается по истории версий.
+	// Учитывается только GetListSubscriptions
+	AsOf string
+	// Limit - размер страницы (1..MaxListLimit), After - курсор EncodeCursor последней подписки
+	// предыдущей страницы. Учитываются только GetListSubscriptions
+	Limit string
+	After string
+}
+
+// ParamError - ошибка одного параметра фильтра
+type ParamError struct {
+	Param   string `json:"param"`
+	Message string `json:"error"`
+	// Position - номер символа (с 1) в выражении filter, на котором найдена ошибка
+	Position int `json:"position,omitempty"`
+	err      error
+}
+
+func (e ParamError) Error() string {
+	return e.Message
+}
+
+func (e ParamError) Unwrap() error {
+	return e.err
+}
+
+// FilterError - все некорректные параметры фильтра
+type FilterError struct {
+	Params []ParamError
+}
+
+func (e *FilterError) Error() string {
+	messages := make([]string, 0, len(e.Params))
+	for _, p := range e.Params {
+		messages = append(messages, p.Message)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (e *FilterError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Params))
+	for _, p := range e.Params {
+		errs = append(errs, p)
+	}
+
+	return errs
+}
+
+// add записывает ошибку параметра param; err - ошибка myerrors, если сообщение совпадает с ней
+func (e *FilterError) add(param, message string, err error) {
+	e.Params = append(e.Params, ParamError{Param: param, Message: message, err: err})
+}
+
+// month разбирает параметр-месяц YYYY-MM, пустой дает nil
+func (e *FilterError) month(param, value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	month, err := time.Parse(DateLayout, value)
+	if err != nil {
+		e.add(param, param+" must be in YYYY-MM format", nil)
+		return nil
+	}
+
+	return &month
+}
+
+// price разбирает параметр-цену, пустой дает nil
+func (e *FilterError) price(param, value string) *int {
+	if value == "" {
+		return nil
+	}
+	price, err := strconv.Atoi(value)
+	if err != nil || price < 0 {
+		e.add(param, param+" must be a non-negative integer", nil)
+		return nil
+	}
+
+	return &price
+}
+
+// monthRange дописывает условие from <= column <= to
+func monthRange(column string, from, to *time.Time, args []any) (string, []any) {
+	var conds string
+	if from != nil {
+		args = append(args, *from)
+		conds += fmt.Sprintf(" AND %s >= $%d", column, len(args))
+	}
+	if to != nil {
+		args = append(args, *to)
+		conds += fmt.Sprintf(" AND %s <= $%d", column, len(args))
+	}
+
+	return conds, args
+}
+
+// where дописывает к args параметры фильтра и возвращает условия вида " AND ...".
+// prefix - имя или алиас таблицы subscriptions с точкой; без него id в подзапросе по тегам
+// ссылался бы на tags.id. Некорректные параметры возвращаются все сразу в *FilterError
+func (f SubscriptionFilter) where(prefix string, args []any) (string, []any, error) {
+	var conds string
+	var invalid FilterError
+
+	if f.UserID != "" {
+		if _, err := uuid.Parse(f.UserID); err != nil {
+			invalid.add("user_id", "invalid user_id", nil)
+		} else {
+			// общие подписки выбираются один раз в массив, а не подзапросом на каждую строку:
+			// так обе ветки OR идут по индексам
+			args = append(args, f.UserID)
+			conds += fmt.Sprintf(` AND (%[1]suser_id = $%[2]d OR %[1]sid = ANY(ARRAY(
+			SELECT sm.subscription_id FROM subscription_members sm WHERE sm.user_id = $%[2]d)))`,
+				prefix, len(args))
+		}
+	}
+
+	names := []string{}
+	for _, name := range f.ServiceNames {
+		if name = normalizeServiceName(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		args = append(args, names)
+		conds += serviceNameFilter(prefix, len(args))
+	}
+
+	if f.CategoryID != "" {
+		if _, err := uuid.Parse(f.CategoryID); err != nil {
+			invalid.add("category_id", "invalid category_id", nil)
+		} else {
+			args = append(args, f.CategoryID)
+			conds += fmt.Sprintf(` AND %scategory_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE id = $%d
+				UNION ALL
+				SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id)
+			SELECT id FROM tree)`, prefix, len(args))
+		}
+	}
+
+	for _, tag := range f.Tags {
+		tag = normalizeTag(tag)
+		if tag == "" {
+			continue
+		}
+		args = append(args, tag)
+		conds += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+			WHERE st.subscription_id = %sid AND t.name = $%d)`, prefix, len(args))
+	}
+
+	if len(f.Statuses) > 0 {
+		if err := validStatuses(f.Statuses); err != nil {
+			invalid.add("status", err.Error(), err)
+		} else {
+			args = append(args, f.Statuses)
+			conds += fmt.Sprintf(" AND %sstatus = ANY($%d)", prefix, len(args))
+		}
+	}
+
+	if query := normalizeServiceName(f.Query); query != "" {
+		args = append(args, likePrefix(query), query)
+		conds += fmt.Sprintf(` AND (lower(%[1]sservice_name) LIKE $%[2]d OR $%[3]d <%% lower(%[1]sservice_name))`,
+			prefix, len(args)-1, len(args))
+	}
+
+	startFrom, startTo := invalid.month("start_from", f.StartFrom), invalid.month("start_to", f.StartTo)
+	if startFrom != nil && startTo != nil && startTo.Before(*startFrom) {
+		invalid.add("start_to", "start_to can not be earlier than start_from", nil)
+	}
+	endFrom, endTo := invalid.month("end_from", f.EndFrom), invalid.month("end_to", f.EndTo)
+	if endFrom != nil && endTo != nil && endTo.Before(*endFrom) {
+		invalid.add("end_to", "end_to can not be earlier than end_from", nil)
+	}
+	activeAt := invalid.month("active_at", f.ActiveAt)
+	priceMin, priceMax := invalid.price("price_min", f.PriceMin), invalid.price("price_max", f.PriceMax)
+	if priceMin != nil && priceMax != nil && *priceMax < *priceMin {
+		invalid.add("price_max", "price_max can not be less than price_min", nil)
+	}
+
+	// as_of понимает только список, остальные запросы считают по текущему состоянию
+	if f.AsOf != "" {
+		invalid.add("as_of", "as_of is supported only by the subscription list", nil)
+	}
+
+	invalid.limit("limit", f.Limit)
+	if f.After != "" {
+		// с q список упорядочен по релевантности, а не по (start_date, id)
+		start, id, err := decodeCursor(f.After)
+		switch {
+		case err != nil:
+			invalid.add("after", "after must be a next_cursor value", nil)
+		case normalizeServiceName(f.Query) != "":
+			invalid.add("after", "after can not be combined with q", nil)
+		default:
+			args = append(args, start, id)
+			conds += fmt.Sprintf(" AND (%[1]sstart_date, %[1]sid) > ($%[2]d, $%[3]d)", prefix, len(args)-1, len(args))
+		}
+	}
+
+	if f.Expr != "" {
+		cond, exprArgs, err := compileFilterExpr(f.Expr, prefix, args)
+		var exprErr *ExprError
+		switch {
+		case errors.As(err, &exprErr):
+			invalid.Params = append(invalid.Params, ParamError{
+				Param: "filter", Message: "filter: " + exprErr.Error(), Position: exprErr.Pos, err: exprErr})
+		case err != nil:
+			return "", nil, err
+		default:
+			conds += " AND " + cond
+			args = exprArgs
+		}
+	}
+
+	if len(invalid.Params) > 0 {
+		return "", nil, &invalid
+	}
+
+	var cond string
+	cond, args = monthRange(prefix+"start_date", startFrom, startTo, args)
+	conds += cond
+	cond, args = monthRange(prefix+"end_date", endFrom, endTo, args)
+	conds += cond
+
+	// как и price в ответе, текущая цена - цена в текущем месяце с учетом истории цен
+	price := fmt.Sprintf("subscription_price_at(%sid, date_trunc('month', NOW())::DATE)", prefix)
+	if activeAt != nil {
+		args = append(args, *activeAt)
+		conds += fmt.Sprintf(" AND subscription_active_at(%sid, $%d)", prefix, len(args))
+		price = fmt.Sprintf("subscription_price_at(%sid, $%d)", prefix, len(args))
+	}
+	if priceMin != nil {
+		args = append(args, *priceMin)
+		conds += fmt.Sprintf(" AND %s >= $%d", price, len(args))
+	}
+	if priceMax != nil {
+		args = append(args, *priceMax)
+		conds += fmt.Sprintf(" AND %s <= $%d", price, len(args))
+	}
+
+	return conds, args, nil
+}
+
+// costExpr возвращает выражение стоимости подписки за месяц month: с фильтром по пользователю - только
+// его доля в общей подписке, иначе полная стоимость. id - выражение с ID подписки
+func (f SubscriptionFilter) costExpr(id, month string, args []any) (string, []any) {
+	if f.UserID == "" {
+		return fmt.Sprintf("subscription_cost_at(%s, %s)", id, month), args
+	}
+
+	args = append(args, f.UserID)
+	return fmt.Sprintf("subscription_share_at(%s, $%d::UUID, %s)", id, len(args), month), args
+}
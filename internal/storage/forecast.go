@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+// maxForecastMonths - наибольший горизонт прогноза (10 лет)
+const maxForecastMonths = 120
+
+// GetForecast прогнозирует расходы на months месяцев начиная с from (пустой - текущий месяц UTC)
+// по неистекшим подпискам с теми же фильтрами, что у списка. Каждый месяц стоит столько же, сколько
+// в GetTotalCost: с пробным периодом, вступительной ценой, паузами и ценой, действующей в этом месяце.
+// После end_date подписка продолжается, только если продлевается по режиму renewal; отмененные
+// подписки не продлеваются никогда
+func (s *Storage) GetForecast(filter SubscriptionFilter, from string, months int, renewal string) (*ForecastResponse, error) {
+	const op = "storage.forecast.GetForecast"
+
+	if months < 1 || months > maxForecastMonths {
+		return nil, fmt.Errorf("%s: %w", op, myerrors.ErrInvalidHorizon)
+	}
+	if renewal == "" {
+		renewal = ForecastRenewalAuto
+	}
+	if renewal != ForecastRenewalAuto && renewal != ForecastRenewalAll && renewal != ForecastRenewalNone {
+		return nil, fmt.Errorf("%s: %w", op, myerrors.ErrInvalidRenewalMode)
+	}
+
+	now := time.Now().UTC()
+	fromDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if from != "" {
+		parsed, err := time.Parse(DateLayout, from)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, invalidRequest("from", err))
+		}
+		fromDate = parsed
+	}
+	toDate := fromDate.AddDate(0, months-1, 0)
+
+	conds, args, err := filter.where("s.", []any{fromDate, toDate, renewal})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	cost, args := filter.costExpr("subs.id", "mo.m", args)
+
+	// строки с is_month - итоги месяцев, остальные - итоги подписок
+	query := `WITH months AS (
+		SELECT generate_series($1::DATE, $2::DATE, INTERVAL '1 month')::DATE AS m
+	), subs AS (
+		SELECT s.id, s.service_name, s.user_id, s.start_date, s.end_date, s.renewal_months,
+			s.status <> 'cancelled' AND s.end_date IS NOT NULL
+				AND CASE $3::TEXT WHEN 'all' THEN TRUE WHEN 'none' THEN FALSE ELSE s.auto_renew END AS renews
+		FROM subscriptions s
+		WHERE s.status <> 'expired'` + conds + `
+	), items AS (
+		SELECT subs.*, mo.m, ` + cost + ` AS cost
+		FROM subs
+		JOIN months mo ON subs.start_date <= mo.m AND (subs.end_date IS NULL OR subs.renews OR subs.end_date >= mo.m)
+	)
+	SELECT GROUPING(mo.m) = 0 AS is_month, mo.m,
+		i.id, i.service_name, i.user_id, i.end_date, i.renews, i.renewal_months,
+		ROUND(COALESCE(SUM(i.cost), 0))::BIGINT, COUNT(i.id)
+	FROM months mo
+	LEFT JOIN items i ON i.m = mo.m
+	GROUP BY GROUPING SETS ((mo.m), (i.id, i.service_name, i.user_id, i.end_date, i.renews, i.renewal_months))
+	HAVING GROUPING(mo.m) = 0 OR i.id IS NOT NULL
+	ORDER BY is_month DESC, mo.m, i.service_name, i.id`
+
+	rows, err := s.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	resp := &ForecastResponse{
+		From:          fromDate.Format(DateLayout),
+		To:            toDate.Format(DateLayout),
+		Renewal:       renewal,
+		Months:        []ForecastMonth{},
+		Subscriptions: []ForecastSubscription{},
+	}
+	for rows.Next() {
+		var isMonth bool
+		var month, end *time.Time
+		var id, userID *uuid.UUID
+		var serviceName *string
+		var renews *bool
+		var renewalMonths *int
+		var total, count int
+		if err := rows.Scan(&isMonth, &month, &id, &serviceName, &userID, &end, &renews, &renewalMonths,
+			&total, &count); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if isMonth {
+			resp.Months = append(resp.Months, ForecastMonth{Month: month.Format(DateLayout), Total: total, Subscriptions: count})
+			resp.Total += total
+			continue
+		}
+
+		sub := ForecastSubscription{ID: *id, ServiceName: *serviceName, UserID: *userID, Total: total, Months: count}
+		if end != nil {
+			sub.EndDate = end.Format(DateLayout)
+			if *renews {
+				sub.RenewalDates = renewalDates(*end, *renewalMonths, fromDate, toDate)
+				sub.Renews = len(sub.RenewalDates) > 0
+			}
+		}
+		resp.Subscriptions = append(resp.Subscriptions, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// renewalDates возвращает месяцы from..to, с которых начинаются новые сроки подписки,
+// закончившейся в месяце end и продлеваемой на term месяцев
+func renewalDates(end time.Time, term int, from, to time.Time) []string {
+	if term < 1 {
+		term = DefaultRenewalMonths
+	}
+
+	var dates []string
+	for start := end.AddDate(0, 1, 0); !start.After(to); start = start.AddDate(0, term, 0) {
+		if !start.Before(from) {
+			dates = append(dates, start.Format(DateLayout))
+		}
+	}
+
+	return dates
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+// historyColumns - столбцы версии подписки в порядке scanSubscription; цена - в месяце момента $1
+const historyColumns = `h.id, h.service_name, subscription_history_price(h.prices, h.price, date_trunc('month', $1::TIMESTAMPTZ)::DATE),
+	h.user_id, h.start_date, h.end_date, h.service_id, h.category_id,
+	h.auto_renew, h.renewal_months, h.trial_ends_at, h.intro_price, h.intro_months, h.status, h.cancel_reason, h.cancelled_at, h.tags`
+
+// historyValidAt - версия действовала в момент $1
+const historyValidAt = `h.valid_from <= $1 AND (h.valid_to IS NULL OR h.valid_to > $1)`
+
+// parseAsOf разбирает момент as_of: RFC 3339 или дата YYYY-MM-DD (начало дня UTC)
+func parseAsOf(value string) (time.Time, error) {
+	if asOf, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return asOf, nil
+	}
+	if asOf, err := time.Parse(time.DateOnly, value); err == nil {
+		return asOf, nil
+	}
+
+	return time.Time{}, myerrors.ErrInvalidAsOf
+}
+
+// GetSubscriptionAsOf возвращает подписку в том виде, в каком она была в момент asOf, по истории версий.
+// ErrNotFound - подписки в этот момент не было
+func (s *Storage) GetSubscriptionAsOf(id uuid.UUID, asOf string) (*Subscription, error) {
+	const op = "storage.history.GetSubscriptionAsOf"
+
+	at, err := parseAsOf(asOf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sub, err := scanSubscription(s.db.QueryRow(context.Background(), `SELECT `+historyColumns+`
+	FROM subscription_history h
+	WHERE h.id = $2 AND `+historyValidAt, at, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sub, nil
+}
+
+// listSubscriptionsAsOf - GetListSubscriptions по версиям, действовавшим в момент filter.AsOf.
+// Фильтры по паузам, ценам и выражение зависят от текущих данных и с as_of не применяются.
+// Категории и каталог сервисов берутся текущие, владелец и участники - из версии
+func (s *Storage) listSubscriptionsAsOf(filter SubscriptionFilter) ([]Subscription, error) {
+	const op = "storage.history.listSubscriptionsAsOf"
+
+	var invalid FilterError
+	at, err := parseAsOf(filter.AsOf)
+	if err != nil {
+		invalid.add("as_of", err.Error(), err)
+	}
+	if _, err := uuid.Parse(filter.UserID); filter.UserID != "" && err != nil {
+		invalid.add("user_id", "invalid user_id", nil)
+	}
+	for _, p := range []struct{ name, value string }{
+		{"active_at", filter.ActiveAt},
+		{"price_min", filter.PriceMin},
+		{"price_max", filter.PriceMax},
+		{"filter", filter.Expr},
+	} {
+		if p.value != "" {
+			invalid.add(p.name, p.name+" can not be combined with as_of", nil)
+		}
+	}
+
+	current := filter
+	current.ActiveAt, current.PriceMin, current.PriceMax, current.Expr, current.Tags, current.AsOf = "", "", "", "", nil, ""
+	current.UserID = ""
+	conds, args, err := current.where("h.", []any{at})
+	var filterErr *FilterError
+	switch {
+	case errors.As(err, &filterErr):
+		invalid.Params = append(invalid.Params, filterErr.Params...)
+	case err != nil:
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(invalid.Params) > 0 {
+		return nil, fmt.Errorf("%s: %w", op, &invalid)
+	}
+
+	// теги и участники версии хранятся в ней самой
+	if filter.UserID != "" {
+		args = append(args, filter.UserID)
+		conds += fmt.Sprintf(` AND (h.user_id = $%[1]d::UUID OR h.members @> jsonb_build_array(jsonb_build_object('user_id', $%[1]d::UUID)))`,
+			len(args))
+	}
+	for _, tag := range filter.Tags {
+		if tag = normalizeTag(tag); tag != "" {
+			args = append(args, tag)
+			conds += fmt.Sprintf(" AND $%d = ANY(h.tags)", len(args))
+		}
+	}
+
+	rank, args := current.searchRank("h.", args)
+	rows, err := s.db.Query(context.Background(), `SELECT `+historyColumns+`
+	FROM subscription_history h
+	WHERE `+historyValidAt+conds+`
+	ORDER BY `+rank+`h.start_date, h.id`+filter.limitClause(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var subs []Subscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		subs = append(subs, *sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return subs, nil
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+const memberColumns = `user_id, weight, amount, created_at`
+
+func scanMember(row pgx.Row) (*Member, error) {
+	var m Member
+	if err := row.Scan(&m.UserID, &m.Weight, &m.Amount, &m.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// ListMembers возвращает участников общей подписки. Владелец попадает в список, только если его доля задана явно
+func (s *Storage) ListMembers(subscriptionID uuid.UUID) ([]Member, error) {
+	const op = "storage.members.ListMembers"
+
+	if _, err := s.GetSubscription(subscriptionID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.Query(context.Background(), `SELECT `+memberColumns+` FROM subscription_members
+	WHERE subscription_id = $1
+	ORDER BY created_at, user_id`, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	members := []Member{}
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		members = append(members, *m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// SetMember добавляет участника подписки или меняет его долю
+func (s *Storage) SetMember(subscriptionID, userID uuid.UUID, req MemberRequest) (*Member, error) {
+	const op = "storage.members.SetMember"
+
+	if (req.Weight > 0) == (req.Amount > 0) || req.Weight < 0 || req.Amount < 0 {
+		return nil, fmt.Errorf("%s: %w", op, myerrors.ErrInvalidShare)
+	}
+
+	set := memberSetEvent{UserID: userID}
+	if req.Weight > 0 {
+		set.Weight = &req.Weight
+	} else {
+		set.Amount = &req.Amount
+	}
+
+	var member *Member
+	err := s.command(subscriptionID, "", func(ctx context.Context, tx pgx.Tx, strm *stream) error {
+		if err := decideMember(strm, set); err != nil {
+			return err
+		}
+
+		var err error
+		member, err = scanMember(tx.QueryRow(ctx, `SELECT `+memberColumns+` FROM subscription_members
+		WHERE subscription_id = $1 AND user_id = $2`, subscriptionID, userID))
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.checkSubscriptionBudgets(subscriptionID)
+
+	return member, nil
+}
+
+// RemoveMember убирает участника из подписки, его доля переходит к остальным
+func (s *Storage) RemoveMember(subscriptionID, userID uuid.UUID) error {
+	const op = "storage.members.RemoveMember"
+
+	err := s.command(subscriptionID, "", func(_ context.Context, _ pgx.Tx, strm *stream) error {
+		return decideRemoveMember(strm, userID)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// бывший участник больше не платит свою долю
+	s.checkBudgets(userID)
+	s.checkSubscriptionBudgets(subscriptionID)
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/odlev/subscriptions/migrations"
+)
+
+var migrationName = regexp.MustCompile(`^(\d+)_.+\.up\.sql$`)
+
+type migration struct {
+	version int64
+	name    string
+}
+
+// migrate применяет еще не примененные *.up.sql из пакета migrations по порядку версий.
+// Текущая версия хранится в schema_migrations в том же формате, что у golang-migrate,
+// так что базу можно дальше вести и его CLI
+func migrate(ctx context.Context, log *slog.Logger, db *pgxpool.Pool) error {
+	const op = "storage.migrate.migrate"
+
+	// uuid_generate_v4() в DEFAULT таблиц берется из uuid-ossp, а 000001 создает расширение
+	// только после первой таблицы, поэтому оно создается до всех миграций
+	_, err := db.Exec(ctx, `CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = db.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT NOT NULL PRIMARY KEY,
+	dirty BOOLEAN NOT NULL)`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var current int64
+	var dirty bool
+	err = db.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&current, &dirty)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if dirty {
+		return fmt.Errorf("%s: database is dirty at version %d, fix it manually", op, current)
+	}
+
+	pending, err := pendingMigrations(current)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, m := range pending {
+		raw, err := fs.ReadFile(migrations.FS, m.name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = pgx.BeginFunc(ctx, db, func(tx pgx.Tx) error {
+			if sql := strings.TrimSpace(string(raw)); sql != "" {
+				if _, err := tx.Exec(ctx, sql); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, m.version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("%s: migration %s: %w", op, m.name, err)
+		}
+
+		log.Info("migration applied", "migration", m.name)
+	}
+
+	return nil
+}
+
+func pendingMigrations(current int64) ([]migration, error) {
+	entries, err := fs.ReadDir(migrations.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []migration
+	for _, entry := range entries {
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad migration version in %s: %w", entry.Name(), err)
+		}
+		if version > current {
+			pending = append(pending, migration{version: version, name: entry.Name()})
+		}
+	}
+
+	slices.SortFunc(pending, func(a, b migration) int {
+		return cmp.Compare(a.version, b.version)
+	})
+
+	return pending, nil
+}
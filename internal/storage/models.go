@@ -9,37 +9,537 @@ import (
 
 // Subscription - базовая модель БД
 type Subscription struct {
-	ID          uuid.UUID `json:"id,omitempty" example:"550e8400-e29b-41d4-a716-446655440200" format:"uuid"`
-	ServiceName string    `json:"service_name" binding:"required" example:"Netflix"`
-	Price       int       `json:"price" binding:"required,min=1" example:"500"`
-	UserID      uuid.UUID `json:"user_id,omitempty" example:"550e8400-e29b-41d4-a716-446255440000" format:"uuid"`
-	StartDate   time.Time `json:"start_date" binding:"required" example:"2025-07"`
-	EndDate     time.Time `json:"end_date,omitempty" example:"2026-07"`
+	ID            uuid.UUID  `json:"id,omitempty" example:"550e8400-e29b-41d4-a716-446655440200" format:"uuid"`
+	ServiceName   string     `json:"service_name" binding:"required" example:"Netflix"`
+	Price         int        `json:"price" binding:"required,min=1" example:"500"`
+	UserID        uuid.UUID  `json:"user_id,omitempty" example:"550e8400-e29b-41d4-a716-446255440000" format:"uuid"`
+	StartDate     time.Time  `json:"start_date" binding:"required" example:"2025-07"`
+	EndDate       time.Time  `json:"end_date,omitempty" example:"2026-07"`
+	ServiceID     *uuid.UUID `json:"service_id,omitempty" format:"uuid"`
+	CategoryID    *uuid.UUID `json:"category_id,omitempty" format:"uuid"`
+	Tags          []string   `json:"tags"`
+	AutoRenew     bool       `json:"auto_renew"`
+	RenewalMonths int        `json:"renewal_months"`
+	TrialEndsAt   *time.Time `json:"trial_ends_at,omitempty"`
+	IntroPrice    *int       `json:"intro_price,omitempty"`
+	IntroMonths   *int       `json:"intro_months,omitempty"`
+	Status        string     `json:"status"`
+	CancelReason  *string    `json:"cancel_reason,omitempty"`
+	CancelledAt   *time.Time `json:"cancelled_at,omitempty"`
 	//Description *string
 }
 
 // SubscriptionCreateRequest - структура для создания подписки (без ID)
 type SubscriptionCreateRequest struct {
-    ServiceName string    `json:"service_name" binding:"required" example:"Netflix" description:"Название сервиса (обязательное поле)"`
-    Price       int       `json:"price" binding:"required,min=1" example:"500" description:"Стоимость подписки в рублях (обязательное поле)"`
-    UserID      *uuid.UUID `json:"user_id,omitempty" example:"550e8400-e29b-41d4-a716-446655240000" format:"uuid" description:"ID пользователя (если не указан, будет сгенерирован автоматически)"`
-    StartDate   string    `json:"start_date" binding:"required" example:"2025-07" description:"Дата начала в формате YYYY-MM (обязательное поле)"`
-    EndDate     *string   `json:"end_date,omitempty" example:"2026-07" description:"Дата окончания в формате YYYY-MM (если не указана, будет start_date + 1 год)"`
+	ServiceName   string     `json:"service_name" binding:"required_without=ServiceID" example:"Netflix" description:"Название сервиса или его псевдоним из каталога (обязательно, если не указан service_id)"`
+	ServiceID     *uuid.UUID `json:"service_id,omitempty" format:"uuid" description:"ID сервиса из каталога /api/v1/services"`
+	Price         int        `json:"price" binding:"required,min=1" example:"500" description:"Стоимость подписки в рублях (обязательное поле)"`
+	UserID        *uuid.UUID `json:"user_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655240000" format:"uuid" description:"ID существующего пользователя (обязательное поле)"`
+	StartDate     string     `json:"start_date" binding:"required" example:"2025-07" description:"Дата начала в формате YYYY-MM (обязательное поле)"`
+	EndDate       *string    `json:"end_date,omitempty" example:"2026-07" description:"Дата окончания в формате YYYY-MM (если не указана, будет start_date + 1 год)"`
+	CategoryID    *uuid.UUID `json:"category_id,omitempty" format:"uuid" description:"ID категории из /api/v1/categories"`
+	Tags          []string   `json:"tags,omitempty" example:"family,work" description:"Теги подписки, несуществующие создаются"`
+	AutoRenew     bool       `json:"auto_renew,omitempty" description:"Продлевать подписку автоматически по окончании"`
+	RenewalMonths int        `json:"renewal_months,omitempty" binding:"omitempty,min=1,max=120" example:"12" description:"Срок продления в месяцах (по умолчанию 12)"`
+	TrialEndsAt   *string    `json:"trial_ends_at,omitempty" example:"2025-08" description:"Последний месяц бесплатного пробного периода YYYY-MM"`
+	IntroPrice    int        `json:"intro_price,omitempty" binding:"omitempty,min=1" example:"199" description:"Вступительная цена, вместе с intro_months"`
+	IntroMonths   int        `json:"intro_months,omitempty" binding:"omitempty,min=1" example:"3" description:"Сколько месяцев после пробного периода действует intro_price"`
 }
 
 // SubscriptionR - форматированная версия
 type SubscriptionR struct {
-	ID          uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440090" format:"uuid"`
-	ServiceName string    `json:"service_name" example:"Netflix"`
-	Price       int       `json:"price" example:"500"`
-	UserID      uuid.UUID `json:"user_id" example:"550e8400-e29b-41d4-a716-446655240000" format:"uuid"`
-	StartDate   string    `json:"start_date" example:"2025-07"`
-	EndDate     string    `json:"end_date" example:"2026-07"`
+	ID            uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440090" format:"uuid"`
+	ServiceName   string     `json:"service_name" example:"Netflix"`
+	Price         int        `json:"price" example:"500"`
+	UserID        uuid.UUID  `json:"user_id" example:"550e8400-e29b-41d4-a716-446655240000" format:"uuid"`
+	StartDate     string     `json:"start_date" example:"2025-07"`
+	EndDate       string     `json:"end_date" example:"2026-07"`
+	ServiceID     *uuid.UUID `json:"service_id,omitempty" format:"uuid"`
+	CategoryID    *uuid.UUID `json:"category_id,omitempty" format:"uuid"`
+	Tags          []string   `json:"tags" example:"family,work"`
+	AutoRenew     bool       `json:"auto_renew"`
+	RenewalMonths int        `json:"renewal_months" example:"12"`
+	// TrialEndsAt - последний месяц пробного периода, пустой - пробного периода нет
+	TrialEndsAt string `json:"trial_ends_at,omitempty" example:"2025-08"`
+	IntroPrice  int    `json:"intro_price,omitempty" example:"199"`
+	IntroMonths int    `json:"intro_months,omitempty" example:"3"`
+	// Status - trial, active, paused, cancelled или expired
+	Status       string     `json:"status,omitempty" example:"active"`
+	CancelReason string     `json:"cancel_reason,omitempty" example:"too expensive"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	// ConflictPolicy - что делать с пересечением с другой подпиской на тот же сервис, см. ConflictPolicyWarn
+	ConflictPolicy string `json:"-"`
+	// Conflicts - пересекающиеся подписки, в ответ на запись с ConflictPolicyWarn
+	Conflicts []Overlap `json:"conflicts,omitempty"`
 }
 // UpdateSubscriptionRequest - структура для обновления подписки
 type UpdateSubscriptionRequest struct {
-	ServiceName string `json:"service_name,omitempty" example:"Netflix"`
-	Price       int    `json:"price,omitempty" example:"500"`
-	StartDate   string `json:"start_date,omitempty" example:"2025-07"`
-	EndDate     string `json:"end_date,omitempty" example:"2026-07"`
+	ServiceName string     `json:"service_name,omitempty" example:"Netflix"`
+	Price       int        `json:"price,omitempty" example:"500"`
+	StartDate   string     `json:"start_date,omitempty" example:"2025-07"`
+	EndDate     string     `json:"end_date,omitempty" example:"2026-07"`
+	ServiceID   *uuid.UUID `json:"service_id,omitempty" format:"uuid"`
+	// CategoryID - нулевой UUID снимает категорию
+	CategoryID    *uuid.UUID `json:"category_id,omitempty" format:"uuid"`
+	AutoRenew     *bool      `json:"auto_renew,omitempty"`
+	RenewalMonths int        `json:"renewal_months,omitempty" binding:"omitempty,min=1,max=120" example:"12"`
+	// ConflictPolicy приходит из query-параметра conflict_policy
+	ConflictPolicy string `json:"-"`
+	// PriceEffectiveFrom - месяц YYYY-MM, с которого действует новая price (по умолчанию текущий)
+	PriceEffectiveFrom string `json:"price_effective_from,omitempty" example:"2025-09"`
+	// TrialEndsAt - пустая строка убирает пробный период
+	TrialEndsAt *string `json:"trial_ends_at,omitempty" example:"2025-08"`
+	// IntroPrice - 0 убирает вступительную цену вместе с intro_months
+	IntroPrice  *int `json:"intro_price,omitempty" example:"199"`
+	IntroMonths *int `json:"intro_months,omitempty" example:"3"`
+}
+
+// TotalCostResponse - суммарная стоимость подписок за период
+type TotalCostResponse struct {
+	Total int    `json:"total" example:"6000"`
+	From  string `json:"from" example:"2025-01"`
+	To    string `json:"to" example:"2025-12"`
+	// Groups заполняется только при group_by
+	Groups []CostGroup `json:"groups,omitempty"`
+}
+
+// CostGroup - стоимость подписок одной группы. Для group_by=category подписки без категории
+// попадают в группу с пустым category_id
+type CostGroup struct {
+	CategoryID *uuid.UUID `json:"category_id,omitempty" format:"uuid"`
+	Name       string     `json:"name" example:"streaming"`
+	Total      int        `json:"total" example:"3000"`
+}
+
+// SubscriptionsListResponse - ответ со списком подписок
+type SubscriptionsListResponse struct {
+	Subscriptions []SubscriptionR `json:"subscriptions"`
+	// NextCursor - значение after для следующей страницы; есть, только если страница заполнена до limit и нет q
+	NextCursor string `json:"next_cursor,omitempty" example:"MjAyNS0wMS0wMXw1NTBlODQwMC1lMjliLTQxZDQtYTcxNi00NDY2NTU0NDAyMDA"`
+}
+
+// User - пользователь, которому принадлежат подписки
+type User struct {
+	ID              uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655240000" format:"uuid"`
+	Email           *string   `json:"email,omitempty" example:"user@example.com"`
+	DisplayName     string    `json:"display_name" example:"Иван Петров"`
+	Timezone        string    `json:"timezone" example:"Europe/Moscow"`
+	DefaultCurrency string    `json:"default_currency" example:"RUB"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// UserCreateRequest - структура для создания пользователя
+type UserCreateRequest struct {
+	Email           string `json:"email" binding:"required,email" example:"user@example.com"`
+	DisplayName     string `json:"display_name,omitempty" example:"Иван Петров"`
+	Timezone        string `json:"timezone,omitempty" example:"Europe/Moscow" description:"Часовой пояс IANA (по умолчанию UTC)"`
+	DefaultCurrency string `json:"default_currency,omitempty" binding:"omitempty,iso4217" example:"RUB" description:"Код валюты ISO 4217 (по умолчанию RUB)"`
+}
+
+// UserUpdateRequest - структура для обновления пользователя, пустые поля не меняются
+type UserUpdateRequest struct {
+	Email           string `json:"email,omitempty" binding:"omitempty,email" example:"user@example.com"`
+	DisplayName     string `json:"display_name,omitempty" example:"Иван Петров"`
+	Timezone        string `json:"timezone,omitempty" example:"Europe/Moscow"`
+	DefaultCurrency string `json:"default_currency,omitempty" binding:"omitempty,iso4217" example:"RUB"`
+}
+
+// UsersListResponse - ответ со списком пользователей
+type UsersListResponse struct {
+	Users []User `json:"users"`
+}
+
+// Service - сервис из каталога. Название и псевдонимы используются для нормализации service_name подписок
+type Service struct {
+	ID              uuid.UUID `json:"id" format:"uuid"`
+	Name            string    `json:"name" example:"Netflix"`
+	Aliases         []string  `json:"aliases" example:"netflix hd,нетфликс"`
+	Category        string    `json:"category,omitempty" example:"streaming"`
+	Website         string    `json:"website,omitempty" example:"https://netflix.com"`
+	DefaultCurrency string    `json:"default_currency" example:"RUB"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ServiceCreateRequest - структура для добавления сервиса в каталог
+type ServiceCreateRequest struct {
+	Name            string   `json:"name" binding:"required" example:"Netflix"`
+	Aliases         []string `json:"aliases,omitempty" example:"netflix hd,нетфликс"`
+	Category        string   `json:"category,omitempty" example:"streaming"`
+	Website         string   `json:"website,omitempty" binding:"omitempty,url" example:"https://netflix.com"`
+	DefaultCurrency string   `json:"default_currency,omitempty" binding:"omitempty,iso4217" example:"RUB"`
+}
+
+// ServiceUpdateRequest - структура для обновления сервиса. Пустые поля не меняются,
+// aliases заменяются целиком, если переданы (пустой массив очищает список)
+type ServiceUpdateRequest struct {
+	Name            string   `json:"name,omitempty" example:"Netflix"`
+	Aliases         []string `json:"aliases,omitempty" example:"netflix hd,нетфликс"`
+	Category        string   `json:"category,omitempty" example:"streaming"`
+	Website         string   `json:"website,omitempty" binding:"omitempty,url" example:"https://netflix.com"`
+	DefaultCurrency string   `json:"default_currency,omitempty" binding:"omitempty,iso4217" example:"RUB"`
+}
+
+// ServicesListResponse - ответ со списком сервисов каталога
+type ServicesListResponse struct {
+	Services []Service `json:"services"`
+}
+
+// Category - категория подписок. Категории образуют дерево через parent_id
+type Category struct {
+	ID        uuid.UUID  `json:"id" format:"uuid"`
+	Name      string     `json:"name" example:"streaming"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty" format:"uuid"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// CategoryCreateRequest - структура для создания категории
+type CategoryCreateRequest struct {
+	Name     string     `json:"name" binding:"required" example:"streaming"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty" format:"uuid" description:"Родительская категория, без нее категория корневая"`
+}
+
+// CategoryUpdateRequest - структура для обновления категории, пустые поля не меняются.
+// Нулевой UUID в parent_id делает категорию корневой
+type CategoryUpdateRequest struct {
+	Name     string     `json:"name,omitempty" example:"video"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty" format:"uuid"`
+}
+
+// CategoriesListResponse - ответ со списком категорий
+type CategoriesListResponse struct {
+	Categories []Category `json:"categories"`
+}
+
+// Tag - тег подписок и число подписок с ним
+type Tag struct {
+	ID            uuid.UUID `json:"id" format:"uuid"`
+	Name          string    `json:"name" example:"family"`
+	Subscriptions int       `json:"subscriptions" example:"3"`
+}
+
+// TagRequest - структура для создания и переименования тега
+type TagRequest struct {
+	Name string `json:"name" binding:"required" example:"family"`
+}
+
+// SubscriptionTagsRequest - новый набор тегов подписки, пустой массив снимает все теги
+type SubscriptionTagsRequest struct {
+	Tags []string `json:"tags" binding:"required" example:"family,work"`
+}
+
+// TagsListResponse - ответ со списком тегов
+type TagsListResponse struct {
+	Tags []Tag `json:"tags"`
+}
+
+// Budget - месячный бюджет пользователя на все подписки или на категорию (вместе с дочерними)
+type Budget struct {
+	ID         uuid.UUID  `json:"id" format:"uuid"`
+	UserID     uuid.UUID  `json:"user_id" format:"uuid"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty" format:"uuid"`
+	Currency   string     `json:"currency" example:"RUB"`
+	Amount     int        `json:"amount" example:"3000"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// BudgetCreateRequest - структура для создания бюджета
+type BudgetCreateRequest struct {
+	UserID     uuid.UUID  `json:"user_id" binding:"required" format:"uuid"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty" format:"uuid" description:"Без категории бюджет охватывает все подписки пользователя"`
+	Currency   string     `json:"currency,omitempty" binding:"omitempty,iso4217" example:"RUB" description:"По умолчанию валюта пользователя"`
+	Amount     int        `json:"amount" binding:"required,min=1" example:"3000"`
+}
+
+// BudgetUpdateRequest - структура для обновления бюджета, пустые поля не меняются
+type BudgetUpdateRequest struct {
+	Currency string `json:"currency,omitempty" binding:"omitempty,iso4217" example:"RUB"`
+	Amount   int    `json:"amount,omitempty" binding:"omitempty,min=1" example:"5000"`
+}
+
+// BudgetsListResponse - ответ со списком бюджетов
+type BudgetsListResponse struct {
+	Budgets []Budget `json:"budgets"`
+}
+
+// BudgetStatus - расход по бюджету за месяц.
+// Spent - стоимость подписок, действующих в этом месяце, Projected - в следующем
+type BudgetStatus struct {
+	Budget    Budget `json:"budget"`
+	Month     string `json:"month" example:"2025-07"`
+	Spent     int    `json:"spent" example:"2600"`
+	Projected int    `json:"projected" example:"3100"`
+	// Percent - Spent в процентах от Amount
+	Percent int `json:"percent" example:"86"`
+	// Threshold - наибольший достигнутый порог (80 или 100), 0 - ни один
+	Threshold int           `json:"threshold" example:"80"`
+	Alerts    []BudgetAlert `json:"alerts"`
+}
+
+// BudgetAlert - событие пересечения порога бюджета
+type BudgetAlert struct {
+	BudgetID   uuid.UUID  `json:"budget_id" format:"uuid"`
+	UserID     uuid.UUID  `json:"user_id" format:"uuid"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty" format:"uuid"`
+	Month      string     `json:"month" example:"2025-07"`
+	Threshold  int        `json:"threshold" example:"80"`
+	Spent      int        `json:"spent" example:"2600"`
+	Amount     int        `json:"amount" example:"3000"`
+	Currency   string     `json:"currency" example:"RUB"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// виды напоминаний
+const (
+	ReminderExpiring    = "expiring"
+	ReminderTrialEnding = "trial_ending"
+)
+
+// Reminder - подписка, о скором окончании которой (или ее пробного периода) пора напомнить.
+// EndDate - end_date подписки или trial_ends_at для ReminderTrialEnding; период действует
+// до конца этого месяца, ExpiresAt - первый день после окончания
+type Reminder struct {
+	Kind           string
+	SubscriptionID uuid.UUID
+	UserID         uuid.UUID
+	Email          *string
+	ServiceName    string
+	Price          int
+	EndDate        time.Time
+	ExpiresAt      time.Time
+	AutoRenew      bool
+	RenewalMonths  int
+	// WindowDays - окно напоминания (за сколько дней), по которому оно отправляется
+	WindowDays int
+}
+
+// Renewal - продление подписки на очередной срок
+type Renewal struct {
+	ID              uuid.UUID `json:"id" format:"uuid"`
+	SubscriptionID  uuid.UUID `json:"subscription_id" format:"uuid"`
+	UserID          uuid.UUID `json:"user_id" format:"uuid"`
+	Email           *string   `json:"-"`
+	ServiceName     string    `json:"service_name" example:"Netflix"`
+	Price           int       `json:"price" example:"500"`
+	PreviousEndDate string    `json:"previous_end_date" example:"2026-07"`
+	NewEndDate      string    `json:"new_end_date" example:"2027-07"`
+	RenewedAt       time.Time `json:"renewed_at"`
+}
+
+// RenewalsListResponse - ответ со списком продлений подписки
+type RenewalsListResponse struct {
+	Renewals []Renewal `json:"renewals"`
+}
+
+// SubscriptionPrice - период цены подписки: Price действует с месяца EffectiveFrom до начала следующего периода
+type SubscriptionPrice struct {
+	EffectiveFrom string    `json:"effective_from" example:"2025-09"`
+	Price         int       `json:"price" example:"600"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// PricesListResponse - ответ с историей цены подписки
+type PricesListResponse struct {
+	Prices []SubscriptionPrice `json:"prices"`
+}
+
+// Pause - пауза подписки: с StartMonth по EndMonth включительно подписка не оплачивается
+type Pause struct {
+	ID         uuid.UUID `json:"id" format:"uuid"`
+	StartMonth string    `json:"start_month" example:"2025-09"`
+	// EndMonth пустой - пауза до возобновления
+	EndMonth  string    `json:"end_month,omitempty" example:"2025-11"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PauseRequest - запрос на паузу. Без from пауза начинается с текущего месяца, без until длится до возобновления
+type PauseRequest struct {
+	From  string `json:"from,omitempty" example:"2025-09"`
+	Until string `json:"until,omitempty" example:"2025-11"`
+}
+
+// ResumeRequest - запрос на возобновление, from - первый оплачиваемый месяц (по умолчанию текущий)
+type ResumeRequest struct {
+	From string `json:"from,omitempty" example:"2025-10"`
+}
+
+// PausesListResponse - ответ со списком пауз подписки
+type PausesListResponse struct {
+	Pauses []Pause `json:"pauses"`
+}
+
+// статусы подписки
+const (
+	StatusTrial     = "trial"
+	StatusActive    = "active"
+	StatusPaused    = "paused"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+// CancelRequest - запрос на отмену подписки
+type CancelRequest struct {
+	Reason string `json:"reason,omitempty" example:"too expensive"`
+	// EffectiveDate - последний оплачиваемый месяц YYYY-MM, по умолчанию текущий
+	EffectiveDate string `json:"effective_date,omitempty" example:"2025-09"`
+}
+
+// ReactivateRequest - запрос на возобновление отмененной или истекшей подписки
+type ReactivateRequest struct {
+	// EndDate - новый месяц окончания YYYY-MM; без него сохраняется end_date, если он еще не прошел, иначе обязателен
+	EndDate string `json:"end_date,omitempty" example:"2026-09"`
+}
+
+// StatusChange - смена статуса подписки заданием-чистильщиком
+type StatusChange struct {
+	SubscriptionID uuid.UUID `json:"subscription_id" format:"uuid"`
+	UserID         uuid.UUID `json:"user_id" format:"uuid"`
+	Email          *string   `json:"-"`
+	ServiceName    string    `json:"service_name"`
+	From           string    `json:"from" example:"active"`
+	To             string    `json:"to" example:"expired"`
+	EndDate        string    `json:"end_date,omitempty" example:"2025-09"`
+}
+
+// Member - участник общей подписки. Задан ровно один из Weight и Amount
+type Member struct {
+	UserID uuid.UUID `json:"user_id" format:"uuid"`
+	// Weight - вес в делении остатка стоимости после фиксированных сумм
+	Weight *int `json:"weight,omitempty" example:"1"`
+	// Amount - фиксированная сумма в месяц
+	Amount    *int      `json:"amount,omitempty" example:"150"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MemberRequest - доля участника: положительным должно быть ровно одно поле
+type MemberRequest struct {
+	Weight int `json:"weight,omitempty" binding:"omitempty,min=1" example:"1"`
+	Amount int `json:"amount,omitempty" binding:"omitempty,min=1" example:"150"`
+}
+
+// MembersListResponse - ответ со списком участников подписки
+type MembersListResponse struct {
+	Members []Member `json:"members"`
+}
+
+// TimeSeriesResponse - стоимость и число действующих подписок по месяцам
+type TimeSeriesResponse struct {
+	From    string            `json:"from" example:"2025-01"`
+	To      string            `json:"to" example:"2025-12"`
+	GroupBy string            `json:"group_by,omitempty" example:"category"`
+	Points  []TimeSeriesPoint `json:"points"`
+}
+
+// TimeSeriesPoint - один месяц временного ряда
+type TimeSeriesPoint struct {
+	Month  string `json:"month" example:"2025-01"`
+	Total  int    `json:"total" example:"1500"`
+	Active int    `json:"active" example:"3"`
+	// Groups заполняется только при group_by
+	Groups []TimeSeriesGroup `json:"groups,omitempty"`
+}
+
+// TimeSeriesGroup - месяц одной группы. Key - ID сервиса, категории или пользователя,
+// пустой для подписок без сервиса из каталога или без категории
+type TimeSeriesGroup struct {
+	Key    string `json:"key,omitempty" example:"550e8400-e29b-41d4-a716-446655440300"`
+	Name   string `json:"name" example:"streaming"`
+	Total  int    `json:"total" example:"1000"`
+	Active int    `json:"active" example:"2"`
+}
+
+// режимы продления в прогнозе расходов
+const (
+	// ForecastRenewalAuto - продлеваются подписки с auto_renew
+	ForecastRenewalAuto = "auto"
+	// ForecastRenewalAll - продлеваются все неотмененные подписки
+	ForecastRenewalAll = "all"
+	// ForecastRenewalNone - подписки заканчиваются в end_date
+	ForecastRenewalNone = "none"
+)
+
+// ForecastResponse - прогноз расходов на месяцы From..To
+type ForecastResponse struct {
+	From          string                 `json:"from" example:"2025-10"`
+	To            string                 `json:"to" example:"2026-09"`
+	Renewal       string                 `json:"renewal" example:"auto"`
+	Total         int                    `json:"total" example:"18000"`
+	Months        []ForecastMonth        `json:"months"`
+	Subscriptions []ForecastSubscription `json:"subscriptions"`
+}
+
+// ForecastMonth - прогноз расходов на один месяц
+type ForecastMonth struct {
+	Month         string `json:"month" example:"2025-10"`
+	Total         int    `json:"total" example:"1500"`
+	Subscriptions int    `json:"subscriptions" example:"3"`
+}
+
+// ForecastSubscription - вклад подписки в прогноз
+type ForecastSubscription struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	ServiceName string    `json:"service_name" example:"Netflix"`
+	UserID      uuid.UUID `json:"user_id" format:"uuid"`
+	EndDate     string    `json:"end_date,omitempty" example:"2026-03"`
+	// Renews - подписка продлевается в пределах прогноза, RenewalDates - месяцы начала новых сроков
+	Renews       bool     `json:"renews"`
+	RenewalDates []string `json:"renewal_dates,omitempty" example:"2026-04"`
+	Total        int      `json:"total" example:"6000"`
+	// Months - сколько месяцев прогноза подписка действует
+	Months int `json:"months" example:"12"`
+}
+
+// политики пересечения подписок одного пользователя на один сервис
+const (
+	// ConflictPolicyWarn - запись выполняется, пересечения возвращаются в conflicts
+	ConflictPolicyWarn = "warn"
+	// ConflictPolicyReject - запись отклоняется с ErrSubscriptionOverlap
+	ConflictPolicyReject = "reject"
+)
+
+// Overlap - подписка того же пользователя на тот же сервис, период которой пересекается с периодом подписки
+type Overlap struct {
+	SubscriptionID uuid.UUID `json:"subscription_id" format:"uuid"`
+	ServiceName    string    `json:"service_name" example:"Netflix"`
+	StartDate      string    `json:"start_date" example:"2025-01"`
+	EndDate        string    `json:"end_date,omitempty" example:"2025-12"`
+	// From..To - общие месяцы, пустой To - пересечение не ограничено
+	From string `json:"from" example:"2025-07"`
+	To   string `json:"to,omitempty" example:"2025-12"`
+}
+
+// OverlapPair - пара пересекающихся подписок в отчете
+type OverlapPair struct {
+	UserID         uuid.UUID `json:"user_id" format:"uuid"`
+	ServiceName    string    `json:"service_name" example:"Netflix"`
+	SubscriptionID uuid.UUID `json:"subscription_id" format:"uuid"`
+	OtherID        uuid.UUID `json:"other_id" format:"uuid"`
+	From           string    `json:"from" example:"2025-07"`
+	To             string    `json:"to,omitempty" example:"2025-12"`
+}
+
+// OverlapsReportResponse - отчет о пересекающихся подписках
+type OverlapsReportResponse struct {
+	Overlaps []OverlapPair `json:"overlaps"`
+}
+
+// ServiceNamesResponse - подсказки названий сервисов для поиска
+type ServiceNamesResponse struct {
+	ServiceNames []string `json:"service_names"`
+}
+
+// AggregateColumn - столбец таблицы агрегации. Type - string, uuid, month (YYYY-MM), integer или number
+type AggregateColumn struct {
+	Name string `json:"name" example:"service_name"`
+	Type string `json:"type" example:"string"`
+}
+
+// AggregateResponse - таблица агрегации: сначала столбцы группировки, затем метрики.
+// Значения строк идут в порядке Columns, null - подписки без категории
+type AggregateResponse struct {
+	Columns []AggregateColumn `json:"columns"`
+	Rows    [][]any           `json:"rows" swaggertype:"array,object"`
 }
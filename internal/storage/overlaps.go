@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+// overlapCondition - подписка o того же пользователя, что и s, на тот же сервис (по каталогу или по названию
+// без учета регистра) с пересекающимся периодом; подписка без end_date бессрочна
+const overlapCondition = `o.user_id = s.user_id AND o.id <> s.id
+	AND (o.service_id = s.service_id OR lower(btrim(o.service_name)) = lower(btrim(s.service_name)))
+	AND o.start_date <= COALESCE(s.end_date, 'infinity') AND s.start_date <= COALESCE(o.end_date, 'infinity')`
+
+// conflictPolicy проверяет политику пересечений, пустая - ConflictPolicyWarn
+func conflictPolicy(policy string) (string, error) {
+	switch policy {
+	case "":
+		return ConflictPolicyWarn, nil
+	case ConflictPolicyWarn, ConflictPolicyReject:
+		return policy, nil
+	}
+
+	return "", myerrors.ErrInvalidConflictPolicy
+}
+
+// checkOverlaps вызывается в транзакции записи подписки: при ConflictPolicyReject пересечение
+// с другой подпиской отменяет запись
+func checkOverlaps(ctx context.Context, tx pgx.Tx, id uuid.UUID, policy string) error {
+	if policy != ConflictPolicyReject {
+		return nil
+	}
+
+	// блокировка владельца упорядочивает параллельные записи: вторая транзакция увидит подписку,
+	// записанную первой, после ее фиксации
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM users
+	WHERE id = (SELECT user_id FROM subscriptions WHERE id = $1)
+	FOR UPDATE`, id); err != nil {
+		return err
+	}
+
+	var overlaps bool
+	err := tx.QueryRow(ctx, `SELECT EXISTS (
+		SELECT 1 FROM subscriptions s JOIN subscriptions o ON `+overlapCondition+`
+		WHERE s.id = $1)`, id).Scan(&overlaps)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return myerrors.ErrSubscriptionOverlap
+	}
+
+	return nil
+}
+
+// SubscriptionOverlaps возвращает подписки того же пользователя на тот же сервис, пересекающиеся с подпиской id
+func (s *Storage) SubscriptionOverlaps(id uuid.UUID) ([]Overlap, error) {
+	const op = "storage.overlaps.SubscriptionOverlaps"
+
+	rows, err := s.db.Query(context.Background(), `SELECT o.id, o.service_name, o.start_date, o.end_date,
+		GREATEST(s.start_date, o.start_date), LEAST(s.end_date, o.end_date)
+	FROM subscriptions s JOIN subscriptions o ON `+overlapCondition+`
+	WHERE s.id = $1
+	ORDER BY o.start_date, o.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	overlaps := []Overlap{}
+	for rows.Next() {
+		var o Overlap
+		var start, from time.Time
+		var end, to *time.Time
+		if err := rows.Scan(&o.SubscriptionID, &o.ServiceName, &start, &end, &from, &to); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		o.StartDate = start.Format(DateLayout)
+		o.EndDate = formatMonth(end)
+		o.From = from.Format(DateLayout)
+		o.To = formatMonth(to)
+		overlaps = append(overlaps, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return overlaps, nil
+}
+
+// OverlapsReport возвращает все пары пересекающихся подписок, с userID - только подписки этого владельца
+func (s *Storage) OverlapsReport(userID string) ([]OverlapPair, error) {
+	const op = "storage.overlaps.OverlapsReport"
+
+	query := `SELECT s.user_id, s.service_name, s.id, o.id,
+		GREATEST(s.start_date, o.start_date), LEAST(s.end_date, o.end_date)
+	FROM subscriptions s JOIN subscriptions o ON ` + overlapCondition + ` AND s.id < o.id`
+	var args []any
+	if userID != "" {
+		if _, err := uuid.Parse(userID); err != nil {
+			return nil, fmt.Errorf("%s: failed parse id: %w", op, invalidRequest("user_id", err))
+		}
+		query += ` WHERE s.user_id = $1`
+		args = append(args, userID)
+	}
+	query += ` ORDER BY s.user_id, lower(s.service_name), 5, s.id, o.id`
+
+	rows, err := s.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	pairs := []OverlapPair{}
+	for rows.Next() {
+		var p OverlapPair
+		var from time.Time
+		var to *time.Time
+		if err := rows.Scan(&p.UserID, &p.ServiceName, &p.SubscriptionID, &p.OtherID, &from, &to); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		p.From = from.Format(DateLayout)
+		p.To = formatMonth(to)
+		pairs = append(pairs, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return pairs, nil
+}
+
+// formatMonth форматирует необязательный месяц, nil - пустая строка
+func formatMonth(month *time.Time) string {
+	if month == nil {
+		return ""
+	}
+
+	return month.Format(DateLayout)
+}
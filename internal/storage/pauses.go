@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// parseMonthOr разбирает месяц YYYY-MM, пустая строка - def
+func parseMonthOr(value string, def time.Time, field string) (time.Time, error) {
+	if value == "" {
+		return def, nil
+	}
+
+	month, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return time.Time{}, invalidRequest(field, err)
+	}
+
+	return month, nil
+}
+
+// PauseSubscription ставит подписку на паузу с месяца from по until включительно.
+// Пауза должна лежать в пределах start_date..end_date и не пересекаться с другими паузами
+func (s *Storage) PauseSubscription(id uuid.UUID, req PauseRequest) ([]Pause, error) {
+	const op = "storage.pauses.PauseSubscription"
+
+	err := s.command(id, "", func(ctx context.Context, tx pgx.Tx, strm *stream) error {
+		month, err := streamMonth(ctx, tx, strm)
+		if err != nil {
+			return err
+		}
+		return decidePause(strm, req, month)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.checkSubscriptionBudgets(id)
+
+	return s.ListPauses(id)
+}
+
+// ResumeSubscription возобновляет подписку с месяца from: пауза, в которую попадает from, заканчивается
+// месяцем раньше. Пауза, которая начинается с from, удаляется целиком
+func (s *Storage) ResumeSubscription(id uuid.UUID, req ResumeRequest) ([]Pause, error) {
+	const op = "storage.pauses.ResumeSubscription"
+
+	err := s.command(id, "", func(ctx context.Context, tx pgx.Tx, strm *stream) error {
+		month, err := streamMonth(ctx, tx, strm)
+		if err != nil {
+			return err
+		}
+		return decideResume(strm, req, month)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.checkSubscriptionBudgets(id)
+
+	return s.ListPauses(id)
+}
+
+// ListPauses возвращает паузы подписки по возрастанию start_month
+func (s *Storage) ListPauses(subscriptionID uuid.UUID) ([]Pause, error) {
+	const op = "storage.pauses.ListPauses"
+
+	if _, err := s.GetSubscription(subscriptionID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.Query(context.Background(), `SELECT id, start_month, end_month, created_at
+	FROM subscription_pauses
+	WHERE subscription_id = $1
+	ORDER BY start_month`, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	pauses := []Pause{}
+	for rows.Next() {
+		var p Pause
+		var start time.Time
+		var end *time.Time
+		if err := rows.Scan(&p.ID, &start, &end, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		p.StartMonth = start.Format(DateLayout)
+		if end != nil {
+			p.EndMonth = end.Format(DateLayout)
+		}
+		pauses = append(pauses, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return pauses, nil
+}
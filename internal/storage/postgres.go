@@ -9,6 +9,7 @@ import (
 	"time"
 	"github.com/jackc/pgx/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/odlev/subscriptions/internal/config"
 	"github.com/odlev/subscriptions/pkg/myerrors"
@@ -16,16 +17,52 @@ import (
 
 const DateLayout = "2006-01"
 
+// DefaultRenewalMonths - срок автопродления, если он не указан
+const DefaultRenewalMonths = 12
+
+// maxRenewalMonths - наибольший срок автопродления (10 лет)
+const maxRenewalMonths = 120
+
+// subscriptionColumns - порядок колонок для scanSubscription. price - цена, действующая в текущем месяце.
+// Теги выбираются подзапросом, поэтому таблица subscriptions в запросе не должна иметь алиаса
+const subscriptionColumns = `id, service_name, subscription_price_at(id, date_trunc('month', NOW())::DATE) AS price, user_id, start_date, end_date, service_id, category_id,
+	auto_renew, renewal_months, trial_ends_at, intro_price, intro_months, status, cancel_reason, cancelled_at,
+	ARRAY(SELECT t.name FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+		WHERE st.subscription_id = subscriptions.id ORDER BY t.name) AS tags`
+
+func scanSubscription(row pgx.Row) (*Subscription, error) {
+	var sub Subscription
+	if err := row.Scan(&sub.ID, &sub.ServiceName, &sub.Price, &sub.UserID, &sub.StartDate, &sub.EndDate, &sub.ServiceID, &sub.CategoryID,
+		&sub.AutoRenew, &sub.RenewalMonths, &sub.TrialEndsAt, &sub.IntroPrice, &sub.IntroMonths,
+		&sub.Status, &sub.CancelReason, &sub.CancelledAt, &sub.Tags); err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+// dbtx - общее у пула соединений и транзакции. Storage, привязанный к транзакции, выполняет все запросы в ней,
+// а pgx.BeginFunc открывает вложенные транзакции как точки сохранения
+type dbtx interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 type Storage struct {
-	db *pgxpool.Pool
+	db  dbtx
+	log *slog.Logger
+
+	budgetAlertHandlers []BudgetAlertHandler
+	// journal - события подписок дописываются в журнал subscription_events, см. EventStore
+	journal bool
 }
 
 func InitPostgres(log *slog.Logger, cfg config.Config) (*Storage, error) {
 	const op = "storage.postgres.InitPostgres"
 
-	dsn := fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, cfg.SSLMode)
+	dsn := cfg.DSN()
 
 	var db *pgxpool.Pool
 	var err error
@@ -43,65 +80,86 @@ func InitPostgres(log *slog.Logger, cfg config.Config) (*Storage, error) {
 		return nil, fmt.Errorf("%s: ping failed: %w", op, err)
 	}
 
-	_, err = db.Exec(context.Background(), `CREATE TABLE IF NOT EXISTS subscriptions (
-	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-	service_name TEXT NOT NULL,
-	price DECIMAL NOT NULL CHECK (price > 0),
-	user_id UUID NOT NULL DEFAULT uuid_generate_v4(),
-	start_date DATE NOT NULL,
-	end_date DATE,
-	updated_at TIMESTAMPTZ DEFAULT NOW());
-	CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`)
-	if err != nil {
-		return nil, fmt.Errorf("%s: error creating table: %w", op, err)
+	if err := migrate(context.Background(), log, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	log.Info("Connect with PostgreSQL established successfully")
-	return &Storage{db: db}, nil
+	return &Storage{db: db, log: log}, nil
 }
 
 func (s *Storage) CreateSubscription(sub *SubscriptionR) (uuid.UUID, error) {
 	const op = "storage.postgres.NewSubscription"
 
-	var endDate time.Time 
-	startDate, err := time.Parse(DateLayout, sub.StartDate)
+	startDate, endDate, err := parseDates(UpdateSubscriptionRequest{StartDate: sub.StartDate, EndDate: sub.EndDate})
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("%s: time parse error: %w", op, err)
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
 	}
-	if sub.EndDate == "" {
-		endDate = startDate.AddDate(1, 0, 0)
-	} else {
-		endDate, err = time.Parse(DateLayout, sub.EndDate)
-		if err != nil {
-			return uuid.Nil, fmt.Errorf("%s: time parse error: %w", op, err)
-		}
+	if startDate == nil {
+		return uuid.Nil, fmt.Errorf("%s: %w: start_date is required", op, myerrors.ErrInvalidRequest)
 	}
-	if endDate.Before(startDate) {
-		return uuid.Nil, fmt.Errorf("%s: %w", op, myerrors.ErrInvalidDateRange)
+
+	// пользователь должен существовать заранее, см. CreateUser
+	if sub.UserID == uuid.Nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, myerrors.ErrUserRequired)
 	}
 
-	var id uuid.UUID
-	// если не передан user_id - не передаем его в бд и бд создает его по дефолту
-	if sub.UserID == uuid.Nil { 
-		query := `INSERT INTO subscriptions 
-		(service_name, price, start_date, end_date)
-		values ($1, $2, $3, $4) RETURNING id`
+	serviceID, serviceName, err := s.resolveService(context.Background(), sub.ServiceID, sub.ServiceName)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+	tags, err := normalizeTags(sub.Tags)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+	renewal, err := renewalMonths(sub.RenewalMonths)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+	trial, err := parseTrial(sub.TrialEndsAt)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+	introPrice, introMonths, err := introValues(sub.IntroPrice, sub.IntroMonths)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+	policy, err := conflictPolicy(sub.ConflictPolicy)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-		err := s.db.QueryRow(context.Background(), query, sub.ServiceName, sub.Price, startDate, endDate).Scan(&id)
-		if err != nil {
-			return uuid.Nil, fmt.Errorf("%s: %w", op, err)
-		}
-	} else {
-		query := `INSERT INTO subscriptions 
-		(service_name, price, user_id, start_date, end_date)
-		values ($1, $2, $3, $4, $5) RETURNING id`
+	created := createdEvent{
+		ServiceName:   serviceName,
+		ServiceID:     serviceID,
+		Price:         sub.Price,
+		UserID:        sub.UserID,
+		StartDate:     *startDate,
+		EndDate:       endDate,
+		CategoryID:    sub.CategoryID,
+		Tags:          tags,
+		AutoRenew:     sub.AutoRenew,
+		RenewalMonths: renewal,
+		TrialEndsAt:   trial,
+		IntroPrice:    introPrice,
+		IntroMonths:   introMonths,
+	}
 
-		err := s.db.QueryRow(context.Background(), query, sub.ServiceName, sub.Price, sub.UserID, startDate, endDate).Scan(&id)
+	id := uuid.New()
+	err = s.command(id, policy, func(ctx context.Context, tx pgx.Tx, strm *stream) error {
+		month, err := ownerMonth(ctx, tx, sub.UserID)
 		if err != nil {
-			return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+			return err
 		}
+		return decideCreate(strm, created, month)
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
 	}
-	
+
+	s.checkBudgets(sub.UserID)
+
 	return id, nil
 }
 
@@ -109,15 +167,10 @@ func (s *Storage) CreateSubscription(sub *SubscriptionR) (uuid.UUID, error) {
 func (s *Storage) GetSubscription(id uuid.UUID) (*Subscription, error){
 	const op = "storage.postgres.GetSubscriptionByID"
 
-	query := `SELECT id, service_name, price, user_id, start_date, end_date FROM subscriptions
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions
 	WHERE id = $1`
 
-	var sub Subscription
-	
-
-	err := s.db.QueryRow(context.Background(), query, id).Scan(
-		&sub.ID, &sub.ServiceName, &sub.Price, &sub.UserID, &sub.StartDate, &sub.EndDate,
-	)
+	sub, err := scanSubscription(s.db.QueryRow(context.Background(), query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrNotFound)
@@ -125,57 +178,197 @@ func (s *Storage) GetSubscription(id uuid.UUID) (*Subscription, error){
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &sub, nil
+	return sub, nil
 }
 
 func (s *Storage) DeleteSubscription(id uuid.UUID) (string, error) {
 	const op = "storage.postgres.DeleteSusbcription"
 
-	var serviceName string 
+	var serviceName string
+	err := s.command(id, "", func(_ context.Context, _ pgx.Tx, strm *stream) error {
+		if strm.state == nil {
+			return myerrors.ErrNotFound
+		}
+		serviceName = strm.state.ServiceName
+		return strm.emit(deletedEvent{})
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return serviceName, nil
+}
 
-	queryGetName := `SELECT service_name FROM subscriptions WHERE id = $1;`
+func (s *Storage) UpdateSubscription(id uuid.UUID, req UpdateSubscriptionRequest) error {
+	const op = "storage.postgres.UpdateSubscription"
 
-	err := s.db.QueryRow(context.Background(), queryGetName, id).Scan(&serviceName)
+	startDate, endDate, err := parseDates(req)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return "", fmt.Errorf("%s: %w", op, myerrors.ErrNotFound)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	change := subscriptionChange{startDate: startDate, endDate: endDate}
+
+	// сервис меняется, только если передан service_name или service_id
+	if req.ServiceName != "" || req.ServiceID != nil {
+		serviceID, serviceName, err := s.resolveService(context.Background(), req.ServiceID, req.ServiceName)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		change.service = &serviceChangedEvent{ServiceName: serviceName, ServiceID: serviceID}
+	}
+
+	if req.RenewalMonths != 0 {
+		if _, err := renewalMonths(req.RenewalMonths); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	change.autoRenew, change.renewalMonths = req.AutoRenew, req.RenewalMonths
+
+	if req.PriceEffectiveFrom != "" {
+		parsed, err := time.Parse(DateLayout, req.PriceEffectiveFrom)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, invalidRequest("price_effective_from", err))
 		}
-		return "", fmt.Errorf("%s: failed to delete: %w", op, err)
+		change.priceFrom = &parsed
+	}
+	if req.Price != 0 {
+		change.price = &req.Price
 	}
 
-	query := `DELETE FROM subscriptions WHERE id = $1;`
+	// пустая строка убирает пробный период
+	if req.TrialEndsAt != nil {
+		change.trialSet = true
+		if change.trial, err = parseTrial(*req.TrialEndsAt); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if (req.IntroPrice != nil && *req.IntroPrice < 0) || (req.IntroMonths != nil && *req.IntroMonths < 0) {
+		return fmt.Errorf("%s: %w", op, myerrors.ErrInvalidIntro)
+	}
+	change.introPrice, change.introMonths = req.IntroPrice, req.IntroMonths
 
-	_, err = s.db.Exec(context.Background(), query, id)
+	policy, err := conflictPolicy(req.ConflictPolicy)
 	if err != nil {
-		return "", fmt.Errorf("%s, %w", op, err)
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	return serviceName, nil
+
+	// нулевой UUID снимает категорию
+	if req.CategoryID != nil {
+		change.categorySet = true
+		if *req.CategoryID != uuid.Nil {
+			change.categoryID = req.CategoryID
+		}
+	}
+
+	err = s.command(id, policy, func(ctx context.Context, tx pgx.Tx, strm *stream) error {
+		month, err := streamMonth(ctx, tx, strm)
+		if err != nil {
+			return err
+		}
+		return decideChange(strm, change, month)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.checkSubscriptionBudgets(id)
+
+	return nil
 }
 
-func (s *Storage) UpdateSubscription(id uuid.UUID, req UpdateSubscriptionRequest) error {
-	const op = "storage.postgres.UpdateSubscription"
+// ReplaceSubscription полностью перезаписывает подписку (семантика PUT).
+// Если user_id не передан, остается прежний владелец, если не переданы теги - прежние теги
+func (s *Storage) ReplaceSubscription(id uuid.UUID, sub *SubscriptionR) error {
+	const op = "storage.postgres.ReplaceSubscription"
 
-	startDate, endDate, err := parseDates(req)
+	startDate, endDate, err := parseDates(UpdateSubscriptionRequest{StartDate: sub.StartDate, EndDate: sub.EndDate})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	
-	query := `UPDATE subscriptions SET 
-		service_name = COALESCE($1, service_name),
-		price = COALESCE($2, price),
-		start_date = COALESCE($3, start_date),
-		end_date = COALESCE($4, end_date),
-		updated_at = NOW()
-	WHERE id = $5;`	
-	
-	_, err = s.db.Exec(context.Background(), query, req.ServiceName, req.Price, startDate, endDate, id)
+	if startDate == nil {
+		return fmt.Errorf("%s: %w: start_date is required", op, myerrors.ErrInvalidRequest)
+	}
+	serviceID, serviceName, err := s.resolveService(context.Background(), sub.ServiceID, sub.ServiceName)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	tags, err := normalizeTags(sub.Tags)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	renewal, err := renewalMonths(sub.RenewalMonths)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	trial, err := parseTrial(sub.TrialEndsAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, _, err := introValues(sub.IntroPrice, sub.IntroMonths); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	policy, err := conflictPolicy(sub.ConflictPolicy)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	// нулевая вступительная цена убирает ее, как в UpdateSubscription
+	change := subscriptionChange{
+		startDate:     startDate,
+		endDate:       endDate,
+		service:       &serviceChangedEvent{ServiceName: serviceName, ServiceID: serviceID},
+		categorySet:   true,
+		categoryID:    sub.CategoryID,
+		autoRenew:     &sub.AutoRenew,
+		renewalMonths: renewal,
+		trialSet:      true,
+		trial:         trial,
+		introPrice:    &sub.IntroPrice,
+		introMonths:   &sub.IntroMonths,
+		tagsSet:       sub.Tags != nil,
+		tags:          tags,
+		price:         &sub.Price,
+	}
+	if sub.UserID != uuid.Nil {
+		change.owner = &sub.UserID
+	}
+
+	err = s.command(id, policy, func(ctx context.Context, tx pgx.Tx, strm *stream) error {
+		if strm.state == nil {
+			return myerrors.ErrNotFound
+		}
+		// цена и статус считаются по текущему месяцу нового владельца
+		owner := strm.state.UserID
+		if change.owner != nil {
+			owner = *change.owner
+		}
+		month, err := ownerMonth(ctx, tx, owner)
+		if err != nil {
+			return err
+		}
+		return decideChange(strm, change, month)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.checkSubscriptionBudgets(id)
+
 	return nil
 }
 
+// renewalMonths проверяет срок автопродления, 0 - срок по умолчанию
+func renewalMonths(months int) (int, error) {
+	if months == 0 {
+		return DefaultRenewalMonths, nil
+	}
+	if months < 1 || months > maxRenewalMonths {
+		return 0, myerrors.ErrInvalidRenewalTerm
+	}
+
+	return months, nil
+}
+
 func parseDates(req UpdateSubscriptionRequest) (*time.Time, *time.Time, error) {
     const op = "storage.postgres.parseDates"
 
@@ -184,7 +377,7 @@ func parseDates(req UpdateSubscriptionRequest) (*time.Time, *time.Time, error) {
     if req.StartDate != "" {
         parsed, err := time.Parse(DateLayout, req.StartDate)
         if err != nil {
-            return nil, nil, fmt.Errorf("%s: invalid start date: %w", op, err)
+            return nil, nil, fmt.Errorf("%s: %w", op, invalidRequest("start_date", err))
         }
         startDate = &parsed
     }
@@ -192,7 +385,7 @@ func parseDates(req UpdateSubscriptionRequest) (*time.Time, *time.Time, error) {
     if req.EndDate != "" {
         parsed, err := time.Parse(DateLayout, req.EndDate)
         if err != nil {
-            return nil, nil, fmt.Errorf("%s: invalid end date: %w", op, err)
+            return nil, nil, fmt.Errorf("%s: %w", op, invalidRequest("end_date", err))
         }
         endDate = &parsed
     } else if req.StartDate != "" && req.EndDate == "" {
@@ -208,26 +401,27 @@ func parseDates(req UpdateSubscriptionRequest) (*time.Time, *time.Time, error) {
     return startDate, endDate, nil
 }
 
-func (s *Storage) GetListSubscriptions(userID, name string) ([]Subscription, error) {
+func (s *Storage) GetListSubscriptions(filter SubscriptionFilter) ([]Subscription, error) {
 	const op = "storage.postgres.GetAllSubscriptions"
 
-	query := `SELECT id, service_name, price, user_id, start_date, end_date
+	if filter.AsOf != "" {
+		return s.listSubscriptionsAsOf(filter)
+	}
+
+	query := `SELECT ` + subscriptionColumns + `
 	FROM subscriptions WHERE 1 = 1`
 
-	args := []any{}
+	conds, args, err := filter.where("subscriptions.", nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	query = query + conds
 
-	if userID != "" {
-		if _, err := uuid.Parse(userID); err != nil {
-			return nil, fmt.Errorf("%s: failed parse id: %w", op, err)
-		}
-		args = append(args, userID)
-		query = query + fmt.Sprintf(" AND user_id = $%d", len(args))
+	// с поиском q сначала идут лучшие совпадения
+	rank, args := filter.searchRank("subscriptions.", args)
 
-	}
-	if name != "" {
-		args = append(args, name)
-		query = query + fmt.Sprintf(" AND service_name = $%d", len(args))
-	}
+	// стабильный порядок нужен для постраничной выдачи: без q страницы читаются по индексу (start_date, id)
+	query = query + " ORDER BY " + rank + "start_date, id" + filter.limitClause()
 
 	rows, err := s.db.Query(context.Background(), query, args...)
 	if err != nil {
@@ -238,20 +432,13 @@ func (s *Storage) GetListSubscriptions(userID, name string) ([]Subscription, err
 
 	var subs []Subscription
 	
-	var sub Subscription
 	for rows.Next() {
-		if err := rows.Scan(&sub.ID, &sub.ServiceName, &sub.Price, &sub.UserID, &sub.StartDate, &sub.EndDate); err != nil {
+		sub, err := scanSubscription(rows)
+		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
-		subs = append(subs, Subscription{
-			ID: sub.ID,
-			ServiceName: sub.ServiceName,
-			Price: sub.Price,
-			UserID: sub.UserID,
-			StartDate: sub.StartDate,
-			EndDate: sub.EndDate,
-		})
+		subs = append(subs, *sub)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -261,3 +448,65 @@ func (s *Storage) GetListSubscriptions(userID, name string) ([]Subscription, err
 	return subs, nil
 
 }
+
+// GroupByCategory - группировка стоимости по категории подписки
+const GroupByCategory = "category"
+
+// GetTotalCost считает суммарную стоимость подписок за период [from, to] (оба в формате YYYY-MM, включительно).
+// Каждая подписка дает price за каждый месяц пересечения своего периода с запрошенным.
+// С фильтром по пользователю общие подписки учитываются только его долей
+// groupBy пустой или GroupByCategory, во втором случае ответ содержит суммы по категориям
+func (s *Storage) GetTotalCost(filter SubscriptionFilter, from, to, groupBy string) (*TotalCostResponse, error) {
+	const op = "storage.postgres.GetTotalCost"
+
+	if groupBy != "" && groupBy != GroupByCategory {
+		return nil, fmt.Errorf("%s: %w", op, myerrors.ErrInvalidGroupBy)
+	}
+
+	fromDate, toDate, err := parsePeriod(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conds, args, err := filter.where("s.", []any{fromDate, toDate})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	cost, args := filter.costExpr("s.id", "m::DATE", args)
+
+	query := `SELECT c.id, COALESCE(c.name, ''), ROUND(COALESCE(SUM(` + cost + `), 0))::BIGINT
+	FROM subscriptions s
+	CROSS JOIN LATERAL generate_series(
+		GREATEST(s.start_date, $1::DATE),
+		LEAST(COALESCE(s.end_date, $2::DATE), $2::DATE),
+		INTERVAL '1 month') AS m
+	LEFT JOIN categories c ON c.id = s.category_id
+	WHERE 1 = 1` + conds + `
+	GROUP BY c.id, c.name
+	ORDER BY c.name NULLS LAST`
+
+	rows, err := s.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	resp := &TotalCostResponse{From: from, To: to}
+	for rows.Next() {
+		var group CostGroup
+		if err := rows.Scan(&group.CategoryID, &group.Name, &group.Total); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		resp.Total += group.Total
+		if groupBy == GroupByCategory {
+			resp.Groups = append(resp.Groups, group)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return resp, nil
+}
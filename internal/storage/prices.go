@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListPrices возвращает периоды цены подписки по возрастанию effective_from
+func (s *Storage) ListPrices(subscriptionID uuid.UUID) ([]SubscriptionPrice, error) {
+	const op = "storage.prices.ListPrices"
+
+	if _, err := s.GetSubscription(subscriptionID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.Query(context.Background(), `SELECT effective_from, price, created_at
+	FROM subscription_prices
+	WHERE subscription_id = $1
+	ORDER BY effective_from`, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	prices := []SubscriptionPrice{}
+	for rows.Next() {
+		var p SubscriptionPrice
+		var from time.Time
+		if err := rows.Scan(&from, &p.Price, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		p.EffectiveFrom = from.Format(DateLayout)
+		prices = append(prices, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,286 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+// loadState читает состояние подписки из проекции, nil - подписки нет. forUpdate блокирует строку
+// до конца транзакции. История продлений в состояние не входит: она только дополняется событиями renewed
+func loadState(ctx context.Context, tx pgx.Tx, id uuid.UUID, forUpdate bool) (*subscriptionState, error) {
+	query := `SELECT service_name, service_id, price::INT, user_id, start_date, end_date, category_id,
+		ARRAY(SELECT t.name FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+			WHERE st.subscription_id = subscriptions.id),
+		auto_renew, renewal_months, trial_ends_at, intro_price, intro_months, status, cancel_reason, cancelled_at
+	FROM subscriptions WHERE id = $1`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+
+	var st subscriptionState
+	err := tx.QueryRow(ctx, query, id).Scan(&st.ServiceName, &st.ServiceID, &st.Price, &st.UserID, &st.StartDate,
+		&st.EndDate, &st.CategoryID, &st.Tags, &st.AutoRenew, &st.RenewalMonths, &st.TrialEndsAt, &st.IntroPrice,
+		&st.IntroMonths, &st.Status, &st.CancelReason, &st.CancelledAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// порядок тегов в состоянии не должен зависеть от правил сортировки базы
+	st.Tags = sortedTags(st.Tags)
+	if st.CancelledAt != nil {
+		utc := st.CancelledAt.UTC()
+		st.CancelledAt = &utc
+	}
+
+	rows, err := tx.Query(ctx, `SELECT effective_from, price FROM subscription_prices
+	WHERE subscription_id = $1
+	ORDER BY effective_from`, id)
+	if err != nil {
+		return nil, err
+	}
+	st.Prices, err = pgx.CollectRows(rows, pgx.RowToStructByPos[statePrice])
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.Query(ctx, `SELECT id, start_month, end_month FROM subscription_pauses
+	WHERE subscription_id = $1
+	ORDER BY start_month`, id)
+	if err != nil {
+		return nil, err
+	}
+	st.Pauses, err = pgx.CollectRows(rows, pgx.RowToStructByPos[statePause])
+	if err != nil {
+		return nil, err
+	}
+
+	// порядок участников - как у compareMember: PostgreSQL сравнивает UUID побайтно
+	rows, err = tx.Query(ctx, `SELECT user_id, weight, amount FROM subscription_members
+	WHERE subscription_id = $1
+	ORDER BY user_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	st.Members, err = pgx.CollectRows(rows, pgx.RowToStructByPos[stateMember])
+	if err != nil {
+		return nil, err
+	}
+
+	return &st, nil
+}
+
+// projectionDrift сравнивает состояние подписки по журналу want с проекцией current и возвращает
+// расходящиеся поля: "deleted" - подписка удалена в журнале, но осталась в проекции,
+// "missing" - подписки нет в проекции. nil - расхождений нет
+func projectionDrift(want, current *subscriptionState) ([]string, error) {
+	switch {
+	case want == nil && current == nil:
+		return nil, nil
+	case want == nil:
+		return []string{"deleted"}, nil
+	case current == nil:
+		return []string{"missing"}, nil
+	}
+
+	return diffStates(want, current)
+}
+
+// checkProjection возвращает ErrProjectionDrift, если проекция current не совпадает с журналом подписки
+// или подписки, которая есть в проекции, нет в журнале (version 0)
+func checkProjection(want *subscriptionState, version int, current *subscriptionState) error {
+	if version == 0 && current != nil {
+		return fmt.Errorf("%w: subscription is not in the event log", myerrors.ErrProjectionDrift)
+	}
+
+	fields, err := projectionDrift(want, current)
+	if err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		return fmt.Errorf("%w: %s", myerrors.ErrProjectionDrift, strings.Join(fields, ", "))
+	}
+
+	return nil
+}
+
+// project применяет событие к проекции; st - состояние подписки после события
+func project(ctx context.Context, tx pgx.Tx, id uuid.UUID, data eventData, st *subscriptionState) error {
+	var err error
+	switch event := data.(type) {
+	case createdEvent, importedEvent:
+		err = projectState(ctx, tx, id, st)
+	case priceChangedEvent:
+		_, err = tx.Exec(ctx, `INSERT INTO subscription_prices (subscription_id, effective_from, price)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (subscription_id, effective_from) DO UPDATE SET price = EXCLUDED.price, created_at = NOW()`,
+			id, event.EffectiveFrom, event.Price)
+		if err == nil {
+			err = updateProjection(ctx, tx, id, `price = $2`, st.Price)
+		}
+	case datesChangedEvent:
+		err = updateProjection(ctx, tx, id, `start_date = $2, end_date = $3, trial_ends_at = $4`,
+			event.StartDate, event.EndDate, event.TrialEndsAt)
+	case serviceChangedEvent:
+		err = updateProjection(ctx, tx, id, `service_name = $2, service_id = $3`, event.ServiceName, event.ServiceID)
+	case categoryChangedEvent:
+		err = updateProjection(ctx, tx, id, `category_id = $2`, event.CategoryID)
+	case ownerChangedEvent:
+		err = updateProjection(ctx, tx, id, `user_id = $2`, event.UserID)
+	case renewalChangedEvent:
+		err = updateProjection(ctx, tx, id, `auto_renew = $2, renewal_months = $3`, event.AutoRenew, event.RenewalMonths)
+	case introChangedEvent:
+		err = updateProjection(ctx, tx, id, `intro_price = $2, intro_months = $3`, event.IntroPrice, event.IntroMonths)
+	case tagsChangedEvent:
+		if err = setSubscriptionTags(ctx, tx, id, event.Tags); err == nil {
+			err = updateProjection(ctx, tx, id, `updated_at = NOW()`)
+		}
+	case statusChangedEvent:
+		err = updateProjection(ctx, tx, id, `status = $2, status_changed_at = NOW()`, event.To)
+	case cancelledEvent:
+		err = updateProjection(ctx, tx, id, `end_date = $2, trial_ends_at = $3, auto_renew = FALSE,
+			status = $4, status_changed_at = NOW(), cancel_reason = $5, cancelled_at = $6`,
+			event.EndDate, st.TrialEndsAt, st.Status, event.Reason, event.CancelledAt)
+	case reactivatedEvent:
+		err = updateProjection(ctx, tx, id, `end_date = $2, cancel_reason = NULL, cancelled_at = NULL`, event.EndDate)
+	case pausedEvent:
+		_, err = tx.Exec(ctx, `INSERT INTO subscription_pauses (id, subscription_id, start_month, end_month)
+		VALUES ($1, $2, $3, $4)`, event.PauseID, id, event.StartMonth, event.EndMonth)
+	case resumedEvent:
+		if event.EndMonth == nil {
+			_, err = tx.Exec(ctx, `DELETE FROM subscription_pauses WHERE id = $1`, event.PauseID)
+		} else {
+			_, err = tx.Exec(ctx, `UPDATE subscription_pauses SET end_month = $2 WHERE id = $1`, event.PauseID, event.EndMonth)
+		}
+	case renewedEvent:
+		if err = updateProjection(ctx, tx, id, `end_date = $2`, event.NewEndDate); err == nil {
+			_, err = tx.Exec(ctx, `INSERT INTO subscription_renewals (id, subscription_id, previous_end_date, new_end_date)
+			VALUES ($1, $2, $3, $4)`, event.RenewalID, id, event.PreviousEndDate, event.NewEndDate)
+		}
+	case memberSetEvent:
+		_, err = tx.Exec(ctx, `INSERT INTO subscription_members (subscription_id, user_id, weight, amount)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id, user_id) DO UPDATE SET weight = EXCLUDED.weight, amount = EXCLUDED.amount`,
+			id, event.UserID, event.Weight, event.Amount)
+	case memberRemovedEvent:
+		_, err = tx.Exec(ctx, `DELETE FROM subscription_members WHERE subscription_id = $1 AND user_id = $2`,
+			id, event.UserID)
+	case deletedEvent:
+		_, err = tx.Exec(ctx, `DELETE FROM subscriptions WHERE id = $1`, id)
+	default:
+		err = fmt.Errorf("unknown event %T", data)
+	}
+
+	return subscriptionFKError(err)
+}
+
+// updateProjection меняет строку подписки: set - присваивания с аргументами args начиная с $2
+func updateProjection(ctx context.Context, tx pgx.Tx, id uuid.UUID, set string, args ...any) error {
+	if !strings.Contains(set, "updated_at") {
+		set += `, updated_at = NOW()`
+	}
+
+	_, err := tx.Exec(ctx, `UPDATE subscriptions SET `+set+` WHERE id = $1`, append([]any{id}, args...)...)
+
+	return err
+}
+
+// projectState записывает состояние подписки в проекцию целиком: строку, теги, периоды цен, паузы и участников
+func projectState(ctx context.Context, tx pgx.Tx, id uuid.UUID, st *subscriptionState) error {
+	_, err := tx.Exec(ctx, `INSERT INTO subscriptions (id, service_name, service_id, price, user_id, start_date,
+		end_date, category_id, auto_renew, renewal_months, trial_ends_at, intro_price, intro_months, status,
+		cancel_reason, cancelled_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	ON CONFLICT (id) DO UPDATE SET
+		service_name = EXCLUDED.service_name,
+		service_id = EXCLUDED.service_id,
+		price = EXCLUDED.price,
+		user_id = EXCLUDED.user_id,
+		start_date = EXCLUDED.start_date,
+		end_date = EXCLUDED.end_date,
+		category_id = EXCLUDED.category_id,
+		auto_renew = EXCLUDED.auto_renew,
+		renewal_months = EXCLUDED.renewal_months,
+		trial_ends_at = EXCLUDED.trial_ends_at,
+		intro_price = EXCLUDED.intro_price,
+		intro_months = EXCLUDED.intro_months,
+		status_changed_at = CASE WHEN subscriptions.status <> EXCLUDED.status
+			THEN NOW() ELSE subscriptions.status_changed_at END,
+		status = EXCLUDED.status,
+		cancel_reason = EXCLUDED.cancel_reason,
+		cancelled_at = EXCLUDED.cancelled_at,
+		updated_at = NOW()`,
+		id, st.ServiceName, st.ServiceID, st.Price, st.UserID, st.StartDate,
+		st.EndDate, st.CategoryID, st.AutoRenew, st.RenewalMonths, st.TrialEndsAt, st.IntroPrice, st.IntroMonths, st.Status,
+		st.CancelReason, st.CancelledAt)
+	if err != nil {
+		return err
+	}
+
+	if err := setSubscriptionTags(ctx, tx, id, st.Tags); err != nil {
+		return err
+	}
+
+	dates := make([]time.Time, 0, len(st.Prices))
+	prices := make([]int, 0, len(st.Prices))
+	for _, p := range st.Prices {
+		dates = append(dates, p.EffectiveFrom)
+		prices = append(prices, p.Price)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM subscription_prices WHERE subscription_id = $1`, id); err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `INSERT INTO subscription_prices (subscription_id, effective_from, price)
+	SELECT $1, p.effective_from, p.price FROM unnest($2::DATE[], $3::INT[]) AS p (effective_from, price)`,
+		id, dates, prices)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(st.Pauses))
+	starts := make([]time.Time, 0, len(st.Pauses))
+	ends := make([]*time.Time, 0, len(st.Pauses))
+	for _, p := range st.Pauses {
+		ids = append(ids, p.ID)
+		starts = append(starts, p.StartMonth)
+		ends = append(ends, p.EndMonth)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM subscription_pauses WHERE subscription_id = $1`, id); err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `INSERT INTO subscription_pauses (id, subscription_id, start_month, end_month)
+	SELECT p.id, $1, p.start_month, p.end_month
+	FROM unnest($2::UUID[], $3::DATE[], $4::DATE[]) AS p (id, start_month, end_month)`,
+		id, ids, starts, ends)
+	if err != nil {
+		return err
+	}
+
+	// участники, которые остаются, сохраняют created_at: по нему упорядочен ListMembers
+	users := make([]uuid.UUID, 0, len(st.Members))
+	weights := make([]*int, 0, len(st.Members))
+	amounts := make([]*int, 0, len(st.Members))
+	for _, m := range st.Members {
+		users = append(users, m.UserID)
+		weights = append(weights, m.Weight)
+		amounts = append(amounts, m.Amount)
+	}
+	_, err = tx.Exec(ctx, `DELETE FROM subscription_members WHERE subscription_id = $1 AND user_id <> ALL($2)`, id, users)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `INSERT INTO subscription_members (subscription_id, user_id, weight, amount)
+	SELECT $1, m.user_id, m.weight, m.amount FROM unnest($2::UUID[], $3::INT[], $4::INT[]) AS m (user_id, weight, amount)
+	ON CONFLICT (subscription_id, user_id) DO UPDATE SET weight = EXCLUDED.weight, amount = EXCLUDED.amount`,
+		id, users, weights, amounts)
+
+	return err
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DueReminders возвращает подписки, до окончания которых (ReminderExpiring) или до окончания пробного периода
+// которых (ReminderTrialEnding) на дату today осталось не больше одного из windows дней и по которым напоминание
+// для этого окна еще не отправлялось. Если подошли несколько окон, берется наименьшее: пропущенное напоминание
+// за 7 дней не отправляется, когда уже пора напоминать за 1 день
+func (s *Storage) DueReminders(windows []int, today time.Time) ([]Reminder, error) {
+	const op = "storage.reminders.DueReminders"
+
+	// о конце пробного периода, совпадающем с концом подписки, напоминает ReminderExpiring.
+	// Price - цена первого месяца после события: после пробного периода с учетом вступительной цены
+	query := `WITH events AS (
+		SELECT id, 'expiring' AS kind, end_date AS event_date FROM subscriptions
+		WHERE end_date IS NOT NULL
+		UNION ALL
+		SELECT id, 'trial_ending', trial_ends_at FROM subscriptions
+		WHERE trial_ends_at IS NOT NULL AND (end_date IS NULL OR trial_ends_at < end_date)
+	)
+	SELECT ev.kind, s.id, s.user_id, u.email, s.service_name,
+		CASE WHEN ev.kind = 'trial_ending' THEN subscription_cost_at(s.id, e.expires_at)
+			ELSE subscription_price_at(s.id, ev.event_date) END,
+		ev.event_date, e.expires_at, s.auto_renew, s.renewal_months, w.days
+	FROM events ev
+	JOIN subscriptions s ON s.id = ev.id
+	JOIN users u ON u.id = s.user_id
+	CROSS JOIN LATERAL (SELECT (ev.event_date + INTERVAL '1 month')::DATE AS expires_at) e
+	CROSS JOIN LATERAL (
+		SELECT MIN(d) AS days FROM unnest($1::INT[]) AS d
+		WHERE e.expires_at - $2::DATE <= d) w
+	WHERE e.expires_at > $2::DATE
+		AND w.days IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM subscription_reminders r
+			WHERE r.subscription_id = s.id AND r.kind = ev.kind AND r.end_date = ev.event_date AND r.window_days = w.days)
+	ORDER BY e.expires_at, s.id`
+
+	rows, err := s.db.Query(context.Background(), query, windows, today)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var reminders []Reminder
+	for rows.Next() {
+		var r Reminder
+		if err := rows.Scan(&r.Kind, &r.SubscriptionID, &r.UserID, &r.Email, &r.ServiceName, &r.Price, &r.EndDate, &r.ExpiresAt,
+			&r.AutoRenew, &r.RenewalMonths, &r.WindowDays); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		reminders = append(reminders, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return reminders, nil
+}
+
+// ClaimReminder отмечает напоминание отправленным до фактической отправки, чтобы два экземпляра
+// сервиса не отправили его дважды. false - напоминание уже отмечено кем-то другим
+func (s *Storage) ClaimReminder(r Reminder) (bool, error) {
+	const op = "storage.reminders.ClaimReminder"
+
+	tag, err := s.db.Exec(context.Background(), `INSERT INTO subscription_reminders (subscription_id, kind, end_date, window_days)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT DO NOTHING`, r.SubscriptionID, r.Kind, r.EndDate, r.WindowDays)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// ReleaseReminder снимает отметку, если напоминание доставить не удалось; оно будет отправлено повторно
+func (s *Storage) ReleaseReminder(r Reminder) error {
+	const op = "storage.reminders.ReleaseReminder"
+
+	_, err := s.db.Exec(context.Background(), `DELETE FROM subscription_reminders
+	WHERE subscription_id = $1 AND kind = $2 AND end_date = $3 AND window_days = $4`,
+		r.SubscriptionID, r.Kind, r.EndDate, r.WindowDays)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// maxRenewalPasses ограничивает число сроков, на которые подписка продлевается за один запуск,
+// если задание долго не запускалось
+const maxRenewalPasses = 120
+
+// renewalColumns - порядок колонок для scanRenewal; r - продление, s - подписка, u - ее владелец
+const renewalColumns = `r.id, r.subscription_id, s.user_id, u.email, s.service_name,
+	subscription_price_at(r.subscription_id, (r.previous_end_date + INTERVAL '1 month')::DATE) AS price,
+	r.previous_end_date, r.new_end_date, r.renewed_at`
+
+func scanRenewal(row pgx.Row) (*Renewal, error) {
+	var r Renewal
+	var previousEnd, newEnd time.Time
+	if err := row.Scan(&r.ID, &r.SubscriptionID, &r.UserID, &r.Email, &r.ServiceName, &r.Price,
+		&previousEnd, &newEnd, &r.RenewedAt); err != nil {
+		return nil, err
+	}
+	r.PreviousEndDate = previousEnd.Format(DateLayout)
+	r.NewEndDate = newEnd.Format(DateLayout)
+
+	return &r, nil
+}
+
+// RenewDueSubscriptions продлевает на renewal_months неотмененные подписки с auto_renew, срок которых закончился
+// к дате today (подписка действует до конца месяца end_date). Подписка, пропустившая несколько сроков,
+// продлевается несколько раз, пока не станет действующей; каждое продление записывается событием renewed.
+// Подписка, разошедшаяся с журналом, пропускается до исправления проекции
+func (s *Storage) RenewDueSubscriptions(today time.Time) ([]Renewal, error) {
+	const op = "storage.renewals.RenewDueSubscriptions"
+
+	ctx := context.Background()
+	renewals := []Renewal{}
+	var renewed []uuid.UUID
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		// SKIP LOCKED - параллельный запуск на другом экземпляре не продлит подписку второй раз
+		rows, err := tx.Query(ctx, `SELECT id FROM subscriptions
+		WHERE auto_renew AND status <> 'cancelled' AND end_date IS NOT NULL AND end_date < date_trunc('month', $1::DATE)
+		ORDER BY id
+		FOR UPDATE SKIP LOCKED`, today)
+		if err != nil {
+			return err
+		}
+		due, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+		if err != nil {
+			return err
+		}
+
+		var renewalIDs []uuid.UUID
+		for _, id := range due {
+			strm, err := s.openStream(ctx, tx, id)
+			if errors.Is(err, myerrors.ErrProjectionDrift) {
+				s.log.Warn("skipping renewal", slog.String("op", op), slog.Any("subscription id", id), sl.Err(err))
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("subscription %s: %w", id, err)
+			}
+
+			if err := decideRenewals(strm, today); err != nil {
+				return fmt.Errorf("subscription %s: %w", id, err)
+			}
+			for _, data := range strm.pending {
+				if r, ok := data.(renewedEvent); ok {
+					renewalIDs = append(renewalIDs, r.RenewalID)
+				}
+			}
+			if len(strm.pending) == 0 {
+				continue
+			}
+			if err := s.saveStream(ctx, tx, strm); err != nil {
+				return fmt.Errorf("subscription %s: %w", id, err)
+			}
+			renewed = append(renewed, id)
+		}
+
+		rows, err = tx.Query(ctx, `SELECT `+renewalColumns+`
+		FROM subscription_renewals r
+		JOIN subscriptions s ON s.id = r.subscription_id
+		JOIN users u ON u.id = s.user_id
+		WHERE r.id = ANY($1)
+		ORDER BY r.new_end_date, r.subscription_id`, renewalIDs)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			r, err := scanRenewal(rows)
+			if err != nil {
+				return err
+			}
+			renewals = append(renewals, *r)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("rows iteration error: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// продленные подписки снова учитываются в расходах текущего месяца владельца и участников
+	for _, id := range renewed {
+		s.checkSubscriptionBudgets(id)
+	}
+
+	return renewals, nil
+}
+
+// ListRenewals возвращает историю продлений подписки, новые первыми
+func (s *Storage) ListRenewals(subscriptionID uuid.UUID) ([]Renewal, error) {
+	const op = "storage.renewals.ListRenewals"
+
+	if _, err := s.GetSubscription(subscriptionID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `SELECT ` + renewalColumns + `
+	FROM subscription_renewals r
+	JOIN subscriptions s ON s.id = r.subscription_id
+	JOIN users u ON u.id = s.user_id
+	WHERE r.subscription_id = $1
+	ORDER BY r.new_end_date DESC`
+
+	rows, err := s.db.Query(context.Background(), query, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	renewals := []Renewal{}
+	for rows.Next() {
+		r, err := scanRenewal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		renewals = append(renewals, *r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return renewals, nil
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+const (
+	// DefaultSuggestLimit - число подсказок, если limit не указан
+	DefaultSuggestLimit = 10
+	maxSuggestLimit     = 50
+)
+
+// likeEscaper экранирует спецсимволы LIKE, чтобы % и _ в запросе искались как обычные символы
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePrefix возвращает шаблон LIKE для поиска по префиксу
+func likePrefix(query string) string {
+	return likeEscaper.Replace(query) + "%"
+}
+
+// searchRank возвращает выражения ORDER BY для поиска filter.Query: сначала совпадения по префиксу,
+// затем по убыванию похожести. Без поиска возвращает пустую строку
+func (f SubscriptionFilter) searchRank(prefix string, args []any) (string, []any) {
+	query := normalizeServiceName(f.Query)
+	if query == "" {
+		return "", args
+	}
+
+	args = append(args, likePrefix(query), query)
+	return fmt.Sprintf(`lower(%[1]sservice_name) LIKE $%[2]d DESC, word_similarity($%[3]d, lower(%[1]sservice_name)) DESC, `,
+		prefix, len(args)-1, len(args)), args
+}
+
+// SuggestServiceNames возвращает до limit различных (без учета регистра) названий сервисов подписок,
+// подходящих под filter.Query, в порядке ранжирования поиска. Остальные поля фильтра сужают выборку
+func (s *Storage) SuggestServiceNames(filter SubscriptionFilter, limit int) ([]string, error) {
+	const op = "storage.search.SuggestServiceNames"
+
+	if normalizeServiceName(filter.Query) == "" {
+		return nil, fmt.Errorf("%s: %w", op, myerrors.ErrSearchQueryRequired)
+	}
+	if limit < 1 || limit > maxSuggestLimit {
+		return nil, fmt.Errorf("%s: %w", op, myerrors.ErrInvalidLimit)
+	}
+
+	conds, args, err := filter.where("s.", nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rank, args := filter.searchRank("s.", args)
+	args = append(args, limit)
+
+	// выражения ранжирования зависят только от lower(service_name), поэтому допустимы после GROUP BY
+	rows, err := s.db.Query(context.Background(), `SELECT min(s.service_name)
+	FROM subscriptions s WHERE 1 = 1`+conds+`
+	GROUP BY lower(s.service_name)
+	ORDER BY `+rank+`lower(s.service_name)
+	LIMIT $`+fmt.Sprint(len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,278 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+const serviceColumns = `id, name, aliases, category, website, default_currency, created_at, updated_at`
+
+func scanService(row pgx.Row) (*Service, error) {
+	var svc Service
+	if err := row.Scan(&svc.ID, &svc.Name, &svc.Aliases, &svc.Category, &svc.Website, &svc.DefaultCurrency, &svc.CreatedAt, &svc.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &svc, nil
+}
+
+// normalizeServiceName приводит название к виду, в котором хранятся псевдонимы:
+// нижний регистр, без лишних пробелов
+func normalizeServiceName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// normalizeAliases нормализует псевдонимы, убирает пустые, повторы и совпадающие с названием
+func normalizeAliases(name string, aliases []string) []string {
+	result := []string{}
+	canonical := normalizeServiceName(name)
+
+	for _, alias := range aliases {
+		alias = normalizeServiceName(alias)
+		if alias == "" || alias == canonical || slices.Contains(result, alias) {
+			continue
+		}
+		result = append(result, alias)
+	}
+
+	return result
+}
+
+// serviceNameFilter - условие на название сервиса подписки с учетом каталога:
+// совпадает само название либо подписка привязана к сервису, у которого это название или псевдоним.
+// Аргумент с номером argN - массив названий, уже нормализованных через normalizeServiceName.
+// Сервисы выбираются один раз в массив: с = ANY по массиву обе ветки OR идут по индексам
+func serviceNameFilter(prefix string, argN int) string {
+	return fmt.Sprintf(` AND (lower(%[1]sservice_name) = ANY($%[2]d) OR %[1]sservice_id = ANY(ARRAY(
+		SELECT id FROM services WHERE lower(name) = ANY($%[2]d) OR aliases && $%[2]d::TEXT[])))`, prefix, argN)
+}
+
+// resolveService находит сервис каталога по ID или по названию/псевдониму.
+// Возвращает ID сервиса (nil, если название в каталоге не найдено) и название для записи в подписку:
+// каноническое для сервиса из каталога, иначе переданное как есть
+func (s *Storage) resolveService(ctx context.Context, id *uuid.UUID, name string) (*uuid.UUID, string, error) {
+	const op = "storage.services.resolveService"
+
+	if id != nil {
+		var canonical string
+		err := s.db.QueryRow(ctx, `SELECT name FROM services WHERE id = $1`, *id).Scan(&canonical)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, "", fmt.Errorf("%s: %w", op, myerrors.ErrUnknownService)
+			}
+			return nil, "", fmt.Errorf("%s: %w", op, err)
+		}
+		return id, canonical, nil
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("%s: %w", op, myerrors.ErrServiceRequired)
+	}
+
+	var found uuid.UUID
+	var canonical string
+	err := s.db.QueryRow(ctx, `SELECT id, name FROM services
+	WHERE lower(name) = $1 OR $1 = ANY(aliases)
+	LIMIT 1`, normalizeServiceName(name)).Scan(&found, &canonical)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, name, nil
+		}
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &found, canonical, nil
+}
+
+// checkServiceNames проверяет, что название и псевдонимы не заняты другим сервисом
+func checkServiceNames(ctx context.Context, tx pgx.Tx, exceptID uuid.UUID, name string, aliases []string) error {
+	names := append([]string{normalizeServiceName(name)}, aliases...)
+
+	var taken bool
+	err := tx.QueryRow(ctx, `SELECT EXISTS (
+		SELECT 1 FROM services
+		WHERE id <> $1 AND (lower(name) = ANY($2) OR aliases && $2))`, exceptID, names).Scan(&taken)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return myerrors.ErrServiceExists
+	}
+
+	return nil
+}
+
+// linkedSubscriptions выбирает подписки без service_id, чье название совпадает с названием
+// или псевдонимом сервиса: запись сервиса привязывает их к нему
+func linkedSubscriptions(ctx context.Context, tx pgx.Tx, svc *Service) ([]uuid.UUID, error) {
+	return subscriptionIDs(ctx, tx, `SELECT id FROM subscriptions
+	WHERE service_id IS NULL AND (lower(service_name) = lower($1) OR lower(service_name) = ANY($2))
+	ORDER BY id`, svc.Name, svc.Aliases)
+}
+
+func (s *Storage) CreateService(req ServiceCreateRequest) (*Service, error) {
+	const op = "storage.services.CreateService"
+
+	name := strings.TrimSpace(req.Name)
+	aliases := normalizeAliases(name, req.Aliases)
+
+	var svc *Service
+	err := s.cascade(catalogChange{
+		write: func(ctx context.Context, tx pgx.Tx) error {
+			if err := checkServiceNames(ctx, tx, uuid.Nil, name, aliases); err != nil {
+				return err
+			}
+
+			var err error
+			svc, err = scanService(tx.QueryRow(ctx, `INSERT INTO services (name, aliases, category, website, default_currency)
+			VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'RUB'))
+			RETURNING `+serviceColumns,
+				name, aliases, req.Category, req.Website, strings.ToUpper(req.DefaultCurrency)))
+			return err
+		},
+		affected: func(ctx context.Context, tx pgx.Tx) ([]uuid.UUID, error) { return linkedSubscriptions(ctx, tx, svc) },
+		decide:   func(strm *stream) error { return decideService(strm, svc.Name, &svc.ID) },
+	})
+	if err != nil {
+		if pgErrCode(err) == pgUniqueViolation {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrServiceExists)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return svc, nil
+}
+
+func (s *Storage) GetService(id uuid.UUID) (*Service, error) {
+	const op = "storage.services.GetService"
+
+	svc, err := scanService(s.db.QueryRow(context.Background(), `SELECT `+serviceColumns+` FROM services WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrServiceNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return svc, nil
+}
+
+func (s *Storage) ListServices() ([]Service, error) {
+	const op = "storage.services.ListServices"
+
+	rows, err := s.db.Query(context.Background(), `SELECT `+serviceColumns+` FROM services ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	services := []Service{}
+	for rows.Next() {
+		svc, err := scanService(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		services = append(services, *svc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return services, nil
+}
+
+// UpdateService меняет сервис; при смене названия оно же проставляется привязанным подпискам
+func (s *Storage) UpdateService(id uuid.UUID, req ServiceUpdateRequest) (*Service, error) {
+	const op = "storage.services.UpdateService"
+
+	var svc *Service
+	err := s.cascade(catalogChange{
+		write: func(ctx context.Context, tx pgx.Tx) error {
+			current, err := scanService(tx.QueryRow(ctx, `SELECT `+serviceColumns+` FROM services WHERE id = $1 FOR UPDATE`, id))
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return myerrors.ErrServiceNotFound
+				}
+				return err
+			}
+
+			name := current.Name
+			if trimmed := strings.TrimSpace(req.Name); trimmed != "" {
+				name = trimmed
+			}
+			aliases := current.Aliases
+			if req.Aliases != nil {
+				aliases = req.Aliases
+			}
+			aliases = normalizeAliases(name, aliases)
+
+			if err := checkServiceNames(ctx, tx, id, name, aliases); err != nil {
+				return err
+			}
+
+			svc, err = scanService(tx.QueryRow(ctx, `UPDATE services SET
+				name = $1,
+				aliases = $2,
+				category = COALESCE(NULLIF($3, ''), category),
+				website = COALESCE(NULLIF($4, ''), website),
+				default_currency = COALESCE(NULLIF($5, ''), default_currency),
+				updated_at = NOW()
+			WHERE id = $6
+			RETURNING `+serviceColumns,
+				name, aliases, req.Category, req.Website, strings.ToUpper(req.DefaultCurrency), id))
+			return err
+		},
+		// привязанные подписки получают новое название, подходящие по названию привязываются
+		affected: func(ctx context.Context, tx pgx.Tx) ([]uuid.UUID, error) {
+			return subscriptionIDs(ctx, tx, `SELECT id FROM subscriptions
+			WHERE service_id = $1 OR (service_id IS NULL AND (lower(service_name) = lower($2) OR lower(service_name) = ANY($3)))
+			ORDER BY id`, id, svc.Name, svc.Aliases)
+		},
+		decide: func(strm *stream) error { return decideService(strm, svc.Name, &svc.ID) },
+	})
+	if err != nil {
+		if pgErrCode(err) == pgUniqueViolation {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrServiceExists)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return svc, nil
+}
+
+// DeleteService удаляет сервис из каталога. Подписки остаются с прежним service_name и без service_id
+func (s *Storage) DeleteService(id uuid.UUID) error {
+	const op = "storage.services.DeleteService"
+
+	err := s.cascade(catalogChange{
+		before: true,
+		affected: func(ctx context.Context, tx pgx.Tx) ([]uuid.UUID, error) {
+			return subscriptionIDs(ctx, tx, `SELECT id FROM subscriptions WHERE service_id = $1 ORDER BY id`, id)
+		},
+		write: func(ctx context.Context, tx pgx.Tx) error {
+			tag, err := tx.Exec(ctx, `DELETE FROM services WHERE id = $1`, id)
+			if err != nil {
+				return err
+			}
+			if tag.RowsAffected() == 0 {
+				return myerrors.ErrServiceNotFound
+			}
+			return nil
+		},
+		decide: func(strm *stream) error { return decideService(strm, strm.state.ServiceName, nil) },
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// statusTransitions - допустимые переходы между статусами. Отмененная и истекшая подписки
+// возвращаются только через ReactivateSubscription
+var statusTransitions = map[string][]string{
+	StatusTrial:     {StatusActive, StatusPaused, StatusCancelled, StatusExpired},
+	StatusActive:    {StatusPaused, StatusCancelled, StatusExpired},
+	StatusPaused:    {StatusTrial, StatusActive, StatusCancelled, StatusExpired},
+	StatusCancelled: {StatusTrial, StatusActive, StatusPaused},
+	StatusExpired:   {StatusTrial, StatusActive, StatusPaused},
+}
+
+// checkTransition возвращает ErrInvalidTransition, если из статуса from нельзя перейти в to
+func checkTransition(from, to string) error {
+	if !slices.Contains(statusTransitions[from], to) {
+		return fmt.Errorf("%w: %s -> %s", myerrors.ErrInvalidTransition, from, to)
+	}
+
+	return nil
+}
+
+// validStatuses проверяет значения фильтра status
+func validStatuses(statuses []string) error {
+	for _, status := range statuses {
+		if _, ok := statusTransitions[status]; !ok {
+			return myerrors.ErrInvalidStatus
+		}
+	}
+
+	return nil
+}
+
+// CancelSubscription отменяет подписку: она действует по месяц effective_date включительно
+// (по умолчанию текущий месяц владельца), автопродление выключается, паузы после этого месяца удаляются
+func (s *Storage) CancelSubscription(id uuid.UUID, req CancelRequest) (*Subscription, error) {
+	const op = "storage.status.CancelSubscription"
+
+	cancelledAt := time.Now().UTC().Truncate(time.Microsecond)
+	err := s.command(id, "", func(ctx context.Context, tx pgx.Tx, strm *stream) error {
+		month, err := streamMonth(ctx, tx, strm)
+		if err != nil {
+			return err
+		}
+		return decideCancel(strm, req, month, cancelledAt)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.checkSubscriptionBudgets(id)
+
+	return s.GetSubscription(id)
+}
+
+// reactivationEnd - end_date возобновленной подписки: новый месяц value или прежний end_date, если он еще не прошел.
+// Подписке с прошедшим end_date нужен новый срок, иначе ErrInvalidDateRange
+func reactivationEnd(start time.Time, end *time.Time, month time.Time, value string) (time.Time, error) {
+	if value == "" {
+		if end == nil || end.Before(month) {
+			return time.Time{}, fmt.Errorf("%w: end_date has passed, a new end_date is required", myerrors.ErrInvalidDateRange)
+		}
+
+		return *end, nil
+	}
+
+	parsed, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return time.Time{}, invalidRequest("end_date", err)
+	}
+	if parsed.Before(start) || parsed.Before(month) {
+		return time.Time{}, myerrors.ErrInvalidDateRange
+	}
+
+	return parsed, nil
+}
+
+// ReactivateSubscription возвращает отмененную или истекшую подписку. Новый статус следует из дат и пауз
+func (s *Storage) ReactivateSubscription(id uuid.UUID, req ReactivateRequest) (*Subscription, error) {
+	const op = "storage.status.ReactivateSubscription"
+
+	err := s.command(id, "", func(ctx context.Context, tx pgx.Tx, strm *stream) error {
+		month, err := streamMonth(ctx, tx, strm)
+		if err != nil {
+			return err
+		}
+		return decideReactivate(strm, req, month)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.checkSubscriptionBudgets(id)
+
+	return s.GetSubscription(id)
+}
+
+// SweepStatuses приводит статусы trial, active и paused в соответствие с датами и паузами на текущий месяц
+// владельца, записывая каждую смену событием status_changed. Недопустимые переходы и подписки,
+// разошедшиеся с журналом, пропускаются
+func (s *Storage) SweepStatuses() ([]StatusChange, error) {
+	const op = "storage.status.SweepStatuses"
+
+	ctx := context.Background()
+	changes := []StatusChange{}
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		// SKIP LOCKED - подписки, которые сейчас меняются, обработает следующий запуск
+		rows, err := tx.Query(ctx, `SELECT s.id, s.user_id, u.email, s.service_name, s.end_date, u.timezone
+		FROM subscriptions s
+		JOIN users u ON u.id = s.user_id
+		CROSS JOIN LATERAL (
+			SELECT subscription_status_at(s.id, date_trunc('month', NOW() AT TIME ZONE u.timezone)::DATE) AS status) d
+		WHERE s.status IN ('trial', 'active', 'paused') AND d.status <> s.status
+		ORDER BY s.id
+		FOR UPDATE OF s SKIP LOCKED`)
+		if err != nil {
+			return err
+		}
+
+		type candidate struct {
+			change   StatusChange
+			timezone string
+		}
+		var due []candidate
+		for rows.Next() {
+			var c candidate
+			var end *time.Time
+			if err := rows.Scan(&c.change.SubscriptionID, &c.change.UserID, &c.change.Email, &c.change.ServiceName,
+				&end, &c.timezone); err != nil {
+				rows.Close()
+				return err
+			}
+			c.change.EndDate = formatMonth(end)
+			due = append(due, c)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("rows iteration error: %w", err)
+		}
+
+		for _, c := range due {
+			id := c.change.SubscriptionID
+			strm, err := s.openStream(ctx, tx, id)
+			if errors.Is(err, myerrors.ErrProjectionDrift) {
+				s.log.Warn("skipping status change", slog.String("op", op), slog.Any("subscription id", id), sl.Err(err))
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("subscription %s: %w", id, err)
+			}
+
+			from := strm.state.Status
+			err = decideStatus(strm, currentMonth(c.timezone))
+			if errors.Is(err, myerrors.ErrInvalidTransition) {
+				s.log.Warn("skipping status change", slog.String("op", op), slog.Any("subscription id", id), sl.Err(err))
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("subscription %s: %w", id, err)
+			}
+			if len(strm.pending) == 0 {
+				continue
+			}
+			if err := s.saveStream(ctx, tx, strm); err != nil {
+				return fmt.Errorf("subscription %s: %w", id, err)
+			}
+
+			c.change.From, c.change.To = from, strm.state.Status
+			changes = append(changes, c.change)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return changes, nil
+}
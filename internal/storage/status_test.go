@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+func TestReactivationEnd(t *testing.T) {
+	start := month(t, "2025-01")
+	current := month(t, "2025-08")
+
+	tests := []struct {
+		name  string
+		end   *time.Time
+		value string
+		want  string
+		err   error
+	}{
+		{"keeps end that has not passed", monthPtr(t, "2025-12"), "", "2025-12", nil},
+		{"keeps end in the current month", monthPtr(t, "2025-08"), "", "2025-08", nil},
+		{"passed end needs a new one", monthPtr(t, "2025-05"), "", "", myerrors.ErrInvalidDateRange},
+		{"no end needs a new one", nil, "", "", myerrors.ErrInvalidDateRange},
+		{"new end", monthPtr(t, "2025-05"), "2026-06", "2026-06", nil},
+		{"new end before the current month", monthPtr(t, "2025-05"), "2025-07", "", myerrors.ErrInvalidDateRange},
+		{"new end before start", monthPtr(t, "2025-05"), "2024-12", "", myerrors.ErrInvalidDateRange},
+		{"malformed end", monthPtr(t, "2025-05"), "2026-13", "", myerrors.ErrInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reactivationEnd(start, tt.end, current, tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if tt.err == nil && got.Format(DateLayout) != tt.want {
+				t.Errorf("got %s, want %s", got.Format(DateLayout), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+// stream - события одной подписки в транзакции команды
+type stream struct {
+	id uuid.UUID
+	// version - последняя записанная в журнал версия, 0 - событий нет или журнал выключен
+	version int
+	// state - состояние после записанных и новых событий, nil - подписки нет
+	state *subscriptionState
+	// pending - новые события, уже примененные к проекции
+	pending []eventData
+	rules   subscriptionRules
+}
+
+// subscriptionRules - проекция событий и правила подписки, которые живут только в SQL:
+// статус по датам и паузам (subscription_status_at) и цена в месяце (subscription_price_at).
+// Правила читают проекцию, поэтому видят все события потока, примененные до вызова
+type subscriptionRules interface {
+	project(id uuid.UUID, data eventData, st *subscriptionState) error
+	statusAt(id uuid.UUID, month time.Time) (string, error)
+	priceAt(id uuid.UUID, month time.Time) (int, error)
+}
+
+// txRules - subscriptionRules в транзакции команды
+type txRules struct {
+	ctx context.Context
+	tx  pgx.Tx
+}
+
+func (r txRules) project(id uuid.UUID, data eventData, st *subscriptionState) error {
+	return project(r.ctx, r.tx, id, data, st)
+}
+
+func (r txRules) statusAt(id uuid.UUID, month time.Time) (string, error) {
+	var status string
+	err := r.tx.QueryRow(r.ctx, `SELECT subscription_status_at($1, $2)`, id, month).Scan(&status)
+
+	return status, err
+}
+
+func (r txRules) priceAt(id uuid.UUID, month time.Time) (int, error) {
+	var price int
+	err := r.tx.QueryRow(r.ctx, `SELECT subscription_price_at($1, $2)`, id, month).Scan(&price)
+
+	return price, err
+}
+
+// emit применяет событие к состоянию и проекции; событие, которое нельзя применить к состоянию, - ошибка
+func (s *stream) emit(data eventData) error {
+	next, err := applyEvent(s.state, data)
+	if err != nil {
+		return err
+	}
+	if err := s.rules.project(s.id, data, next); err != nil {
+		return fmt.Errorf("%s: %w", data.eventType(), err)
+	}
+	s.state = next
+	s.pending = append(s.pending, data)
+
+	return nil
+}
+
+// statusAt - статус, который следует из дат и пауз подписки в месяце month после событий потока
+func (s *stream) statusAt(month time.Time) (string, error) {
+	return s.rules.statusAt(s.id, month)
+}
+
+// priceAt - цена подписки в месяце month после событий потока
+func (s *stream) priceAt(month time.Time) (int, error) {
+	return s.rules.priceAt(s.id, month)
+}
+
+// command выполняет команду над подпиской id в одной транзакции: открывает ее поток, decide решает,
+// какие события с ней произошли, они применяются к проекции и, если журнал включен, дописываются в него.
+// policy - политика пересечений подписки после команды, пустая - не проверять
+func (s *Storage) command(id uuid.UUID, policy string, decide func(ctx context.Context, tx pgx.Tx, strm *stream) error) error {
+	ctx := context.Background()
+
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		strm, err := s.openStream(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := decide(ctx, tx, strm); err != nil {
+			return err
+		}
+		if err := s.saveStream(ctx, tx, strm); err != nil {
+			return err
+		}
+		if strm.state == nil {
+			return nil
+		}
+
+		return checkOverlaps(ctx, tx, id, policy)
+	})
+}
+
+// openStream блокирует строку подписки и открывает ее поток. Без журнала состояние читается из проекции.
+// С журналом оно сворачивается из событий и сверяется с проекцией: блокировка строки упорядочивает
+// команды над одной подпиской, а UNIQUE (subscription_id, version) не даст записать две ветки журнала
+func (s *Storage) openStream(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*stream, error) {
+	current, err := loadState(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	strm := &stream{id: id, state: current, rules: txRules{ctx: ctx, tx: tx}}
+	if !s.journal {
+		return strm, nil
+	}
+
+	if strm.state, strm.version, err = replaySubscription(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	if err := checkProjection(strm.state, strm.version, current); err != nil {
+		return nil, err
+	}
+
+	return strm, nil
+}
+
+// saveStream дописывает новые события потока в журнал, если он включен
+func (s *Storage) saveStream(ctx context.Context, tx pgx.Tx, strm *stream) error {
+	if s.journal {
+		for _, data := range strm.pending {
+			if err := appendEvent(ctx, tx, strm.id, strm.version+1, data); err != nil {
+				return err
+			}
+			strm.version++
+		}
+	}
+	strm.pending = nil
+
+	return nil
+}
+
+// appendEvent дописывает событие подписки в журнал с версией version
+func appendEvent(ctx context.Context, tx pgx.Tx, id uuid.UUID, version int, data eventData) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO subscription_events (subscription_id, version, type, data)
+	VALUES ($1, $2, $3, $4)`, id, version, data.eventType(), payload)
+
+	return err
+}
+
+// replaySubscription сворачивает события подписки в ее состояние; version - последняя версия, 0 - событий нет
+func replaySubscription(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*subscriptionState, int, error) {
+	rows, err := tx.Query(ctx, `SELECT version, type, data FROM subscription_events
+	WHERE subscription_id = $1
+	ORDER BY version`, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var st *subscriptionState
+	var version int
+	for rows.Next() {
+		var typ string
+		var raw []byte
+		if err := rows.Scan(&version, &typ, &raw); err != nil {
+			return nil, 0, err
+		}
+		data, err := decodeEvent(typ, raw)
+		if err != nil {
+			return nil, 0, fmt.Errorf("version %d: %w", version, err)
+		}
+		if st, err = applyEvent(st, data); err != nil {
+			return nil, 0, fmt.Errorf("version %d: %w", version, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return st, version, nil
+}
+
+// streamMonth - текущий месяц владельца подписки; ErrNotFound - подписки нет
+func streamMonth(ctx context.Context, tx pgx.Tx, strm *stream) (time.Time, error) {
+	if strm.state == nil {
+		return time.Time{}, myerrors.ErrNotFound
+	}
+
+	return ownerMonth(ctx, tx, strm.state.UserID)
+}
+
+// ownerMonth - текущий месяц в часовом поясе пользователя; ErrUnknownUser - пользователя нет
+func ownerMonth(ctx context.Context, tx pgx.Tx, userID uuid.UUID) (time.Time, error) {
+	var timezone string
+	err := tx.QueryRow(ctx, `SELECT timezone FROM users WHERE id = $1`, userID).Scan(&timezone)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, myerrors.ErrUnknownUser
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return currentMonth(timezone), nil
+}
+
+// catalogChange - запись справочника, которая меняет подписки: привязка к сервису, переименование
+// сервиса или тега, удаление сервиса, категории или тега
+type catalogChange struct {
+	// before - affected выбирает подписки до write, пока внешние ключи их не изменили; иначе после
+	before   bool
+	affected func(ctx context.Context, tx pgx.Tx) ([]uuid.UUID, error)
+	// write меняет только справочник; подписки меняются событиями decide
+	write func(ctx context.Context, tx pgx.Tx) error
+	// decide записывает в поток подписки событие о том, что с ней сделала write
+	decide func(strm *stream) error
+}
+
+// cascade выполняет запись справочника и в той же транзакции записывает события затронутых подписок.
+// С журналом проекция после событий сверяется с ним: изменение подписки внешним ключом, которого
+// нет в событиях, отменяет запись целиком
+func (s *Storage) cascade(change catalogChange) error {
+	ctx := context.Background()
+
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		var streams []*stream
+		open := func() error {
+			ids, err := change.affected(ctx, tx)
+			if err != nil {
+				return err
+			}
+			for _, id := range ids {
+				strm, err := s.openStream(ctx, tx, id)
+				if err != nil {
+					return fmt.Errorf("subscription %s: %w", id, err)
+				}
+				streams = append(streams, strm)
+			}
+			return nil
+		}
+
+		if change.before {
+			if err := open(); err != nil {
+				return err
+			}
+		}
+		if err := change.write(ctx, tx); err != nil {
+			return err
+		}
+		if !change.before {
+			if err := open(); err != nil {
+				return err
+			}
+		}
+
+		for _, strm := range streams {
+			if err := change.decide(strm); err != nil {
+				return fmt.Errorf("subscription %s: %w", strm.id, err)
+			}
+			if err := s.saveStream(ctx, tx, strm); err != nil {
+				return fmt.Errorf("subscription %s: %w", strm.id, err)
+			}
+			if !s.journal {
+				continue
+			}
+			current, err := loadState(ctx, tx, strm.id, false)
+			if err != nil {
+				return fmt.Errorf("subscription %s: %w", strm.id, err)
+			}
+			if err := checkProjection(strm.state, strm.version, current); err != nil {
+				return fmt.Errorf("subscription %s: %w", strm.id, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// subscriptionIDs выбирает ID подписок запросом query
+func subscriptionIDs(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+}
+
+// tagName возвращает название тега; ErrTagNotFound - тега нет
+func tagName(ctx context.Context, tx pgx.Tx, id uuid.UUID) (string, error) {
+	var name string
+	err := tx.QueryRow(ctx, `SELECT name FROM tags WHERE id = $1`, id).Scan(&name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", myerrors.ErrTagNotFound
+	}
+
+	return name, err
+}
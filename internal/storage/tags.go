@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+const maxTagLength = 64
+
+// normalizeTag приводит тег к нижнему регистру и убирает лишние пробелы
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// normalizeTags нормализует теги и убирает повторы; пустой или слишком длинный тег - ошибка
+func normalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, myerrors.ErrInvalidTag
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		result = append(result, tag)
+	}
+
+	return result, nil
+}
+
+// setSubscriptionTags заменяет набор тегов подписки, недостающие теги создаются
+func setSubscriptionTags(ctx context.Context, tx pgx.Tx, subscriptionID uuid.UUID, tags []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM subscription_tags WHERE subscription_id = $1`, subscriptionID); err != nil {
+		return err
+	}
+
+	return addSubscriptionTags(ctx, tx, subscriptionID, tags)
+}
+
+func addSubscriptionTags(ctx context.Context, tx pgx.Tx, subscriptionID uuid.UUID, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, `INSERT INTO tags (name) SELECT unnest($1::TEXT[]) ON CONFLICT (name) DO NOTHING`, tags)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO subscription_tags (subscription_id, tag_id)
+	SELECT $1::UUID, id FROM tags WHERE name = ANY($2)
+	ON CONFLICT DO NOTHING`, subscriptionID, tags)
+
+	return err
+}
+
+// SetSubscriptionTags заменяет все теги подписки
+func (s *Storage) SetSubscriptionTags(id uuid.UUID, tags []string) error {
+	const op = "storage.tags.SetSubscriptionTags"
+
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.command(id, "", func(_ context.Context, _ pgx.Tx, strm *stream) error {
+		return decideTags(strm, func([]string) []string { return tags })
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AddSubscriptionTag добавляет подписке тег; повторное добавление ничего не меняет
+func (s *Storage) AddSubscriptionTag(id uuid.UUID, tag string) error {
+	const op = "storage.tags.AddSubscriptionTag"
+
+	tags, err := normalizeTags([]string{tag})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.command(id, "", func(_ context.Context, _ pgx.Tx, strm *stream) error {
+		return decideTags(strm, func(current []string) []string { return append(current, tags[0]) })
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RemoveSubscriptionTag снимает тег с подписки; сам тег остается в справочнике
+func (s *Storage) RemoveSubscriptionTag(id uuid.UUID, tag string) error {
+	const op = "storage.tags.RemoveSubscriptionTag"
+
+	tag = normalizeTag(tag)
+	err := s.command(id, "", func(_ context.Context, _ pgx.Tx, strm *stream) error {
+		return decideTags(strm, func(current []string) []string {
+			return slices.DeleteFunc(current, func(t string) bool { return t == tag })
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) CreateTag(name string) (*Tag, error) {
+	const op = "storage.tags.CreateTag"
+
+	tags, err := normalizeTags([]string{name})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var tag Tag
+	err = s.db.QueryRow(context.Background(), `INSERT INTO tags (name) VALUES ($1) RETURNING id, name`, tags[0]).
+		Scan(&tag.ID, &tag.Name)
+	if err != nil {
+		if pgErrCode(err) == pgUniqueViolation {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrTagExists)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &tag, nil
+}
+
+// ListTags возвращает все теги с числом подписок у каждого
+func (s *Storage) ListTags() ([]Tag, error) {
+	const op = "storage.tags.ListTags"
+
+	rows, err := s.db.Query(context.Background(), `SELECT t.id, t.name, COUNT(st.subscription_id)
+	FROM tags t
+	LEFT JOIN subscription_tags st ON st.tag_id = t.id
+	GROUP BY t.id, t.name
+	ORDER BY t.name`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	tags := []Tag{}
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Subscriptions); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return tags, nil
+}
+
+func (s *Storage) RenameTag(id uuid.UUID, name string) (*Tag, error) {
+	const op = "storage.tags.RenameTag"
+
+	tags, err := normalizeTags([]string{name})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var oldName string
+	var tag Tag
+	err = s.cascade(catalogChange{
+		before: true,
+		affected: func(ctx context.Context, tx pgx.Tx) ([]uuid.UUID, error) {
+			var err error
+			if oldName, err = tagName(ctx, tx, id); err != nil {
+				return nil, err
+			}
+			return subscriptionIDs(ctx, tx, `SELECT subscription_id FROM subscription_tags WHERE tag_id = $1 ORDER BY 1`, id)
+		},
+		write: func(ctx context.Context, tx pgx.Tx) error {
+			return tx.QueryRow(ctx, `UPDATE tags SET name = $1 WHERE id = $2
+			RETURNING id, name, (SELECT COUNT(*) FROM subscription_tags WHERE tag_id = $2)`, tags[0], id).
+				Scan(&tag.ID, &tag.Name, &tag.Subscriptions)
+		},
+		decide: func(strm *stream) error {
+			return decideTags(strm, func(current []string) []string {
+				return append(slices.DeleteFunc(current, func(t string) bool { return t == oldName }), tag.Name)
+			})
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows), errors.Is(err, myerrors.ErrTagNotFound):
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrTagNotFound)
+		case pgErrCode(err) == pgUniqueViolation:
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrTagExists)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &tag, nil
+}
+
+// DeleteTag удаляет тег из справочника и со всех подписок
+func (s *Storage) DeleteTag(id uuid.UUID) error {
+	const op = "storage.tags.DeleteTag"
+
+	var name string
+	err := s.cascade(catalogChange{
+		before: true,
+		affected: func(ctx context.Context, tx pgx.Tx) ([]uuid.UUID, error) {
+			var err error
+			if name, err = tagName(ctx, tx, id); err != nil {
+				return nil, err
+			}
+			return subscriptionIDs(ctx, tx, `SELECT subscription_id FROM subscription_tags WHERE tag_id = $1 ORDER BY 1`, id)
+		},
+		write: func(ctx context.Context, tx pgx.Tx) error {
+			_, err := tx.Exec(ctx, `DELETE FROM tags WHERE id = $1`, id)
+			return err
+		},
+		decide: func(strm *stream) error {
+			return decideTags(strm, func(current []string) []string {
+				return slices.DeleteFunc(current, func(t string) bool { return t == name })
+			})
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+// группировки временного ряда, кроме GroupByCategory
+const (
+	GroupByService = "service"
+	GroupByUser    = "user"
+)
+
+// maxSeriesMonths - наибольшая длина временного ряда (20 лет)
+const maxSeriesMonths = 240
+
+// parsePeriod разбирает период from..to в формате YYYY-MM
+func parsePeriod(from, to string) (time.Time, time.Time, error) {
+	fromDate, err := time.Parse(DateLayout, from)
+	if err != nil {
+		return time.Time{}, time.Time{}, invalidRequest("from", err)
+	}
+	toDate, err := time.Parse(DateLayout, to)
+	if err != nil {
+		return time.Time{}, time.Time{}, invalidRequest("to", err)
+	}
+	if toDate.Before(fromDate) {
+		return time.Time{}, time.Time{}, myerrors.ErrInvalidPeriod
+	}
+
+	return fromDate, toDate, nil
+}
+
+// GetTimeSeries возвращает для каждого месяца периода from..to стоимость подписок и число действующих
+// (не на паузе) подписок с теми же фильтрами, что у списка. groupBy - пустой, GroupByService, GroupByCategory
+// или GroupByUser; при GroupByUser общая подписка делится между участниками по их долям
+func (s *Storage) GetTimeSeries(filter SubscriptionFilter, from, to, groupBy string) (*TimeSeriesResponse, error) {
+	const op = "storage.timeseries.GetTimeSeries"
+
+	fromDate, toDate, err := parsePeriod(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if toDate.After(fromDate.AddDate(0, maxSeriesMonths-1, 0)) {
+		return nil, fmt.Errorf("%s: %w", op, myerrors.ErrPeriodTooLong)
+	}
+
+	conds, args, err := filter.where("s.", []any{fromDate, toDate})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var key, name, joins, cost string
+	switch groupBy {
+	case "":
+		key, name = "''", "''"
+		cost, args = filter.costExpr("s.id", "mo.m", args)
+	case GroupByService:
+		key, name = "COALESCE(s.service_id::TEXT, '')", "s.service_name"
+		cost, args = filter.costExpr("s.id", "mo.m", args)
+	case GroupByCategory:
+		key, name = "COALESCE(c.id::TEXT, '')", "COALESCE(c.name, '')"
+		joins = "LEFT JOIN categories c ON c.id = s.category_id"
+		cost, args = filter.costExpr("s.id", "mo.m", args)
+	case GroupByUser:
+		// участники - владелец и пользователи из subscription_members
+		key, name = "p.user_id::TEXT", "COALESCE(NULLIF(u.display_name, ''), u.email, '')"
+		joins = `CROSS JOIN LATERAL (
+			SELECT s.user_id
+			UNION
+			SELECT sm.user_id FROM subscription_members sm WHERE sm.subscription_id = s.id) p
+		JOIN users u ON u.id = p.user_id`
+		cost = "subscription_share_at(s.id, p.user_id, mo.m)"
+		if filter.UserID != "" {
+			args = append(args, filter.UserID)
+			conds += fmt.Sprintf(" AND p.user_id = $%d", len(args))
+		}
+	default:
+		return nil, fmt.Errorf("%s: %w", op, myerrors.ErrInvalidGroupBy)
+	}
+
+	// строки с GROUPING(i.key) = 1 - итог месяца, остальные - группы. Итог считается отдельно,
+	// чтобы общая подписка в группировке по пользователям учитывалась в числе действующих один раз
+	query := `WITH months AS (
+		SELECT generate_series($1::DATE, $2::DATE, INTERVAL '1 month')::DATE AS m
+	), items AS (
+		SELECT mo.m, s.id, ` + key + ` AS key, ` + name + ` AS name, ` + cost + ` AS cost,
+			subscription_active_at(s.id, mo.m) AS active
+		FROM months mo
+		JOIN subscriptions s ON s.start_date <= mo.m AND (s.end_date IS NULL OR s.end_date >= mo.m)
+		` + joins + `
+		WHERE 1 = 1` + conds + `
+	)
+	SELECT mo.m, GROUPING(i.key, i.name) = 0, i.key, i.name,
+		ROUND(COALESCE(SUM(i.cost), 0))::BIGINT,
+		COUNT(DISTINCT i.id) FILTER (WHERE i.active)
+	FROM months mo
+	LEFT JOIN items i ON i.m = mo.m
+	GROUP BY GROUPING SETS ((mo.m), (mo.m, i.key, i.name))
+	ORDER BY mo.m, GROUPING(i.key, i.name) DESC, i.name, i.key`
+
+	rows, err := s.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	resp := &TimeSeriesResponse{From: from, To: to, GroupBy: groupBy, Points: []TimeSeriesPoint{}}
+	for rows.Next() {
+		var month time.Time
+		var isGroup bool
+		var groupKey, groupName *string
+		var total, active int
+		if err := rows.Scan(&month, &isGroup, &groupKey, &groupName, &total, &active); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !isGroup {
+			resp.Points = append(resp.Points, TimeSeriesPoint{Month: month.Format(DateLayout), Total: total, Active: active})
+			continue
+		}
+		// пустой месяц дает группу без ключа
+		if groupBy == "" || groupKey == nil {
+			continue
+		}
+		point := &resp.Points[len(resp.Points)-1]
+		point.Groups = append(point.Groups, TimeSeriesGroup{Key: *groupKey, Name: *groupName, Total: total, Active: active})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return resp, nil
+}
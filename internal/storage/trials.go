@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+// parseTrial разбирает trial_ends_at в формате YYYY-MM, пустая строка - пробного периода нет.
+// Попадание в границы подписки проверяет ограничение subscriptions_trial_check
+func parseTrial(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	trial, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return nil, invalidRequest("trial_ends_at", err)
+	}
+
+	return &trial, nil
+}
+
+// introValues проверяет вступительную цену и срок: оба нулевые (вступительной цены нет) или оба положительные
+func introValues(price, months int) (*int, *int, error) {
+	if price == 0 && months == 0 {
+		return nil, nil, nil
+	}
+	if price < 1 || months < 1 {
+		return nil, nil, myerrors.ErrInvalidIntro
+	}
+
+	return &price, &months, nil
+}
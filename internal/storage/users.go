@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+const userColumns = `id, email, display_name, timezone, default_currency, created_at, updated_at`
+
+func scanUser(row pgx.Row) (*User, error) {
+	var u User
+	if err := row.Scan(&u.ID, &u.Email, &u.DisplayName, &u.Timezone, &u.DefaultCurrency, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// validateTimezone проверяет имя часового пояса IANA, пустое значение допустимо
+func validateTimezone(tz string) error {
+	if tz == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return myerrors.ErrInvalidTimezone
+	}
+
+	return nil
+}
+
+func (s *Storage) CreateUser(req UserCreateRequest) (*User, error) {
+	const op = "storage.users.CreateUser"
+
+	if err := validateTimezone(req.Timezone); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `INSERT INTO users (email, display_name, timezone, default_currency)
+	VALUES ($1, $2, COALESCE(NULLIF($3, ''), 'UTC'), COALESCE(NULLIF($4, ''), 'RUB'))
+	RETURNING ` + userColumns
+
+	user, err := scanUser(s.db.QueryRow(context.Background(), query,
+		strings.ToLower(req.Email), req.DisplayName, req.Timezone, strings.ToUpper(req.DefaultCurrency)))
+	if err != nil {
+		if pgErrCode(err) == pgUniqueViolation {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrUserExists)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+func (s *Storage) GetUser(id uuid.UUID) (*User, error) {
+	const op = "storage.users.GetUser"
+
+	user, err := scanUser(s.db.QueryRow(context.Background(), `SELECT `+userColumns+` FROM users WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+func (s *Storage) ListUsers() ([]User, error) {
+	const op = "storage.users.ListUsers"
+
+	rows, err := s.db.Query(context.Background(), `SELECT `+userColumns+` FROM users ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		users = append(users, *user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows iteration error: %w", op, err)
+	}
+
+	return users, nil
+}
+
+func (s *Storage) UpdateUser(id uuid.UUID, req UserUpdateRequest) (*User, error) {
+	const op = "storage.users.UpdateUser"
+
+	if err := validateTimezone(req.Timezone); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `UPDATE users SET
+		email = COALESCE(NULLIF($1, ''), email),
+		display_name = COALESCE(NULLIF($2, ''), display_name),
+		timezone = COALESCE(NULLIF($3, ''), timezone),
+		default_currency = COALESCE(NULLIF($4, ''), default_currency),
+		updated_at = NOW()
+	WHERE id = $5
+	RETURNING ` + userColumns
+
+	user, err := scanUser(s.db.QueryRow(context.Background(), query,
+		strings.ToLower(req.Email), req.DisplayName, req.Timezone, strings.ToUpper(req.DefaultCurrency), id))
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrUserNotFound)
+		case pgErrCode(err) == pgUniqueViolation:
+			return nil, fmt.Errorf("%s: %w", op, myerrors.ErrUserExists)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// DeleteUser удаляет пользователя без подписок; если подписки есть - ErrUserHasSubscriptions
+func (s *Storage) DeleteUser(id uuid.UUID) error {
+	const op = "storage.users.DeleteUser"
+
+	// участие в общих подписках удаляется вместе с пользователем, подписки записывают его событием
+	err := s.cascade(catalogChange{
+		before: true,
+		affected: func(ctx context.Context, tx pgx.Tx) ([]uuid.UUID, error) {
+			return subscriptionIDs(ctx, tx, `SELECT subscription_id FROM subscription_members
+			WHERE user_id = $1
+			ORDER BY subscription_id`, id)
+		},
+		write: func(ctx context.Context, tx pgx.Tx) error {
+			tag, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+			if err != nil {
+				return err
+			}
+			if tag.RowsAffected() == 0 {
+				return myerrors.ErrUserNotFound
+			}
+			return nil
+		},
+		decide: func(strm *stream) error { return decideRemoveMember(strm, id) },
+	})
+	if err != nil {
+		if pgErrCode(err) == pgForeignKeyViolation {
+			return fmt.Errorf("%s: %w", op, myerrors.ErrUserHasSubscriptions)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// Package sweeper runs the job that keeps subscription statuses in line with their dates
+package sweeper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/odlev/subscriptions/internal/notifier"
+	"github.com/odlev/subscriptions/internal/storage"
+	"github.com/odlev/subscriptions/pkg/sl"
+)
+
+// EventSubscriptionExpired - тип уведомления об истечении подписки
+const EventSubscriptionExpired = "subscription.expired"
+
+type StatusStore interface {
+	SweepStatuses() ([]storage.StatusChange, error)
+}
+
+// Job раз в interval переводит подписки в статус, который следует из их дат и пауз,
+// и уведомляет об истекших подписках
+type Job struct {
+	log      *slog.Logger
+	store    StatusStore
+	notifier notifier.Notifier
+	interval time.Duration
+}
+
+func New(log *slog.Logger, store StatusStore, n notifier.Notifier, interval time.Duration) *Job {
+	return &Job{
+		log:      log,
+		store:    store,
+		notifier: n,
+		interval: interval,
+	}
+}
+
+// Run выполняет проход сразу и затем каждые interval, пока не отменен ctx
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce меняет статусы один раз. Статус уже записан к моменту уведомления,
+// поэтому ошибка доставки только логируется
+func (j *Job) RunOnce(ctx context.Context) {
+	changes, err := j.store.SweepStatuses()
+	if err != nil {
+		j.log.Error("failed to sweep subscription statuses", sl.Err(err))
+		return
+	}
+
+	for _, c := range changes {
+		log := j.log.With(slog.Any("subscription id", c.SubscriptionID), slog.String("from", c.From), slog.String("to", c.To))
+		log.Info("subscription status changed")
+
+		if c.To != storage.StatusExpired {
+			continue
+		}
+		if err := j.notifier.Notify(ctx, notification(c)); err != nil {
+			log.Error("failed to send expiration notification", sl.Err(err))
+		}
+	}
+}
+
+func notification(c storage.StatusChange) notifier.Notification {
+	n := notifier.Notification{
+		Event:   EventSubscriptionExpired,
+		UserID:  c.UserID,
+		Subject: fmt.Sprintf("Подписка %s закончилась", c.ServiceName),
+		Text:    fmt.Sprintf("Срок подписки %s закончился в %s. Ее можно возобновить.", c.ServiceName, c.EndDate),
+		Data:    c,
+	}
+	if c.Email != nil {
+		n.Email = *c.Email
+	}
+
+	return n
+}
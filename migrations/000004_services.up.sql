@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS services (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL,
+    -- псевдонимы хранятся в нижнем регистре, без лишних пробелов
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    category TEXT NOT NULL DEFAULT '',
+    website TEXT NOT NULL DEFAULT '',
+    default_currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS services_lower_name_key ON services (lower(name));
+CREATE INDEX IF NOT EXISTS services_aliases_idx ON services USING GIN (aliases);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS service_id UUID REFERENCES services (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS subscriptions_service_id_idx ON subscriptions (service_id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS subscription_tags;
+DROP TABLE IF EXISTS tags;
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS categories;
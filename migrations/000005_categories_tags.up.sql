@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL,
+    parent_id UUID REFERENCES categories (id) ON DELETE RESTRICT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- имя уникально среди категорий с одним родителем, корневые считаются одной группой
+CREATE UNIQUE INDEX IF NOT EXISTS categories_parent_lower_name_key
+    ON categories (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'::UUID), lower(name));
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories (parent_id);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS category_id UUID
+        CONSTRAINT subscriptions_category_id_fkey REFERENCES categories (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS subscriptions_category_id_idx ON subscriptions (category_id);
+
+-- теги хранятся в нижнем регистре, без лишних пробелов
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS subscription_tags (
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (subscription_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS subscription_tags_tag_id_idx ON subscription_tags (tag_id);
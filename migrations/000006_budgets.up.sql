@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS budgets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- без категории бюджет охватывает все подписки пользователя
+    category_id UUID REFERENCES categories (id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    amount INT NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- один бюджет на пользователя и категорию, общий бюджет - тоже один
+CREATE UNIQUE INDEX IF NOT EXISTS budgets_user_category_key
+    ON budgets (user_id, COALESCE(category_id, '00000000-0000-0000-0000-000000000000'::UUID));
+
+-- отправленные оповещения: каждый порог срабатывает один раз за месяц
+CREATE TABLE IF NOT EXISTS budget_alerts (
+    budget_id UUID NOT NULL REFERENCES budgets (id) ON DELETE CASCADE,
+    month DATE NOT NULL,
+    threshold INT NOT NULL,
+    spent INT NOT NULL,
+    amount INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (budget_id, month, threshold)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS subscriptions_end_date_idx;
+DROP TABLE IF EXISTS subscription_reminders;
@@ -0,0 +1,11 @@
+-- отправленные напоминания об окончании подписки. end_date входит в ключ,
+-- чтобы после продления подписки напоминание пришло снова
+CREATE TABLE IF NOT EXISTS subscription_reminders (
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    end_date DATE NOT NULL,
+    window_days INT NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, end_date, window_days)
+);
+
+CREATE INDEX IF NOT EXISTS subscriptions_end_date_idx ON subscriptions (end_date);
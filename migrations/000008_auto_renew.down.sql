@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS subscriptions_auto_renew_end_date_idx;
+DROP TABLE IF EXISTS subscription_renewals;
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS renewal_months,
+    DROP COLUMN IF EXISTS auto_renew;
@@ -0,0 +1,19 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS auto_renew BOOLEAN NOT NULL DEFAULT FALSE,
+    -- срок продления в месяцах
+    ADD COLUMN IF NOT EXISTS renewal_months INT NOT NULL DEFAULT 12 CHECK (renewal_months > 0);
+
+-- история продлений: одна запись на каждый срок, на который продлилась подписка
+CREATE TABLE IF NOT EXISTS subscription_renewals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    previous_end_date DATE NOT NULL,
+    new_end_date DATE NOT NULL,
+    renewed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS subscription_renewals_subscription_id_idx
+    ON subscription_renewals (subscription_id);
+
+CREATE INDEX IF NOT EXISTS subscriptions_auto_renew_end_date_idx
+    ON subscriptions (end_date) WHERE auto_renew;
@@ -0,0 +1,27 @@
+-- периоды цен подписки: цена действует с effective_from (первое число месяца) до следующего периода
+CREATE TABLE IF NOT EXISTS subscription_prices (
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    effective_from DATE NOT NULL,
+    price INT NOT NULL CHECK (price > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, effective_from)
+);
+
+INSERT INTO subscription_prices (subscription_id, effective_from, price)
+SELECT id, date_trunc('month', start_date)::DATE, price::INT
+FROM subscriptions
+ON CONFLICT DO NOTHING;
+
+-- цена подписки $1 в месяце $2: последний период, начавшийся не позже месяца.
+-- До первого периода действует первая цена, без периодов - subscriptions.price
+CREATE OR REPLACE FUNCTION subscription_price_at(UUID, DATE) RETURNS INT
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(
+        (SELECT p.price FROM subscription_prices p
+            WHERE p.subscription_id = $1
+            ORDER BY p.effective_from <= $2 DESC,
+                CASE WHEN p.effective_from <= $2 THEN p.effective_from END DESC,
+                p.effective_from
+            LIMIT 1),
+        (SELECT s.price::INT FROM subscriptions s WHERE s.id = $1))
+$$;
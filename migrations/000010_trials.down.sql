@@ -0,0 +1,13 @@
+DROP FUNCTION IF EXISTS subscription_cost_at(UUID, DATE);
+
+DELETE FROM subscription_reminders WHERE kind <> 'expiring';
+ALTER TABLE subscription_reminders DROP CONSTRAINT subscription_reminders_pkey;
+ALTER TABLE subscription_reminders ADD PRIMARY KEY (subscription_id, end_date, window_days);
+ALTER TABLE subscription_reminders DROP COLUMN IF EXISTS kind;
+
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS subscriptions_intro_check,
+    DROP CONSTRAINT IF EXISTS subscriptions_trial_check,
+    DROP COLUMN IF EXISTS intro_months,
+    DROP COLUMN IF EXISTS intro_price,
+    DROP COLUMN IF EXISTS trial_ends_at;
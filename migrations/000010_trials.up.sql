@@ -0,0 +1,33 @@
+ALTER TABLE subscriptions
+    -- последний месяц пробного периода, включительно
+    ADD COLUMN IF NOT EXISTS trial_ends_at DATE,
+    -- вступительная цена на первые intro_months оплачиваемых месяцев
+    ADD COLUMN IF NOT EXISTS intro_price INT CHECK (intro_price > 0),
+    ADD COLUMN IF NOT EXISTS intro_months INT CHECK (intro_months > 0);
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_trial_check CHECK (
+        trial_ends_at IS NULL OR (trial_ends_at >= start_date AND (end_date IS NULL OR trial_ends_at <= end_date))),
+    ADD CONSTRAINT subscriptions_intro_check CHECK ((intro_price IS NULL) = (intro_months IS NULL));
+
+-- напоминания бывают об окончании подписки и об окончании пробного периода;
+-- end_date - дата события: end_date или trial_ends_at подписки
+ALTER TABLE subscription_reminders ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'expiring';
+ALTER TABLE subscription_reminders DROP CONSTRAINT subscription_reminders_pkey;
+ALTER TABLE subscription_reminders ADD PRIMARY KEY (subscription_id, kind, end_date, window_days);
+
+-- сколько подписка $1 стоит в месяце $2: 0 в пробный период, intro_price в первые intro_months
+-- месяцев после него (или после start_date), дальше цена из subscription_price_at
+CREATE OR REPLACE FUNCTION subscription_cost_at(UUID, DATE) RETURNS INT
+LANGUAGE sql STABLE AS $$
+    SELECT CASE
+        WHEN s.trial_ends_at IS NOT NULL AND $2 <= s.trial_ends_at THEN 0
+        WHEN s.intro_price IS NOT NULL
+            AND $2 < COALESCE((s.trial_ends_at + INTERVAL '1 month')::DATE, s.start_date)
+                + make_interval(months => s.intro_months)
+            THEN s.intro_price
+        ELSE subscription_price_at(s.id, $2)
+    END
+    FROM subscriptions s
+    WHERE s.id = $1
+$$;
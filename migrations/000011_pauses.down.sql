@@ -0,0 +1,17 @@
+CREATE OR REPLACE FUNCTION subscription_cost_at(UUID, DATE) RETURNS INT
+LANGUAGE sql STABLE AS $$
+    SELECT CASE
+        WHEN s.trial_ends_at IS NOT NULL AND $2 <= s.trial_ends_at THEN 0
+        WHEN s.intro_price IS NOT NULL
+            AND $2 < COALESCE((s.trial_ends_at + INTERVAL '1 month')::DATE, s.start_date)
+                + make_interval(months => s.intro_months)
+            THEN s.intro_price
+        ELSE subscription_price_at(s.id, $2)
+    END
+    FROM subscriptions s
+    WHERE s.id = $1
+$$;
+
+DROP FUNCTION IF EXISTS subscription_active_at(UUID, DATE);
+DROP FUNCTION IF EXISTS subscription_paused_at(UUID, DATE);
+DROP TABLE IF EXISTS subscription_pauses;
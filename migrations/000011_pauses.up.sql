@@ -0,0 +1,44 @@
+-- паузы подписки: с start_month по end_month включительно подписка не оплачивается
+CREATE TABLE IF NOT EXISTS subscription_pauses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    start_month DATE NOT NULL,
+    -- NULL - пауза до возобновления
+    end_month DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (end_month IS NULL OR end_month >= start_month)
+);
+
+CREATE INDEX IF NOT EXISTS subscription_pauses_subscription_id_idx
+    ON subscription_pauses (subscription_id, start_month);
+
+CREATE OR REPLACE FUNCTION subscription_paused_at(UUID, DATE) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT EXISTS (
+        SELECT 1 FROM subscription_pauses p
+        WHERE p.subscription_id = $1 AND p.start_month <= $2 AND (p.end_month IS NULL OR p.end_month >= $2))
+$$;
+
+-- подписка $1 действует в месяце $2: месяц в пределах start_date..end_date и не на паузе
+CREATE OR REPLACE FUNCTION subscription_active_at(UUID, DATE) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT s.start_date <= $2 AND (s.end_date IS NULL OR s.end_date >= $2) AND NOT subscription_paused_at(s.id, $2)
+    FROM subscriptions s
+    WHERE s.id = $1
+$$;
+
+-- месяцы на паузе ничего не стоят
+CREATE OR REPLACE FUNCTION subscription_cost_at(UUID, DATE) RETURNS INT
+LANGUAGE sql STABLE AS $$
+    SELECT CASE
+        WHEN subscription_paused_at(s.id, $2) THEN 0
+        WHEN s.trial_ends_at IS NOT NULL AND $2 <= s.trial_ends_at THEN 0
+        WHEN s.intro_price IS NOT NULL
+            AND $2 < COALESCE((s.trial_ends_at + INTERVAL '1 month')::DATE, s.start_date)
+                + make_interval(months => s.intro_months)
+            THEN s.intro_price
+        ELSE subscription_price_at(s.id, $2)
+    END
+    FROM subscriptions s
+    WHERE s.id = $1
+$$;
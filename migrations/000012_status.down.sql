@@ -0,0 +1,10 @@
+DROP FUNCTION IF EXISTS subscription_status_at(UUID, DATE);
+
+DROP INDEX IF EXISTS subscriptions_status_idx;
+
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS subscriptions_status_check,
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS cancel_reason,
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,29 @@
+-- явный статус подписки: trial, active, paused, cancelled, expired
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active',
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS cancel_reason TEXT,
+    ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ;
+
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_status_check
+    CHECK (status IN ('trial', 'active', 'paused', 'cancelled', 'expired'));
+
+CREATE INDEX IF NOT EXISTS subscriptions_status_idx ON subscriptions (status);
+
+-- статус, который следует из дат и пауз подписки $1 в месяце $2.
+-- Подписка с auto_renew не истекает: ее продлевает задание автопродления
+CREATE OR REPLACE FUNCTION subscription_status_at(UUID, DATE) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+    SELECT CASE
+        WHEN s.end_date IS NOT NULL AND s.end_date < $2 AND NOT s.auto_renew THEN 'expired'
+        WHEN subscription_paused_at(s.id, $2) THEN 'paused'
+        WHEN s.trial_ends_at IS NOT NULL AND s.trial_ends_at >= $2 THEN 'trial'
+        ELSE 'active'
+    END
+    FROM subscriptions s
+    WHERE s.id = $1
+$$;
+
+UPDATE subscriptions s SET status = subscription_status_at(s.id, date_trunc('month', NOW() AT TIME ZONE u.timezone)::DATE)
+FROM users u
+WHERE u.id = s.user_id;
@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS subscription_share_at(UUID, UUID, DATE);
+
+DROP TABLE IF EXISTS subscription_members;
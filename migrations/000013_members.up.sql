@@ -0,0 +1,39 @@
+-- участники общей подписки: доля задается весом или фиксированной суммой в месяц
+CREATE TABLE IF NOT EXISTS subscription_members (
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    weight INT CHECK (weight > 0),
+    amount INT CHECK (amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, user_id),
+    CONSTRAINT subscription_members_share_check CHECK ((weight IS NULL) <> (amount IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS subscription_members_user_id_idx ON subscription_members (user_id);
+
+-- доля пользователя $2 в стоимости подписки $1 за месяц $3. Владелец участвует с весом 1, если его доля
+-- не задана явно. Фиксированные суммы вычитаются первыми (пропорционально уменьшаются, если их сумма
+-- больше стоимости месяца), остаток делится по весам. Если участников с весом нет, остаток платит владелец
+CREATE OR REPLACE FUNCTION subscription_share_at(UUID, UUID, DATE) RETURNS NUMERIC
+LANGUAGE sql STABLE AS $$
+    WITH parts AS (
+        SELECT m.user_id, m.weight, m.amount FROM subscription_members m
+        WHERE m.subscription_id = $1
+        UNION ALL
+        SELECT s.user_id, 1, NULL FROM subscriptions s
+        WHERE s.id = $1 AND NOT EXISTS (
+            SELECT 1 FROM subscription_members m WHERE m.subscription_id = s.id AND m.user_id = s.user_id)
+    ), k AS (
+        SELECT s.user_id AS owner, c.cost, t.fixed, t.weights,
+            (c.cost - LEAST(t.fixed, c.cost))::NUMERIC AS rest,
+            CASE WHEN t.fixed > c.cost THEN c.cost::NUMERIC / t.fixed ELSE 1 END AS scale
+        FROM subscriptions s
+        CROSS JOIN LATERAL (SELECT subscription_cost_at(s.id, $3) AS cost) c
+        CROSS JOIN LATERAL (SELECT COALESCE(SUM(amount), 0) AS fixed, COALESCE(SUM(weight), 0) AS weights FROM parts) t
+        WHERE s.id = $1
+    )
+    SELECT COALESCE(SUM(CASE WHEN p.amount IS NOT NULL THEN p.amount * k.scale
+            ELSE k.rest * p.weight / k.weights END), 0)
+        + COALESCE(MAX(CASE WHEN k.weights = 0 AND k.owner = $2 THEN k.rest END), 0)
+    FROM k LEFT JOIN parts p ON p.user_id = $2
+$$;
@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- поиск q= идет по lower(service_name): префикс через LIKE и нечеткое совпадение через триграммы
+CREATE INDEX IF NOT EXISTS subscriptions_service_name_trgm_idx
+    ON subscriptions USING GIN (lower(service_name) gin_trgm_ops);
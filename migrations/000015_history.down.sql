@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS subscription_members_history ON subscription_members;
+DROP TRIGGER IF EXISTS subscription_prices_history ON subscription_prices;
+DROP TRIGGER IF EXISTS subscription_tags_history ON subscription_tags;
+DROP TRIGGER IF EXISTS subscriptions_history ON subscriptions;
+
+DROP FUNCTION IF EXISTS subscriptions_history_trigger();
+DROP FUNCTION IF EXISTS subscription_history_price(JSONB, INT, DATE);
+DROP FUNCTION IF EXISTS subscription_snapshot(UUID);
+
+DROP TABLE IF EXISTS subscription_history;
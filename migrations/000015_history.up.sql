@@ -0,0 +1,112 @@
+-- версии строк подписок (системное время): версия действует с valid_from до valid_to, открытая - текущая.
+-- Вместе со строкой хранятся ее теги, периоды цен и участники, чтобы восстановить подписку на момент в прошлом
+CREATE TABLE IF NOT EXISTS subscription_history (
+    id UUID NOT NULL,
+    service_name TEXT NOT NULL,
+    price INT NOT NULL,
+    user_id UUID NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    service_id UUID,
+    category_id UUID,
+    auto_renew BOOLEAN NOT NULL,
+    renewal_months INT NOT NULL,
+    trial_ends_at DATE,
+    intro_price INT,
+    intro_months INT,
+    status TEXT NOT NULL,
+    cancel_reason TEXT,
+    cancelled_at TIMESTAMPTZ,
+    tags TEXT[] NOT NULL,
+    -- периоды цен: [{"effective_from": "2025-01-01", "price": 500}, ...]
+    prices JSONB NOT NULL,
+    -- участники: [{"user_id": "...", "weight": 1, "amount": null}, ...]
+    members JSONB NOT NULL,
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to TIMESTAMPTZ,
+    PRIMARY KEY (id, valid_from),
+    CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS subscription_history_valid_idx ON subscription_history (valid_from, valid_to);
+CREATE UNIQUE INDEX IF NOT EXISTS subscription_history_current_key ON subscription_history (id) WHERE valid_to IS NULL;
+
+-- закрывает текущую версию подписки $1 и записывает новую из текущего состояния, если подписка существует.
+-- Несколько изменений в одной транзакции дают одну версию: NOW() - время начала транзакции
+CREATE OR REPLACE FUNCTION subscription_snapshot(UUID) RETURNS VOID
+LANGUAGE sql AS $$
+    DELETE FROM subscription_history WHERE id = $1 AND valid_to IS NULL AND valid_from = NOW();
+    UPDATE subscription_history SET valid_to = NOW() WHERE id = $1 AND valid_to IS NULL;
+    INSERT INTO subscription_history (id, service_name, price, user_id, start_date, end_date, service_id, category_id,
+        auto_renew, renewal_months, trial_ends_at, intro_price, intro_months, status, cancel_reason, cancelled_at,
+        tags, prices, members, valid_from)
+    SELECT s.id, s.service_name, s.price::INT, s.user_id, s.start_date, s.end_date, s.service_id, s.category_id,
+        s.auto_renew, s.renewal_months, s.trial_ends_at, s.intro_price, s.intro_months, s.status, s.cancel_reason, s.cancelled_at,
+        ARRAY(SELECT t.name FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+            WHERE st.subscription_id = s.id ORDER BY t.name),
+        COALESCE((SELECT jsonb_agg(jsonb_build_object('effective_from', p.effective_from, 'price', p.price) ORDER BY p.effective_from)
+            FROM subscription_prices p WHERE p.subscription_id = s.id), '[]'::JSONB),
+        COALESCE((SELECT jsonb_agg(jsonb_build_object('user_id', m.user_id, 'weight', m.weight, 'amount', m.amount) ORDER BY m.user_id)
+            FROM subscription_members m WHERE m.subscription_id = s.id), '[]'::JSONB),
+        NOW()
+    FROM subscriptions s WHERE s.id = $1;
+$$;
+
+-- цена версии в месяце $3, как subscription_price_at по периодам цен версии
+CREATE OR REPLACE FUNCTION subscription_history_price(JSONB, INT, DATE) RETURNS INT
+LANGUAGE sql IMMUTABLE AS $$
+    SELECT COALESCE(
+        (SELECT (p->>'price')::INT FROM jsonb_array_elements($1) p
+            ORDER BY (p->>'effective_from')::DATE <= $3 DESC,
+                CASE WHEN (p->>'effective_from')::DATE <= $3 THEN (p->>'effective_from')::DATE END DESC,
+                (p->>'effective_from')::DATE
+            LIMIT 1),
+        $2)
+$$;
+
+CREATE OR REPLACE FUNCTION subscriptions_history_trigger() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF TG_TABLE_NAME = 'subscriptions' THEN
+            UPDATE subscription_history SET valid_to = NOW() WHERE id = OLD.id AND valid_to IS NULL AND valid_from < NOW();
+            DELETE FROM subscription_history WHERE id = OLD.id AND valid_to IS NULL;
+        ELSE
+            PERFORM subscription_snapshot(OLD.subscription_id);
+        END IF;
+        RETURN OLD;
+    END IF;
+
+    IF TG_TABLE_NAME = 'subscriptions' THEN
+        PERFORM subscription_snapshot(NEW.id);
+    ELSE
+        PERFORM subscription_snapshot(NEW.subscription_id);
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER subscriptions_history AFTER INSERT OR UPDATE OR DELETE ON subscriptions
+    FOR EACH ROW EXECUTE FUNCTION subscriptions_history_trigger();
+CREATE TRIGGER subscription_tags_history AFTER INSERT OR DELETE ON subscription_tags
+    FOR EACH ROW EXECUTE FUNCTION subscriptions_history_trigger();
+CREATE TRIGGER subscription_prices_history AFTER INSERT OR UPDATE OR DELETE ON subscription_prices
+    FOR EACH ROW EXECUTE FUNCTION subscriptions_history_trigger();
+CREATE TRIGGER subscription_members_history AFTER INSERT OR UPDATE OR DELETE ON subscription_members
+    FOR EACH ROW EXECUTE FUNCTION subscriptions_history_trigger();
+
+-- история начинается с текущего состояния
+INSERT INTO subscription_history (id, service_name, price, user_id, start_date, end_date, service_id, category_id,
+    auto_renew, renewal_months, trial_ends_at, intro_price, intro_months, status, cancel_reason, cancelled_at,
+    tags, prices, members, valid_from)
+SELECT s.id, s.service_name, s.price::INT, s.user_id, s.start_date, s.end_date, s.service_id, s.category_id,
+    s.auto_renew, s.renewal_months, s.trial_ends_at, s.intro_price, s.intro_months, s.status, s.cancel_reason, s.cancelled_at,
+    ARRAY(SELECT t.name FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+        WHERE st.subscription_id = s.id ORDER BY t.name),
+    COALESCE((SELECT jsonb_agg(jsonb_build_object('effective_from', p.effective_from, 'price', p.price) ORDER BY p.effective_from)
+        FROM subscription_prices p WHERE p.subscription_id = s.id), '[]'::JSONB),
+    COALESCE((SELECT jsonb_agg(jsonb_build_object('user_id', m.user_id, 'weight', m.weight, 'amount', m.amount) ORDER BY m.user_id)
+        FROM subscription_members m WHERE m.subscription_id = s.id), '[]'::JSONB),
+    LEAST(COALESCE(s.updated_at, NOW()), NOW())
+FROM subscriptions s
+ON CONFLICT DO NOTHING;
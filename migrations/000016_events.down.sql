@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS subscription_events_no_truncate ON subscription_events;
+DROP TRIGGER IF EXISTS subscription_events_append_only ON subscription_events;
+
+DROP FUNCTION IF EXISTS subscription_events_append_only();
+
+DROP TABLE IF EXISTS subscription_events;
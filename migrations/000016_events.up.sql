@@ -0,0 +1,25 @@
+-- журнал событий подписок для EventStore: только дополняется, version растет с 1 для каждой подписки
+CREATE TABLE IF NOT EXISTS subscription_events (
+    seq BIGSERIAL PRIMARY KEY,
+    subscription_id UUID NOT NULL,
+    version INT NOT NULL CHECK (version > 0),
+    type TEXT NOT NULL CHECK (type IN (
+        'created', 'imported', 'price_changed', 'dates_changed', 'service_changed', 'category_changed',
+        'owner_changed', 'renewal_changed', 'intro_changed', 'tags_changed', 'status_changed',
+        'cancelled', 'reactivated', 'paused', 'resumed', 'renewed', 'member_set', 'member_removed', 'deleted')),
+    data JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (subscription_id, version)
+);
+
+CREATE OR REPLACE FUNCTION subscription_events_append_only() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    RAISE EXCEPTION 'subscription_events is append-only';
+END;
+$$;
+
+CREATE TRIGGER subscription_events_append_only BEFORE UPDATE OR DELETE ON subscription_events
+    FOR EACH ROW EXECUTE FUNCTION subscription_events_append_only();
+CREATE TRIGGER subscription_events_no_truncate BEFORE TRUNCATE ON subscription_events
+    FOR EACH STATEMENT EXECUTE FUNCTION subscription_events_append_only();
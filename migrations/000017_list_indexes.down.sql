@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS subscriptions_service_name_idx;
+DROP INDEX IF EXISTS subscriptions_start_date_id_idx;
+DROP INDEX IF EXISTS subscriptions_user_service_start_idx;
@@ -0,0 +1,9 @@
+-- список с фильтром по владельцу и сервису: первый столбец обслуживает и фильтр только по user_id
+CREATE INDEX IF NOT EXISTS subscriptions_user_service_start_idx
+    ON subscriptions (user_id, lower(service_name), start_date, id);
+
+-- порядок списка (start_date, id): первая и следующие страницы по курсору читаются по индексу
+CREATE INDEX IF NOT EXISTS subscriptions_start_date_id_idx ON subscriptions (start_date, id);
+
+-- фильтр service_name без q сравнивает lower(service_name) на равенство
+CREATE INDEX IF NOT EXISTS subscriptions_service_name_idx ON subscriptions (lower(service_name));
@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations applied by storage on startup
+package migrations
+
+import "embed"
+
+// FS содержит файлы вида 000001_name.up.sql / 000001_name.down.sql
+//
+//go:embed *.sql
+var FS embed.FS
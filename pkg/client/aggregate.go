@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// AggregateResponse - таблица Aggregate: по значению в Rows на каждый столбец Columns
+type AggregateResponse struct {
+	Columns []AggregateColumn `json:"columns"`
+	Rows    [][]any           `json:"rows"`
+}
+
+// AggregateColumn - столбец таблицы Aggregate, Type - string, uuid или number
+type AggregateColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// группировка Aggregate по месяцу начала, остальные - GroupByService, GroupByUser и GroupByCategory
+const GroupByStartMonth = "start_month"
+
+// метрики Aggregate
+const (
+	MetricCount    = "count"
+	MetricSumPrice = "sum_price"
+	MetricAvgPrice = "avg_price"
+	MetricMinPrice = "min_price"
+	MetricMaxPrice = "max_price"
+)
+
+// Aggregate считает metrics по подпискам, подходящим под filter, в разрезе groupBy.
+// Числа в строках ответа приходят как float64, как и любые числа JSON
+func (c *Client) Aggregate(ctx context.Context, filter ListFilter, groupBy, metrics []string) (*AggregateResponse, error) {
+	query := listQuery(filter)
+	if len(groupBy) > 0 {
+		query.Set("group_by", strings.Join(groupBy, ","))
+	}
+	if len(metrics) > 0 {
+		query.Set("metrics", strings.Join(metrics, ","))
+	}
+
+	var resp AggregateResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionsPath+"/aggregate", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
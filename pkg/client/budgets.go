@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const budgetsPath = "/api/v1/budgets"
+
+// Budget - месячный бюджет пользователя, без CategoryID - на все его подписки
+type Budget struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	Currency   string     `json:"currency"`
+	Amount     int        `json:"amount"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// BudgetCreateRequest - новый бюджет; пустой Currency - валюта пользователя
+type BudgetCreateRequest struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	Currency   string     `json:"currency,omitempty"`
+	Amount     int        `json:"amount"`
+}
+
+// BudgetUpdateRequest - изменение бюджета, пустые поля не меняются
+type BudgetUpdateRequest struct {
+	Currency string `json:"currency,omitempty"`
+	Amount   int    `json:"amount,omitempty"`
+}
+
+// BudgetStatus - расход по бюджету за месяц и сработавшие пороги
+type BudgetStatus struct {
+	Budget    Budget        `json:"budget"`
+	Month     string        `json:"month"`
+	Spent     int           `json:"spent"`
+	Projected int           `json:"projected"`
+	Percent   int           `json:"percent"`
+	Threshold int           `json:"threshold"`
+	Alerts    []BudgetAlert `json:"alerts"`
+}
+
+// BudgetAlert - пересечение порога бюджета в месяце
+type BudgetAlert struct {
+	BudgetID   uuid.UUID  `json:"budget_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	Month      string     `json:"month"`
+	Threshold  int        `json:"threshold"`
+	Spent      int        `json:"spent"`
+	Amount     int        `json:"amount"`
+	Currency   string     `json:"currency"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type budgetsListResponse struct {
+	Budgets []Budget `json:"budgets"`
+}
+
+func budgetPath(id uuid.UUID) string {
+	return budgetsPath + "/" + id.String()
+}
+
+func (c *Client) CreateBudget(ctx context.Context, req BudgetCreateRequest) (*Budget, error) {
+	var budget Budget
+	if err := c.do(ctx, http.MethodPost, budgetsPath, nil, req, &budget); err != nil {
+		return nil, err
+	}
+
+	return &budget, nil
+}
+
+func (c *Client) GetBudget(ctx context.Context, id uuid.UUID) (*Budget, error) {
+	var budget Budget
+	if err := c.do(ctx, http.MethodGet, budgetPath(id), nil, nil, &budget); err != nil {
+		return nil, err
+	}
+
+	return &budget, nil
+}
+
+// ListBudgets возвращает бюджеты пользователя userID или все, если userID нулевой
+func (c *Client) ListBudgets(ctx context.Context, userID uuid.UUID) ([]Budget, error) {
+	query := url.Values{}
+	if userID != uuid.Nil {
+		query.Set("user_id", userID.String())
+	}
+
+	var resp budgetsListResponse
+	if err := c.do(ctx, http.MethodGet, budgetsPath, query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Budgets, nil
+}
+
+func (c *Client) UpdateBudget(ctx context.Context, id uuid.UUID, req BudgetUpdateRequest) (*Budget, error) {
+	var budget Budget
+	if err := c.do(ctx, http.MethodPatch, budgetPath(id), nil, req, &budget); err != nil {
+		return nil, err
+	}
+
+	return &budget, nil
+}
+
+func (c *Client) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, budgetPath(id), nil, nil, nil)
+}
+
+// BudgetStatus возвращает расход по бюджету за месяц (YYYY-MM); пустой month - текущий месяц
+func (c *Client) BudgetStatus(ctx context.Context, id uuid.UUID, month string) (*BudgetStatus, error) {
+	query := url.Values{}
+	if month != "" {
+		query.Set("month", month)
+	}
+
+	var status BudgetStatus
+	if err := c.do(ctx, http.MethodGet, budgetPath(id)+"/status", query, nil, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const categoriesPath = "/api/v1/categories"
+
+// Category - категория подписок, без ParentID - корневая
+type Category struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type CategoryCreateRequest struct {
+	Name     string     `json:"name"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+// CategoryUpdateRequest - изменение категории, пустые поля не меняются
+type CategoryUpdateRequest struct {
+	Name     string     `json:"name,omitempty"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+type categoriesListResponse struct {
+	Categories []Category `json:"categories"`
+}
+
+func categoryPath(id uuid.UUID) string {
+	return categoriesPath + "/" + id.String()
+}
+
+func (c *Client) CreateCategory(ctx context.Context, req CategoryCreateRequest) (*Category, error) {
+	var category Category
+	if err := c.do(ctx, http.MethodPost, categoriesPath, nil, req, &category); err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (c *Client) GetCategory(ctx context.Context, id uuid.UUID) (*Category, error) {
+	var category Category
+	if err := c.do(ctx, http.MethodGet, categoryPath(id), nil, nil, &category); err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// ListCategories возвращает все категории плоским списком, дерево строится по ParentID
+func (c *Client) ListCategories(ctx context.Context) ([]Category, error) {
+	var resp categoriesListResponse
+	if err := c.do(ctx, http.MethodGet, categoriesPath, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Categories, nil
+}
+
+// UpdateCategory переименовывает и/или переносит категорию; нулевой ParentID делает ее корневой
+func (c *Client) UpdateCategory(ctx context.Context, id uuid.UUID, req CategoryUpdateRequest) (*Category, error) {
+	var category Category
+	if err := c.do(ctx, http.MethodPatch, categoryPath(id), nil, req, &category); err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (c *Client) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, categoryPath(id), nil, nil, nil)
+}
@@ -0,0 +1,201 @@
+// Package client is a typed Go client for the subscriptions HTTP API (/api/v1)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout    = 30 * time.Second
+	defaultMaxRetries = 3
+	defaultBackoff    = 200 * time.Millisecond
+	maxBackoff        = 5 * time.Second
+)
+
+// Client - клиент HTTP API сервиса подписок. Безопасен для конкурентного использования
+type Client struct {
+	baseURL    *url.URL
+	apiKey     string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+	// conflictPolicy передается в conflict_policy при записи подписки
+	conflictPolicy string
+}
+
+// Option настраивает Client в New
+type Option func(*Client)
+
+// WithHTTPClient подменяет http.Client (транспорт, таймауты, прокси)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAPIKey передает ключ в заголовке Authorization: Bearer
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithRetries задает число повторов и базовую задержку экспоненциального backoff.
+// maxRetries = 0 отключает повторы
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// WithConflictPolicy задает политику пересечения с подписками того же пользователя на тот же сервис
+// при создании и изменении подписок: ConflictPolicyWarn (по умолчанию на сервере) или ConflictPolicyReject
+func WithConflictPolicy(policy string) Option {
+	return func(c *Client) {
+		c.conflictPolicy = policy
+	}
+}
+
+// New создает клиент для сервера baseURL, например "http://localhost:8080"
+func New(baseURL string, opts ...Option) (*Client, error) {
+	const op = "client.New"
+
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%s: unsupported scheme %q", op, u.Scheme)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// do выполняет запрос и декодирует JSON-ответ в out (если out != nil).
+// Идемпотентные запросы (все, кроме POST) повторяются при 5xx и сетевых ошибках
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var payload []byte
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		payload = raw
+	}
+
+	target := c.baseURL.JoinPath(path)
+	target.RawQuery = query.Encode()
+
+	retries := c.maxRetries
+	if method == http.MethodPost {
+		retries = 0
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.backoffFor(attempt)); err != nil {
+				return errors.Join(lastErr, err)
+			}
+		}
+
+		var retry bool
+		retry, lastErr = c.once(ctx, method, target.String(), payload, out)
+		if lastErr == nil || !retry {
+			return lastErr
+		}
+	}
+
+	return lastErr
+}
+
+// once делает одну попытку. retry = true, если ошибку имеет смысл повторить
+func (c *Client) once(ctx context.Context, method, target string, payload []byte, out any) (bool, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return true, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return true, fmt.Errorf("%w: failed to read response: %v", ErrUnavailable, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := newAPIError(resp.StatusCode, raw)
+		return resp.StatusCode >= 500, apiErr
+	}
+
+	if out == nil || len(raw) == 0 {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return false, nil
+}
+
+// backoffFor - экспоненциальная задержка с полным jitter
+func (c *Client) backoffFor(attempt int) time.Duration {
+	d := c.backoff << (attempt - 1)
+	if d <= 0 || d > maxBackoff {
+		d = maxBackoff
+	}
+
+	return time.Duration(rand.Int64N(int64(d)) + 1)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
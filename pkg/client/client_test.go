@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// countingServer отвечает статусами statuses по очереди, последним - на все остальные запросы
+func countingServer(t *testing.T, statuses ...int) (*Client, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_ = json.NewEncoder(w).Encode(Subscription{ID: uuid.New(), ServiceName: "Netflix"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": http.StatusText(status)})
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, WithRetries(3, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c, &calls
+}
+
+func TestRetriesServerErrors(t *testing.T) {
+	c, calls := countingServer(t, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK)
+
+	sub, err := c.GetSubscription(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.ServiceName != "Netflix" {
+		t.Errorf("subscription: %+v", sub)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls: got %d, want 3", got)
+	}
+}
+
+func TestRetriesGiveUp(t *testing.T) {
+	c, calls := countingServer(t, http.StatusInternalServerError)
+
+	err := c.DeleteSubscription(context.Background(), uuid.New())
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("got %v, want ErrServer", err)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Errorf("calls: got %d, want 1 + 3 retries", got)
+	}
+}
+
+func TestDoesNotRetry(t *testing.T) {
+	t.Run("POST", func(t *testing.T) {
+		c, calls := countingServer(t, http.StatusInternalServerError, http.StatusOK)
+
+		_, err := c.CreateSubscription(context.Background(), SubscriptionCreateRequest{ServiceName: "Netflix", Price: 500})
+		if !errors.Is(err, ErrServer) {
+			t.Errorf("got %v, want ErrServer", err)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Errorf("calls: got %d, want 1", got)
+		}
+	})
+
+	t.Run("4xx", func(t *testing.T) {
+		c, calls := countingServer(t, http.StatusNotFound, http.StatusOK)
+
+		_, err := c.GetSubscription(context.Background(), uuid.New())
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("got %v, want ErrNotFound", err)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Errorf("calls: got %d, want 1", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		c, calls := countingServer(t, http.StatusBadGateway, http.StatusOK)
+		WithRetries(0, time.Millisecond)(c)
+
+		if _, err := c.GetSubscription(context.Background(), uuid.New()); !errors.Is(err, ErrServer) {
+			t.Errorf("got %v, want ErrServer", err)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Errorf("calls: got %d, want 1", got)
+		}
+	})
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	c, calls := countingServer(t, http.StatusServiceUnavailable)
+	WithRetries(3, time.Hour)(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := c.DeleteSubscription(ctx, uuid.New())
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrServer) {
+		t.Errorf("got %v, want the last server error and the context error", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls: got %d, want 1", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := &Client{backoff: 100 * time.Millisecond}
+
+	for attempt, limit := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		3:  400 * time.Millisecond,
+		10: maxBackoff,
+		70: maxBackoff,
+	} {
+		for range 100 {
+			if d := c.backoffFor(attempt); d <= 0 || d > limit {
+				t.Fatalf("attempt %d: backoff %v outside (0, %v]", attempt, d, limit)
+			}
+		}
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	var auth, policy, contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, policy, contentType = r.Header.Get("Authorization"), r.URL.Query().Get("conflict_policy"), r.Header.Get("Content-Type")
+		_ = json.NewEncoder(w).Encode(Subscription{})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL+"/", WithAPIKey("secret"), WithConflictPolicy(ConflictPolicyReject))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateSubscription(context.Background(), SubscriptionCreateRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if auth != "Bearer secret" || policy != ConflictPolicyReject || contentType != "application/json" {
+		t.Errorf("got Authorization %q, conflict_policy %q, Content-Type %q", auth, policy, contentType)
+	}
+
+	if _, err := New("ftp://localhost"); err == nil {
+		t.Error("New accepted an ftp URL")
+	}
+}
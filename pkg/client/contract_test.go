@@ -0,0 +1,122 @@
+package client
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/odlev/subscriptions/internal/storage"
+)
+
+// jsonFields - поля JSON типа t с их опциями, как их видит encoding/json
+func jsonFields(t reflect.Type) []string {
+	var fields []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		fields = append(fields, tag)
+	}
+	slices.Sort(fields)
+
+	return fields
+}
+
+// TestTypesMatchServer проверяет, что типы клиента повторяют JSON типов сервера:
+// пакет не импортирует storage, поэтому расхождение ловит только этот тест
+func TestTypesMatchServer(t *testing.T) {
+	pairs := []struct {
+		client, server any
+	}{
+		{Subscription{}, storage.SubscriptionR{}},
+		{SubscriptionCreateRequest{}, storage.SubscriptionCreateRequest{}},
+		{UpdateSubscriptionRequest{}, storage.UpdateSubscriptionRequest{}},
+		{ParamError{}, storage.ParamError{}},
+		{TotalCostResponse{}, storage.TotalCostResponse{}},
+		{CostGroup{}, storage.CostGroup{}},
+		{subscriptionsListResponse{}, storage.SubscriptionsListResponse{}},
+		{AggregateResponse{}, storage.AggregateResponse{}},
+		{AggregateColumn{}, storage.AggregateColumn{}},
+		{Budget{}, storage.Budget{}},
+		{BudgetCreateRequest{}, storage.BudgetCreateRequest{}},
+		{BudgetUpdateRequest{}, storage.BudgetUpdateRequest{}},
+		{BudgetStatus{}, storage.BudgetStatus{}},
+		{BudgetAlert{}, storage.BudgetAlert{}},
+		{budgetsListResponse{}, storage.BudgetsListResponse{}},
+		{Category{}, storage.Category{}},
+		{CategoryCreateRequest{}, storage.CategoryCreateRequest{}},
+		{CategoryUpdateRequest{}, storage.CategoryUpdateRequest{}},
+		{categoriesListResponse{}, storage.CategoriesListResponse{}},
+		{ForecastResponse{}, storage.ForecastResponse{}},
+		{ForecastMonth{}, storage.ForecastMonth{}},
+		{ForecastSubscription{}, storage.ForecastSubscription{}},
+		{Member{}, storage.Member{}},
+		{MemberRequest{}, storage.MemberRequest{}},
+		{membersListResponse{}, storage.MembersListResponse{}},
+		{Overlap{}, storage.Overlap{}},
+		{OverlapPair{}, storage.OverlapPair{}},
+		{overlapsReportResponse{}, storage.OverlapsReportResponse{}},
+		{Pause{}, storage.Pause{}},
+		{PauseRequest{}, storage.PauseRequest{}},
+		{ResumeRequest{}, storage.ResumeRequest{}},
+		{pausesListResponse{}, storage.PausesListResponse{}},
+		{SubscriptionPrice{}, storage.SubscriptionPrice{}},
+		{pricesListResponse{}, storage.PricesListResponse{}},
+		{Renewal{}, storage.Renewal{}},
+		{renewalsListResponse{}, storage.RenewalsListResponse{}},
+		{serviceNamesResponse{}, storage.ServiceNamesResponse{}},
+		{Service{}, storage.Service{}},
+		{ServiceCreateRequest{}, storage.ServiceCreateRequest{}},
+		{ServiceUpdateRequest{}, storage.ServiceUpdateRequest{}},
+		{servicesListResponse{}, storage.ServicesListResponse{}},
+		{CancelRequest{}, storage.CancelRequest{}},
+		{ReactivateRequest{}, storage.ReactivateRequest{}},
+		{Tag{}, storage.Tag{}},
+		{tagRequest{}, storage.TagRequest{}},
+		{tagsListResponse{}, storage.TagsListResponse{}},
+		{subscriptionTagsRequest{}, storage.SubscriptionTagsRequest{}},
+		{TimeSeriesResponse{}, storage.TimeSeriesResponse{}},
+		{TimeSeriesPoint{}, storage.TimeSeriesPoint{}},
+		{TimeSeriesGroup{}, storage.TimeSeriesGroup{}},
+		{User{}, storage.User{}},
+		{UserCreateRequest{}, storage.UserCreateRequest{}},
+		{UserUpdateRequest{}, storage.UserUpdateRequest{}},
+		{usersListResponse{}, storage.UsersListResponse{}},
+	}
+
+	for _, p := range pairs {
+		client, server := reflect.TypeOf(p.client), reflect.TypeOf(p.server)
+		if got, want := jsonFields(client), jsonFields(server); !slices.Equal(got, want) {
+			t.Errorf("%s: fields %s, server %s has %s", client.Name(), strings.Join(got, " "), server.Name(), strings.Join(want, " "))
+		}
+	}
+
+	for _, c := range []struct{ client, server string }{
+		{GroupByCategory, storage.GroupByCategory},
+		{GroupByService, storage.GroupByService},
+		{GroupByUser, storage.GroupByUser},
+		{GroupByStartMonth, storage.GroupByStartMonth},
+		{MetricCount, storage.MetricCount},
+		{MetricSumPrice, storage.MetricSumPrice},
+		{MetricAvgPrice, storage.MetricAvgPrice},
+		{MetricMinPrice, storage.MetricMinPrice},
+		{MetricMaxPrice, storage.MetricMaxPrice},
+		{ForecastRenewalAuto, storage.ForecastRenewalAuto},
+		{ForecastRenewalAll, storage.ForecastRenewalAll},
+		{ForecastRenewalNone, storage.ForecastRenewalNone},
+		{ConflictPolicyWarn, storage.ConflictPolicyWarn},
+		{ConflictPolicyReject, storage.ConflictPolicyReject},
+		{StatusTrial, storage.StatusTrial},
+		{StatusActive, storage.StatusActive},
+		{StatusPaused, storage.StatusPaused},
+		{StatusCancelled, storage.StatusCancelled},
+		{StatusExpired, storage.StatusExpired},
+	} {
+		if c.client != c.server {
+			t.Errorf("constant %q, server has %q", c.client, c.server)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+var (
+	// ErrNotFound - сервер ответил 404
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidRequest - сервер отклонил запрос (400)
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrConflict - сервер ответил 409
+	ErrConflict = errors.New("conflict")
+	// ErrServer - сервер ответил 5xx
+	ErrServer = errors.New("server error")
+	// ErrUnavailable - запрос не дошел до сервера
+	ErrUnavailable = errors.New("server unavailable")
+)
+
+// knownErrors - ошибки myerrors, которые сервер возвращает дословно в поле error
+var knownErrors = []error{
+	myerrors.ErrNotFound,
+	myerrors.ErrInvalidDateRange,
+	myerrors.ErrInvalidPeriod,
+	myerrors.ErrUserNotFound,
+	myerrors.ErrUserRequired,
+	myerrors.ErrUnknownUser,
+	myerrors.ErrUserExists,
+	myerrors.ErrUserHasSubscriptions,
+	myerrors.ErrInvalidTimezone,
+	myerrors.ErrServiceNotFound,
+	myerrors.ErrServiceRequired,
+	myerrors.ErrUnknownService,
+	myerrors.ErrServiceExists,
+	myerrors.ErrCategoryNotFound,
+	myerrors.ErrUnknownCategory,
+	myerrors.ErrCategoryExists,
+	myerrors.ErrCategoryCycle,
+	myerrors.ErrCategoryHasChildren,
+	myerrors.ErrInvalidGroupBy,
+	myerrors.ErrTagNotFound,
+	myerrors.ErrTagExists,
+	myerrors.ErrInvalidTag,
+	myerrors.ErrBudgetNotFound,
+	myerrors.ErrBudgetExists,
+	myerrors.ErrInvalidRenewalTerm,
+	myerrors.ErrInvalidTrial,
+	myerrors.ErrInvalidIntro,
+	myerrors.ErrInvalidPause,
+	myerrors.ErrPauseOverlap,
+	myerrors.ErrNotPaused,
+	myerrors.ErrInvalidStatus,
+	myerrors.ErrInvalidTransition,
+	myerrors.ErrInvalidCancelDate,
+	myerrors.ErrMemberNotFound,
+	myerrors.ErrInvalidShare,
+	myerrors.ErrPeriodTooLong,
+	myerrors.ErrInvalidHorizon,
+	myerrors.ErrInvalidRenewalMode,
+	myerrors.ErrSearchQueryRequired,
+	myerrors.ErrInvalidLimit,
+	myerrors.ErrInvalidMetric,
+	myerrors.ErrInvalidAsOf,
+	myerrors.ErrSubscriptionOverlap,
+	myerrors.ErrInvalidConflictPolicy,
+	myerrors.ErrProjectionDrift,
+}
+
+// APIError - ответ сервера с кодом не 2xx.
+// Проверяется через errors.Is как на ошибки этого пакета (ErrNotFound, ErrServer, ...),
+// так и на ошибки pkg/myerrors, если сервер вернул их текст
+type APIError struct {
+	StatusCode int
+	Message    string
+	// Params - ошибки отдельных параметров фильтра, если сервер их вернул
+	Params []ParamError
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("server responded %d: %s", e.StatusCode, e.Message)
+}
+
+func (e *APIError) Unwrap() []error {
+	var errs []error
+
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		errs = append(errs, ErrNotFound)
+	case e.StatusCode == http.StatusBadRequest:
+		errs = append(errs, ErrInvalidRequest)
+	case e.StatusCode == http.StatusConflict:
+		errs = append(errs, ErrConflict)
+	case e.StatusCode >= 500:
+		errs = append(errs, ErrServer)
+	}
+
+	for _, known := range knownErrors {
+		if e.Message == known.Error() {
+			errs = append(errs, known)
+		}
+		for _, p := range e.Params {
+			if p.Message == known.Error() && p.Message != e.Message {
+				errs = append(errs, known)
+			}
+		}
+	}
+
+	return errs
+}
+
+// newAPIError разбирает тело ответа вида {"error": "...", "params": [...]}; если это не JSON, берет тело целиком
+func newAPIError(status int, body []byte) *APIError {
+	var payload struct {
+		Error  string       `json:"error"`
+		Params []ParamError `json:"params"`
+	}
+
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+		message = payload.Error
+	}
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	return &APIError{StatusCode: status, Message: message, Params: payload.Params}
+}
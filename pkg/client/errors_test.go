@@ -0,0 +1,70 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/odlev/subscriptions/pkg/myerrors"
+)
+
+func TestAPIErrorUnwrap(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		is     []error
+		isNot  []error
+	}{
+		{"not found", http.StatusNotFound, `{"error": "subscription not found"}`,
+			[]error{ErrNotFound, myerrors.ErrNotFound}, []error{ErrServer, ErrInvalidRequest}},
+		{"validation", http.StatusBadRequest, `{"error": "end_date can not be earlier than start_date"}`,
+			[]error{ErrInvalidRequest, myerrors.ErrInvalidDateRange}, []error{ErrNotFound}},
+		{"filter params", http.StatusBadRequest,
+			`{"error": "invalid filter", "params": [{"param": "status", "error": "status must be one of trial, active, paused, cancelled, expired"}]}`,
+			[]error{ErrInvalidRequest, myerrors.ErrInvalidStatus}, []error{myerrors.ErrInvalidTag}},
+		{"conflict", http.StatusConflict, `{"error": "subscription does not match its event log, run cmd/projections"}`,
+			[]error{ErrConflict, myerrors.ErrProjectionDrift}, []error{ErrInvalidRequest}},
+		{"unknown message", http.StatusConflict, `{"error": "something else"}`,
+			[]error{ErrConflict}, []error{myerrors.ErrNotFound}},
+		{"server error", http.StatusBadGateway, `<html>bad gateway</html>`,
+			[]error{ErrServer}, []error{ErrConflict}},
+		{"other status", http.StatusUnauthorized, ``,
+			nil, []error{ErrServer, ErrInvalidRequest, ErrNotFound, ErrConflict}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := error(newAPIError(tt.status, []byte(tt.body)))
+			for _, target := range tt.is {
+				if !errors.Is(err, target) {
+					t.Errorf("%v is not %v", err, target)
+				}
+			}
+			for _, target := range tt.isNot {
+				if errors.Is(err, target) {
+					t.Errorf("%v is %v", err, target)
+				}
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("errors.As: %+v", apiErr)
+			}
+		})
+	}
+}
+
+func TestNewAPIErrorMessage(t *testing.T) {
+	for _, tt := range []struct {
+		body, want string
+	}{
+		{`{"error": "tag already exists"}`, "tag already exists"},
+		{"  upstream timeout\n", "upstream timeout"},
+		{``, http.StatusText(http.StatusServiceUnavailable)},
+	} {
+		if got := newAPIError(http.StatusServiceUnavailable, []byte(tt.body)).Message; got != tt.want {
+			t.Errorf("body %q: got message %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
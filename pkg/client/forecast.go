@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// ForecastResponse - прогноз расходов за From..To по месяцам и по подпискам
+type ForecastResponse struct {
+	From          string                 `json:"from"`
+	To            string                 `json:"to"`
+	Renewal       string                 `json:"renewal"`
+	Total         int                    `json:"total"`
+	Months        []ForecastMonth        `json:"months"`
+	Subscriptions []ForecastSubscription `json:"subscriptions"`
+}
+
+// ForecastMonth - прогноз на месяц
+type ForecastMonth struct {
+	Month         string `json:"month"`
+	Total         int    `json:"total"`
+	Subscriptions int    `json:"subscriptions"`
+}
+
+// ForecastSubscription - вклад подписки в прогноз, RenewalDates - месяцы продлений в горизонте
+type ForecastSubscription struct {
+	ID           uuid.UUID `json:"id"`
+	ServiceName  string    `json:"service_name"`
+	UserID       uuid.UUID `json:"user_id"`
+	EndDate      string    `json:"end_date,omitempty"`
+	Renews       bool      `json:"renews"`
+	RenewalDates []string  `json:"renewal_dates,omitempty"`
+	Total        int       `json:"total"`
+	Months       int       `json:"months"`
+}
+
+// режимы продления ForecastFilter.Renewal
+const (
+	ForecastRenewalAuto = "auto"
+	ForecastRenewalAll  = "all"
+	ForecastRenewalNone = "none"
+)
+
+// ForecastFilter - горизонт, режим продления и фильтры прогноза, пустые поля не применяются
+type ForecastFilter struct {
+	// From - первый месяц YYYY-MM, по умолчанию текущий
+	From string
+	// Months - горизонт в месяцах, 0 - значение сервера по умолчанию (12)
+	Months int
+	// Renewal - ForecastRenewalAuto (по умолчанию), ForecastRenewalAll или ForecastRenewalNone
+	Renewal     string
+	UserID      uuid.UUID
+	ServiceName string
+	CategoryID  uuid.UUID
+	Tags        []string
+	Statuses    []string
+}
+
+// Forecast прогнозирует расходы по месяцам и подпискам
+func (c *Client) Forecast(ctx context.Context, filter ForecastFilter) (*ForecastResponse, error) {
+	query := url.Values{}
+	setFilter(query, filter.UserID, filter.ServiceName, filter.CategoryID, filter.Tags, filter.Statuses)
+	if filter.From != "" {
+		query.Set("from", filter.From)
+	}
+	if filter.Months != 0 {
+		query.Set("months", strconv.Itoa(filter.Months))
+	}
+	if filter.Renewal != "" {
+		query.Set("renewal", filter.Renewal)
+	}
+
+	var resp ForecastResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionsPath+"/forecast", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
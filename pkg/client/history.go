@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetSubscriptionAsOf возвращает подписку в том виде, в каком она была в момент asOf.
+// ErrNotFound - подписки в этот момент не было
+func (c *Client) GetSubscriptionAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*Subscription, error) {
+	query := url.Values{"as_of": {asOf.Format(time.RFC3339Nano)}}
+
+	var sub Subscription
+	if err := c.do(ctx, http.MethodGet, subscriptionPath(id), query, nil, &sub); err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Member - участник общей подписки с долей по весу (Weight) или фиксированной суммой (Amount)
+type Member struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Weight    *int      `json:"weight,omitempty"`
+	Amount    *int      `json:"amount,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MemberRequest - доля участника, задается ровно одно из полей
+type MemberRequest struct {
+	Weight int `json:"weight,omitempty"`
+	Amount int `json:"amount,omitempty"`
+}
+
+type membersListResponse struct {
+	Members []Member `json:"members"`
+}
+
+func memberPath(id, userID uuid.UUID) string {
+	return subscriptionPath(id) + "/members/" + userID.String()
+}
+
+// ListMembers возвращает участников общей подписки
+func (c *Client) ListMembers(ctx context.Context, id uuid.UUID) ([]Member, error) {
+	var resp membersListResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionPath(id)+"/members", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Members, nil
+}
+
+// SetMember добавляет участника подписки или меняет его долю
+func (c *Client) SetMember(ctx context.Context, id, userID uuid.UUID, req MemberRequest) (*Member, error) {
+	var member Member
+	if err := c.do(ctx, http.MethodPut, memberPath(id, userID), nil, req, &member); err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// RemoveMember убирает участника из подписки
+func (c *Client) RemoveMember(ctx context.Context, id, userID uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, memberPath(id, userID), nil, nil, nil)
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// Overlap - подписка того же пользователя на тот же сервис, пересекающаяся с записанной.
+// From..To - общие месяцы, пустой To - пересечение не ограничено
+type Overlap struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	ServiceName    string    `json:"service_name"`
+	StartDate      string    `json:"start_date"`
+	EndDate        string    `json:"end_date,omitempty"`
+	From           string    `json:"from"`
+	To             string    `json:"to,omitempty"`
+}
+
+// OverlapPair - пара пересекающихся подписок из OverlapsReport
+type OverlapPair struct {
+	UserID         uuid.UUID `json:"user_id"`
+	ServiceName    string    `json:"service_name"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	OtherID        uuid.UUID `json:"other_id"`
+	From           string    `json:"from"`
+	To             string    `json:"to,omitempty"`
+}
+
+type overlapsReportResponse struct {
+	Overlaps []OverlapPair `json:"overlaps"`
+}
+
+// политики пересечения для query-параметра conflict_policy, см. WithConflictPolicy
+const (
+	ConflictPolicyWarn   = "warn"
+	ConflictPolicyReject = "reject"
+)
+
+// OverlapsReport возвращает пары пересекающихся подписок, с userID - только подписки этого владельца
+func (c *Client) OverlapsReport(ctx context.Context, userID uuid.UUID) ([]OverlapPair, error) {
+	query := url.Values{}
+	if userID != uuid.Nil {
+		query.Set("user_id", userID.String())
+	}
+
+	var resp overlapsReportResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionsPath+"/overlaps", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Overlaps, nil
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pause - пауза подписки по месяц EndMonth включительно, пустой EndMonth - без срока
+type Pause struct {
+	ID         uuid.UUID `json:"id"`
+	StartMonth string    `json:"start_month"`
+	EndMonth   string    `json:"end_month,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// PauseRequest - пауза с месяца From (по умолчанию текущий) по Until включительно
+type PauseRequest struct {
+	From  string `json:"from,omitempty"`
+	Until string `json:"until,omitempty"`
+}
+
+// ResumeRequest - возобновление с месяца From, по умолчанию текущего
+type ResumeRequest struct {
+	From string `json:"from,omitempty"`
+}
+
+type pausesListResponse struct {
+	Pauses []Pause `json:"pauses"`
+}
+
+// PauseSubscription ставит подписку на паузу и возвращает все ее паузы
+func (c *Client) PauseSubscription(ctx context.Context, id uuid.UUID, req PauseRequest) ([]Pause, error) {
+	var resp pausesListResponse
+	if err := c.do(ctx, http.MethodPost, subscriptionPath(id)+"/pause", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Pauses, nil
+}
+
+// ResumeSubscription возобновляет подписку и возвращает все ее паузы
+func (c *Client) ResumeSubscription(ctx context.Context, id uuid.UUID, req ResumeRequest) ([]Pause, error) {
+	var resp pausesListResponse
+	if err := c.do(ctx, http.MethodPost, subscriptionPath(id)+"/resume", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Pauses, nil
+}
+
+// ListPauses возвращает паузы подписки по возрастанию start_month
+func (c *Client) ListPauses(ctx context.Context, id uuid.UUID) ([]Pause, error) {
+	var resp pausesListResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionPath(id)+"/pauses", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Pauses, nil
+}
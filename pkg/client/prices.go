@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionPrice - цена подписки с месяца EffectiveFrom
+type SubscriptionPrice struct {
+	EffectiveFrom string    `json:"effective_from"`
+	Price         int       `json:"price"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type pricesListResponse struct {
+	Prices []SubscriptionPrice `json:"prices"`
+}
+
+// ListPrices возвращает историю цены подписки по возрастанию effective_from
+func (c *Client) ListPrices(ctx context.Context, id uuid.UUID) ([]SubscriptionPrice, error) {
+	var resp pricesListResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionPath(id)+"/prices", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Prices, nil
+}
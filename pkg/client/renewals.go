@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Renewal - автопродление подписки с PreviousEndDate до NewEndDate
+type Renewal struct {
+	ID              uuid.UUID `json:"id"`
+	SubscriptionID  uuid.UUID `json:"subscription_id"`
+	UserID          uuid.UUID `json:"user_id"`
+	ServiceName     string    `json:"service_name"`
+	Price           int       `json:"price"`
+	PreviousEndDate string    `json:"previous_end_date"`
+	NewEndDate      string    `json:"new_end_date"`
+	RenewedAt       time.Time `json:"renewed_at"`
+}
+
+type renewalsListResponse struct {
+	Renewals []Renewal `json:"renewals"`
+}
+
+// ListRenewals возвращает историю автопродлений подписки, новые первыми
+func (c *Client) ListRenewals(ctx context.Context, id uuid.UUID) ([]Renewal, error) {
+	var resp renewalsListResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionPath(id)+"/renewals", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Renewals, nil
+}
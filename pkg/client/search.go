@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+type serviceNamesResponse struct {
+	ServiceNames []string `json:"service_names"`
+}
+
+// SuggestServiceNames возвращает названия сервисов подписок, подходящие под filter.Query (обязателен),
+// лучшие совпадения первыми. limit 0 - значение сервера по умолчанию
+func (c *Client) SuggestServiceNames(ctx context.Context, filter ListFilter, limit int) ([]string, error) {
+	query := listQuery(filter)
+	query.Set("q", filter.Query)
+	if limit != 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var resp serviceNamesResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionsPath+"/suggest", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.ServiceNames, nil
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const servicesPath = "/api/v1/services"
+
+// Service - сервис каталога, Aliases - другие названия, по которым он находится
+type Service struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	Aliases         []string  `json:"aliases"`
+	Category        string    `json:"category,omitempty"`
+	Website         string    `json:"website,omitempty"`
+	DefaultCurrency string    `json:"default_currency"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type ServiceCreateRequest struct {
+	Name            string   `json:"name"`
+	Aliases         []string `json:"aliases,omitempty"`
+	Category        string   `json:"category,omitempty"`
+	Website         string   `json:"website,omitempty"`
+	DefaultCurrency string   `json:"default_currency,omitempty"`
+}
+
+// ServiceUpdateRequest - изменение сервиса, пустые поля не меняются
+type ServiceUpdateRequest struct {
+	Name            string   `json:"name,omitempty"`
+	Aliases         []string `json:"aliases,omitempty"`
+	Category        string   `json:"category,omitempty"`
+	Website         string   `json:"website,omitempty"`
+	DefaultCurrency string   `json:"default_currency,omitempty"`
+}
+
+type servicesListResponse struct {
+	Services []Service `json:"services"`
+}
+
+func servicePath(id uuid.UUID) string {
+	return servicesPath + "/" + id.String()
+}
+
+func (c *Client) CreateService(ctx context.Context, req ServiceCreateRequest) (*Service, error) {
+	var svc Service
+	if err := c.do(ctx, http.MethodPost, servicesPath, nil, req, &svc); err != nil {
+		return nil, err
+	}
+
+	return &svc, nil
+}
+
+func (c *Client) GetService(ctx context.Context, id uuid.UUID) (*Service, error) {
+	var svc Service
+	if err := c.do(ctx, http.MethodGet, servicePath(id), nil, nil, &svc); err != nil {
+		return nil, err
+	}
+
+	return &svc, nil
+}
+
+func (c *Client) ListServices(ctx context.Context) ([]Service, error) {
+	var resp servicesListResponse
+	if err := c.do(ctx, http.MethodGet, servicesPath, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Services, nil
+}
+
+func (c *Client) UpdateService(ctx context.Context, id uuid.UUID, req ServiceUpdateRequest) (*Service, error) {
+	var svc Service
+	if err := c.do(ctx, http.MethodPatch, servicePath(id), nil, req, &svc); err != nil {
+		return nil, err
+	}
+
+	return &svc, nil
+}
+
+func (c *Client) DeleteService(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, servicePath(id), nil, nil, nil)
+}
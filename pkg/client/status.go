@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// CancelRequest - отмена с месяца EffectiveDate (по умолчанию текущего) с причиной Reason
+type CancelRequest struct {
+	Reason        string `json:"reason,omitempty"`
+	EffectiveDate string `json:"effective_date,omitempty"`
+}
+
+// ReactivateRequest - новый месяц окончания; обязателен, если прежний end_date уже прошел
+type ReactivateRequest struct {
+	EndDate string `json:"end_date,omitempty"`
+}
+
+// статусы подписки для ListFilter.Statuses
+const (
+	StatusTrial     = "trial"
+	StatusActive    = "active"
+	StatusPaused    = "paused"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+// CancelSubscription отменяет подписку, она действует по месяц req.EffectiveDate включительно
+func (c *Client) CancelSubscription(ctx context.Context, id uuid.UUID, req CancelRequest) (*Subscription, error) {
+	var sub Subscription
+	if err := c.do(ctx, http.MethodPost, subscriptionPath(id)+"/cancel", nil, req, &sub); err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+// ReactivateSubscription возвращает отмененную или истекшую подписку
+func (c *Client) ReactivateSubscription(ctx context.Context, id uuid.UUID, req ReactivateRequest) (*Subscription, error) {
+	var sub Subscription
+	if err := c.do(ctx, http.MethodPost, subscriptionPath(id)+"/reactivate", nil, req, &sub); err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
@@ -0,0 +1,295 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const subscriptionsPath = "/api/v1/subscriptions"
+
+// Типы запросов и ответов повторяют JSON API и не зависят от пакетов сервера
+
+// Subscription - подписка. Даты - месяцы YYYY-MM, Conflicts - пересечения, найденные при записи
+type Subscription struct {
+	ID            uuid.UUID  `json:"id"`
+	ServiceName   string     `json:"service_name"`
+	Price         int        `json:"price"`
+	UserID        uuid.UUID  `json:"user_id"`
+	StartDate     string     `json:"start_date"`
+	EndDate       string     `json:"end_date"`
+	ServiceID     *uuid.UUID `json:"service_id,omitempty"`
+	CategoryID    *uuid.UUID `json:"category_id,omitempty"`
+	Tags          []string   `json:"tags"`
+	AutoRenew     bool       `json:"auto_renew"`
+	RenewalMonths int        `json:"renewal_months"`
+	TrialEndsAt   string     `json:"trial_ends_at,omitempty"`
+	IntroPrice    int        `json:"intro_price,omitempty"`
+	IntroMonths   int        `json:"intro_months,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	CancelReason  string     `json:"cancel_reason,omitempty"`
+	CancelledAt   *time.Time `json:"cancelled_at,omitempty"`
+	Conflicts     []Overlap  `json:"conflicts,omitempty"`
+}
+
+// SubscriptionCreateRequest - новая подписка или полная замена (PUT).
+// Нужны ServiceName или ServiceID; EndDate nil - start_date + 1 год
+type SubscriptionCreateRequest struct {
+	ServiceName   string     `json:"service_name"`
+	ServiceID     *uuid.UUID `json:"service_id,omitempty"`
+	Price         int        `json:"price"`
+	UserID        *uuid.UUID `json:"user_id"`
+	StartDate     string     `json:"start_date"`
+	EndDate       *string    `json:"end_date,omitempty"`
+	CategoryID    *uuid.UUID `json:"category_id,omitempty"`
+	Tags          []string   `json:"tags,omitempty"`
+	AutoRenew     bool       `json:"auto_renew,omitempty"`
+	RenewalMonths int        `json:"renewal_months,omitempty"`
+	TrialEndsAt   *string    `json:"trial_ends_at,omitempty"`
+	IntroPrice    int        `json:"intro_price,omitempty"`
+	IntroMonths   int        `json:"intro_months,omitempty"`
+}
+
+// UpdateSubscriptionRequest - изменение подписки (PATCH), пустые поля не меняются.
+// PriceEffectiveFrom - месяц, с которого действует новая Price, по умолчанию текущий
+type UpdateSubscriptionRequest struct {
+	ServiceName        string     `json:"service_name,omitempty"`
+	Price              int        `json:"price,omitempty"`
+	StartDate          string     `json:"start_date,omitempty"`
+	EndDate            string     `json:"end_date,omitempty"`
+	ServiceID          *uuid.UUID `json:"service_id,omitempty"`
+	CategoryID         *uuid.UUID `json:"category_id,omitempty"`
+	AutoRenew          *bool      `json:"auto_renew,omitempty"`
+	RenewalMonths      int        `json:"renewal_months,omitempty"`
+	PriceEffectiveFrom string     `json:"price_effective_from,omitempty"`
+	TrialEndsAt        *string    `json:"trial_ends_at,omitempty"`
+	IntroPrice         *int       `json:"intro_price,omitempty"`
+	IntroMonths        *int       `json:"intro_months,omitempty"`
+}
+
+// ParamError - ошибка одного параметра фильтра, Position - позиция в выражении filter
+type ParamError struct {
+	Param    string `json:"param"`
+	Message  string `json:"error"`
+	Position int    `json:"position,omitempty"`
+}
+
+// TotalCostResponse - стоимость подписок за период, Groups - по категориям
+type TotalCostResponse struct {
+	Total  int         `json:"total"`
+	From   string      `json:"from"`
+	To     string      `json:"to"`
+	Groups []CostGroup `json:"groups,omitempty"`
+}
+
+// CostGroup - стоимость подписок категории, без CategoryID - подписки без категории
+type CostGroup struct {
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	Name       string     `json:"name"`
+	Total      int        `json:"total"`
+}
+
+type subscriptionsListResponse struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	NextCursor    string         `json:"next_cursor,omitempty"`
+}
+
+// GroupByCategory - значение TotalFilter.GroupBy для сумм по категориям
+const GroupByCategory = "category"
+
+// ListFilter - фильтры списка подписок, пустые поля не применяются
+type ListFilter struct {
+	UserID      uuid.UUID
+	ServiceName string
+	// CategoryID включает и дочерние категории
+	CategoryID uuid.UUID
+	// Tags - подписка должна иметь все перечисленные теги
+	Tags []string
+	// Statuses - подписка должна иметь один из перечисленных статусов
+	Statuses []string
+	// Query - поиск по названию сервиса, результаты упорядочены по релевантности
+	Query string
+	// ServiceNames - подписка на любой из сервисов, вместе с ServiceName
+	ServiceNames []string
+	// StartFrom, StartTo, EndFrom, EndTo, ActiveAt - месяцы YYYY-MM, границы включительны
+	StartFrom string
+	StartTo   string
+	EndFrom   string
+	EndTo     string
+	ActiveAt  string
+	// PriceMin, PriceMax - границы цены, nil не применяется
+	PriceMin *int
+	PriceMax *int
+	// Expr - выражение фильтра, например price > 500 and active_at("2025-06")
+	Expr string
+	// AsOf - список на момент в прошлом, только для ListSubscriptions
+	AsOf time.Time
+}
+
+// TotalFilter - период (YYYY-MM, включительно) и фильтры для подсчета стоимости
+type TotalFilter struct {
+	From        string
+	To          string
+	UserID      uuid.UUID
+	ServiceName string
+	CategoryID  uuid.UUID
+	Tags        []string
+	Statuses    []string
+	// GroupBy - пустая строка или GroupByCategory
+	GroupBy string
+}
+
+func subscriptionPath(id uuid.UUID) string {
+	return subscriptionsPath + "/" + id.String()
+}
+
+// setFilter дописывает в query общие фильтры списка и подсчета стоимости
+func setFilter(query url.Values, userID uuid.UUID, serviceName string, categoryID uuid.UUID, tags, statuses []string) {
+	if userID != uuid.Nil {
+		query.Set("user_id", userID.String())
+	}
+	if serviceName != "" {
+		query.Set("service_name", serviceName)
+	}
+	if categoryID != uuid.Nil {
+		query.Set("category_id", categoryID.String())
+	}
+	for _, tag := range tags {
+		query.Add("tag", tag)
+	}
+	for _, status := range statuses {
+		query.Add("status", status)
+	}
+}
+
+// writeQuery - query-параметры запросов, записывающих подписку
+func (c *Client) writeQuery() url.Values {
+	if c.conflictPolicy == "" {
+		return nil
+	}
+
+	return url.Values{"conflict_policy": {c.conflictPolicy}}
+}
+
+// CreateSubscription создает подписку. Пересекающиеся подписки на тот же сервис возвращаются в Conflicts
+func (c *Client) CreateSubscription(ctx context.Context, req SubscriptionCreateRequest) (*Subscription, error) {
+	var sub Subscription
+	if err := c.do(ctx, http.MethodPost, subscriptionsPath, c.writeQuery(), req, &sub); err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+func (c *Client) GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	var sub Subscription
+	if err := c.do(ctx, http.MethodGet, subscriptionPath(id), nil, nil, &sub); err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+// listQuery - query-параметры всех фильтров ListFilter
+func listQuery(filter ListFilter) url.Values {
+	query := url.Values{}
+	setFilter(query, filter.UserID, filter.ServiceName, filter.CategoryID, filter.Tags, filter.Statuses)
+	for _, name := range filter.ServiceNames {
+		query.Add("service_name", name)
+	}
+	for param, value := range map[string]string{
+		"q":          filter.Query,
+		"start_from": filter.StartFrom,
+		"start_to":   filter.StartTo,
+		"end_from":   filter.EndFrom,
+		"end_to":     filter.EndTo,
+		"active_at":  filter.ActiveAt,
+		"filter":     filter.Expr,
+	} {
+		if value != "" {
+			query.Set(param, value)
+		}
+	}
+	if filter.PriceMin != nil {
+		query.Set("price_min", strconv.Itoa(*filter.PriceMin))
+	}
+	if filter.PriceMax != nil {
+		query.Set("price_max", strconv.Itoa(*filter.PriceMax))
+	}
+	if !filter.AsOf.IsZero() {
+		query.Set("as_of", filter.AsOf.Format(time.RFC3339Nano))
+	}
+
+	return query
+}
+
+func (c *Client) ListSubscriptions(ctx context.Context, filter ListFilter) ([]Subscription, error) {
+	query := listQuery(filter)
+
+	var resp subscriptionsListResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionsPath, query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Subscriptions, nil
+}
+
+// ListSubscriptionsPage возвращает до limit подписок после курсора after (пустой - первая страница)
+// и курсор следующей страницы; пустой курсор - страниц больше нет. С filter.Query after не передается
+func (c *Client) ListSubscriptionsPage(ctx context.Context, filter ListFilter, limit int, after string) ([]Subscription, string, error) {
+	query := listQuery(filter)
+	query.Set("limit", strconv.Itoa(limit))
+	if after != "" {
+		query.Set("after", after)
+	}
+
+	var resp subscriptionsListResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionsPath, query, nil, &resp); err != nil {
+		return nil, "", err
+	}
+
+	return resp.Subscriptions, resp.NextCursor, nil
+}
+
+// ReplaceSubscription полностью перезаписывает подписку (PUT)
+func (c *Client) ReplaceSubscription(ctx context.Context, id uuid.UUID, req SubscriptionCreateRequest) (*Subscription, error) {
+	var sub Subscription
+	if err := c.do(ctx, http.MethodPut, subscriptionPath(id), c.writeQuery(), req, &sub); err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+// UpdateSubscription меняет только непустые поля req (PATCH)
+func (c *Client) UpdateSubscription(ctx context.Context, id uuid.UUID, req UpdateSubscriptionRequest) (*Subscription, error) {
+	var sub Subscription
+	if err := c.do(ctx, http.MethodPatch, subscriptionPath(id), c.writeQuery(), req, &sub); err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+func (c *Client) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, subscriptionPath(id), nil, nil, nil)
+}
+
+func (c *Client) TotalCost(ctx context.Context, filter TotalFilter) (*TotalCostResponse, error) {
+	query := url.Values{"from": {filter.From}, "to": {filter.To}}
+	setFilter(query, filter.UserID, filter.ServiceName, filter.CategoryID, filter.Tags, filter.Statuses)
+	if filter.GroupBy != "" {
+		query.Set("group_by", filter.GroupBy)
+	}
+
+	var resp TotalCostResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionsPath+"/total", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
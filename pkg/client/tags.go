@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+const tagsPath = "/api/v1/tags"
+
+// Tag - тег и число подписок с ним
+type Tag struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Subscriptions int       `json:"subscriptions"`
+}
+
+type tagRequest struct {
+	Name string `json:"name"`
+}
+
+type tagsListResponse struct {
+	Tags []Tag `json:"tags"`
+}
+
+type subscriptionTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+func tagPath(id uuid.UUID) string {
+	return tagsPath + "/" + id.String()
+}
+
+func (c *Client) CreateTag(ctx context.Context, name string) (*Tag, error) {
+	var tag Tag
+	if err := c.do(ctx, http.MethodPost, tagsPath, nil, tagRequest{Name: name}, &tag); err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// ListTags возвращает все теги с числом подписок у каждого
+func (c *Client) ListTags(ctx context.Context) ([]Tag, error) {
+	var resp tagsListResponse
+	if err := c.do(ctx, http.MethodGet, tagsPath, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Tags, nil
+}
+
+func (c *Client) RenameTag(ctx context.Context, id uuid.UUID, name string) (*Tag, error) {
+	var tag Tag
+	if err := c.do(ctx, http.MethodPatch, tagPath(id), nil, tagRequest{Name: name}, &tag); err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (c *Client) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, tagPath(id), nil, nil, nil)
+}
+
+// SetSubscriptionTags заменяет теги подписки целиком, пустой набор снимает все теги
+func (c *Client) SetSubscriptionTags(ctx context.Context, id uuid.UUID, tags []string) (*Subscription, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+
+	var sub Subscription
+	if err := c.do(ctx, http.MethodPut, subscriptionPath(id)+"/tags", nil, subscriptionTagsRequest{Tags: tags}, &sub); err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+func (c *Client) AddSubscriptionTag(ctx context.Context, id uuid.UUID, tag string) (*Subscription, error) {
+	var sub Subscription
+	if err := c.do(ctx, http.MethodPost, subscriptionPath(id)+"/tags", nil, tagRequest{Name: tag}, &sub); err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+func (c *Client) RemoveSubscriptionTag(ctx context.Context, id uuid.UUID, tag string) error {
+	return c.do(ctx, http.MethodDelete, subscriptionPath(id)+"/tags/"+url.PathEscape(tag), nil, nil, nil)
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// TimeSeriesResponse - стоимость и число действующих подписок по месяцам From..To
+type TimeSeriesResponse struct {
+	From    string            `json:"from"`
+	To      string            `json:"to"`
+	GroupBy string            `json:"group_by,omitempty"`
+	Points  []TimeSeriesPoint `json:"points"`
+}
+
+// TimeSeriesPoint - месяц ряда, Groups - разбивка по GroupBy
+type TimeSeriesPoint struct {
+	Month  string            `json:"month"`
+	Total  int               `json:"total"`
+	Active int               `json:"active"`
+	Groups []TimeSeriesGroup `json:"groups,omitempty"`
+}
+
+// TimeSeriesGroup - группа месяца ряда, Key - ID сервиса, категории или пользователя
+type TimeSeriesGroup struct {
+	Key    string `json:"key,omitempty"`
+	Name   string `json:"name"`
+	Total  int    `json:"total"`
+	Active int    `json:"active"`
+}
+
+// значения TotalFilter.GroupBy для временного ряда, кроме GroupByCategory
+const (
+	GroupByService = "service"
+	GroupByUser    = "user"
+)
+
+// TimeSeries возвращает стоимость и число действующих подписок по месяцам периода filter.From..filter.To.
+// GroupBy - пустая строка, GroupByService, GroupByCategory или GroupByUser
+func (c *Client) TimeSeries(ctx context.Context, filter TotalFilter) (*TimeSeriesResponse, error) {
+	query := url.Values{"from": {filter.From}, "to": {filter.To}}
+	setFilter(query, filter.UserID, filter.ServiceName, filter.CategoryID, filter.Tags, filter.Statuses)
+	if filter.GroupBy != "" {
+		query.Set("group_by", filter.GroupBy)
+	}
+
+	var resp TimeSeriesResponse
+	if err := c.do(ctx, http.MethodGet, subscriptionsPath+"/timeseries", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const usersPath = "/api/v1/users"
+
+type User struct {
+	ID              uuid.UUID `json:"id"`
+	Email           *string   `json:"email,omitempty"`
+	DisplayName     string    `json:"display_name"`
+	Timezone        string    `json:"timezone"`
+	DefaultCurrency string    `json:"default_currency"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// UserCreateRequest - новый пользователь; пустые Timezone и DefaultCurrency - UTC и RUB
+type UserCreateRequest struct {
+	Email           string `json:"email"`
+	DisplayName     string `json:"display_name,omitempty"`
+	Timezone        string `json:"timezone,omitempty"`
+	DefaultCurrency string `json:"default_currency,omitempty"`
+}
+
+// UserUpdateRequest - изменение пользователя, пустые поля не меняются
+type UserUpdateRequest struct {
+	Email           string `json:"email,omitempty"`
+	DisplayName     string `json:"display_name,omitempty"`
+	Timezone        string `json:"timezone,omitempty"`
+	DefaultCurrency string `json:"default_currency,omitempty"`
+}
+
+type usersListResponse struct {
+	Users []User `json:"users"`
+}
+
+func userPath(id uuid.UUID) string {
+	return usersPath + "/" + id.String()
+}
+
+func (c *Client) CreateUser(ctx context.Context, req UserCreateRequest) (*User, error) {
+	var user User
+	if err := c.do(ctx, http.MethodPost, usersPath, nil, req, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (c *Client) GetUser(ctx context.Context, id uuid.UUID) (*User, error) {
+	var user User
+	if err := c.do(ctx, http.MethodGet, userPath(id), nil, nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
+	var resp usersListResponse
+	if err := c.do(ctx, http.MethodGet, usersPath, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Users, nil
+}
+
+func (c *Client) UpdateUser(ctx context.Context, id uuid.UUID, req UserUpdateRequest) (*User, error) {
+	var user User
+	if err := c.do(ctx, http.MethodPatch, userPath(id), nil, req, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (c *Client) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, userPath(id), nil, nil, nil)
+}
@@ -5,5 +5,60 @@ import "errors"
 
 var ( 
 	ErrNotFound = errors.New("subscription not found")
+	ErrInvalidRequest = errors.New("invalid request")
 	ErrInvalidDateRange = errors.New("end_date can not be earlier than start_date")
+	ErrInvalidPeriod = errors.New("to can not be earlier than from")
+	ErrPeriodTooLong = errors.New("period must not exceed 240 months")
+	ErrInvalidHorizon = errors.New("months must be between 1 and 120")
+	ErrInvalidRenewalMode = errors.New("renewal must be one of auto, all, none")
+	ErrSearchQueryRequired = errors.New("q is required")
+	ErrInvalidLimit = errors.New("limit must be between 1 and 50")
+	ErrInvalidAsOf = errors.New("as_of must be an RFC 3339 timestamp or YYYY-MM-DD")
+
+	ErrUserNotFound = errors.New("user not found")
+	ErrUserRequired = errors.New("user_id is required")
+	ErrUnknownUser = errors.New("user_id does not reference an existing user")
+	ErrUserExists = errors.New("user with this email already exists")
+	ErrUserHasSubscriptions = errors.New("user still has subscriptions")
+	ErrInvalidTimezone = errors.New("invalid timezone")
+
+	ErrServiceNotFound = errors.New("service not found")
+	ErrServiceRequired = errors.New("service_name or service_id is required")
+	ErrUnknownService = errors.New("service_id does not reference a catalog service")
+	ErrServiceExists = errors.New("service name or alias is already used by another service")
+
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrUnknownCategory = errors.New("category_id does not reference an existing category")
+	ErrCategoryExists = errors.New("category with this name already exists under the same parent")
+	ErrCategoryCycle = errors.New("category can not be moved under itself or its descendant")
+	ErrCategoryHasChildren = errors.New("category still has child categories")
+	ErrInvalidGroupBy = errors.New("unsupported group_by")
+	ErrInvalidMetric = errors.New("metrics must be a list of count, sum_price, avg_price, min_price, max_price without repeats")
+
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagExists = errors.New("tag already exists")
+	ErrInvalidTag = errors.New("tag must be non-empty and at most 64 characters")
+
+	ErrBudgetNotFound = errors.New("budget not found")
+	ErrBudgetExists = errors.New("budget for this user and category already exists")
+
+	ErrInvalidRenewalTerm = errors.New("renewal_months must be between 1 and 120")
+	ErrInvalidTrial = errors.New("trial_ends_at must be between start_date and end_date")
+	ErrInvalidIntro = errors.New("intro_price and intro_months must be positive and set together")
+
+	ErrInvalidPause = errors.New("pause must be within start_date and end_date")
+	ErrPauseOverlap = errors.New("pause overlaps an existing pause")
+	ErrNotPaused = errors.New("subscription is not paused")
+
+	ErrInvalidStatus = errors.New("status must be one of trial, active, paused, cancelled, expired")
+	ErrInvalidTransition = errors.New("subscription status transition is not allowed")
+	ErrInvalidCancelDate = errors.New("effective_date must be between start_date and end_date")
+
+	ErrMemberNotFound = errors.New("member not found")
+	ErrInvalidShare = errors.New("exactly one of weight and amount must be positive")
+
+	ErrSubscriptionOverlap = errors.New("subscription overlaps another subscription to the same service")
+	ErrInvalidConflictPolicy = errors.New("conflict_policy must be warn or reject")
+
+	ErrProjectionDrift = errors.New("subscription does not match its event log, run cmd/projections")
 )